@@ -2,21 +2,33 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	fileexport "kpo-hw-2/internal/application/files/export"
 	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
 	"kpo-hw-2/internal/infrastructure/di/bootstrap"
 	infraexport "kpo-hw-2/internal/infrastructure/files/export"
 	infraimport "kpo-hw-2/internal/infrastructure/files/import"
 )
 
 func main() {
+	importPath := flag.String("import", "", "файл для импорта перед запуском (формат определяется по расширению)")
+	importEncoding := flag.String("import-encoding", "", "кодировка импортируемого CSV: auto, utf-8 или windows-1251")
+	importDelimiter := flag.String("import-delimiter", "", "разделитель полей импортируемого CSV: auto, tab или один символ")
+	queryExpr := flag.String("query", "", "вывести операции по запросу и завершить работу без интерфейса")
+	flag.Parse()
+
 	logFn, closeLog, err := openTimingLogger("logs/timings.log")
 	if err != nil {
 		log.Fatalf("не удалось открыть лог таймингов: %v", err)
@@ -34,17 +46,41 @@ func main() {
 			infraexport.NewJSONExporter(),
 			infraexport.NewCSVExporter(),
 			infraexport.NewYAMLExporter(),
+			infraexport.NewQIFExporter(),
+			infraexport.NewLedgerExporter(),
+			infraexport.NewHledgerExporter(),
+			infraexport.NewBeancountExporter(),
 		},
 		[]fileimport.Importer{
 			infraimport.NewJSONImporter(),
 			infraimport.NewCSVImporter(),
 			infraimport.NewYAMLImporter(),
+			infraimport.NewStatementImporter(),
+			infraimport.NewOFXImporter(),
+			infraimport.NewQIFImporter(),
+			infraimport.NewJournalImporter(),
 		},
+		infraimport.NewProfileStore("storage/profiles"),
+		infraexport.NewReportWriter(),
 	)
 	if err != nil {
 		log.Fatalf("не удалось инициализировать приложение: %v", err)
 	}
 
+	if *importPath != "" {
+		dialect := fileimport.Dialect{Encoding: *importEncoding, Delimiter: *importDelimiter}
+		if err := importFile(app, *importPath, dialect); err != nil {
+			log.Fatalf("не удалось импортировать %s: %v", *importPath, err)
+		}
+	}
+
+	if *queryExpr != "" {
+		if err := runQuery(app, *queryExpr, os.Stdout); err != nil {
+			log.Fatalf("не удалось выполнить запрос: %v", err)
+		}
+		return
+	}
+
 	if err := runProgram(app.Model); err != nil {
 		log.Fatalf("не удалось запустить интерфейс: %v", err)
 	}
@@ -56,6 +92,63 @@ func runProgram(model tea.Model) error {
 	return err
 }
 
+func importFile(app *bootstrap.App, path string, dialect fileimport.Dialect) error {
+	formatKey := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if formatKey == "yml" {
+		formatKey = "yaml"
+	}
+
+	options := fileimport.DefaultOptions()
+	options.Dialect = dialect
+	_, err := app.Import.ImportFromPath(formatKey, path, options).Execute(context.Background())
+	return err
+}
+
+func runQuery(app *bootstrap.App, expression string, out io.Writer) error {
+	ctx := context.Background()
+
+	operations, err := app.Operations.Query(expression).Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := app.Accounts.List().Execute(ctx)
+	if err != nil {
+		return err
+	}
+	accountNames := make(map[domain.ID]string, len(accounts))
+	for _, account := range accounts {
+		accountNames[account.ID()] = account.Name()
+	}
+
+	categories, err := app.Categories.List("").Execute(ctx)
+	if err != nil {
+		return err
+	}
+	categoryNames := make(map[domain.ID]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID()] = category.Name()
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, op := range operations {
+		amount := op.Amount()
+		if op.Type() == domain.OperationTypeExpense {
+			amount = -amount
+		}
+		fmt.Fprintf(
+			writer,
+			"%s\t%+d\t%s\t%s\t%s\n",
+			op.Date().Format("2006-01-02"),
+			amount,
+			accountNames[op.BankAccountID()],
+			categoryNames[op.CategoryID()],
+			op.Description(),
+		)
+	}
+	return writer.Flush()
+}
+
 func openTimingLogger(path string) (func(name string, duration time.Duration, err error), func() error, error) {
 	if dir := filepath.Dir(path); dir != "" && dir != "." {
 		if err := os.MkdirAll(dir, 0o755); err != nil {
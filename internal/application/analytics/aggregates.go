@@ -0,0 +1,44 @@
+package analytics
+
+import (
+	"fmt"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+func (service) TotalsFromAggregates(cells []repository.AggregateCell) (Totals, error) {
+	var totals Totals
+
+	for _, cell := range cells {
+		switch cell.Type {
+		case domain.OperationTypeIncome:
+			totals.Income += cell.Amount
+		case domain.OperationTypeExpense:
+			totals.Expense += cell.Amount
+		default:
+			return Totals{}, fmt.Errorf("analytics: unsupported operation type %q", cell.Type)
+		}
+	}
+
+	totals.Delta = totals.Income - totals.Expense
+	return totals, nil
+}
+
+func operationCells(operations []*domain.Operation) []repository.AggregateCell {
+	cells := make([]repository.AggregateCell, 0, len(operations))
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		cells = append(cells, repository.AggregateCell{
+			AccountID:  op.BankAccountID(),
+			CategoryID: op.CategoryID(),
+			Type:       op.Type(),
+			Day:        op.Date(),
+			Amount:     op.Amount(),
+			Count:      1,
+		})
+	}
+	return cells
+}
@@ -0,0 +1,217 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+const (
+	defaultAnomalyLookback  = 12
+	defaultAnomalyThreshold = 3.5
+	madScale                = 0.6745
+	flatBaselineRatio       = 3
+	minOperationSamples     = 5
+	minMonthSamples         = 3
+)
+
+type AnomalyKind string
+
+const (
+	AnomalyOperation     AnomalyKind = "operation"
+	AnomalyCategoryMonth AnomalyKind = "category_month"
+)
+
+type AnomalyOptions struct {
+	Now            time.Time
+	LookbackMonths int
+	Threshold      float64
+}
+
+func (o AnomalyOptions) normalized() AnomalyOptions {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	if o.LookbackMonths <= 0 {
+		o.LookbackMonths = defaultAnomalyLookback
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = defaultAnomalyThreshold
+	}
+	return o
+}
+
+func (o AnomalyOptions) WindowStart() time.Time {
+	o = o.normalized()
+	return time.Date(o.Now.Year(), o.Now.Month(), 1, 0, 0, 0, 0, o.Now.Location()).AddDate(0, -o.LookbackMonths+1, 0)
+}
+
+type Anomaly struct {
+	Kind        AnomalyKind
+	CategoryID  domain.ID
+	AccountID   domain.ID
+	OperationID domain.ID
+	Description string
+	Date        time.Time
+	Amount      int64
+	Median      float64
+	MAD         float64
+	Score       float64
+	Ratio       float64
+	Samples     int
+}
+
+func (service) Anomalies(operations []*domain.Operation, options AnomalyOptions) ([]Anomaly, error) {
+	options = options.normalized()
+	from := options.WindowStart()
+
+	byCategory := make(map[domain.ID][]*domain.Operation)
+	for _, op := range operations {
+		if op == nil || op.Type() != domain.OperationTypeExpense {
+			continue
+		}
+		if op.Date().Before(from) || op.Date().After(options.Now) {
+			continue
+		}
+		byCategory[op.CategoryID()] = append(byCategory[op.CategoryID()], op)
+	}
+
+	var anomalies []Anomaly
+	for categoryID, ops := range byCategory {
+		anomalies = append(anomalies, operationAnomalies(categoryID, ops, options.Threshold)...)
+		anomalies = append(anomalies, monthAnomalies(categoryID, ops, from, options)...)
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].Score == anomalies[j].Score {
+			if anomalies[i].Date.Equal(anomalies[j].Date) {
+				return anomalies[i].OperationID < anomalies[j].OperationID
+			}
+			return anomalies[i].Date.After(anomalies[j].Date)
+		}
+		return anomalies[i].Score > anomalies[j].Score
+	})
+
+	return anomalies, nil
+}
+
+func operationAnomalies(categoryID domain.ID, ops []*domain.Operation, threshold float64) []Anomaly {
+	if len(ops) < minOperationSamples {
+		return nil
+	}
+
+	amounts := make([]float64, 0, len(ops))
+	for _, op := range ops {
+		amounts = append(amounts, float64(op.Amount()))
+	}
+	center, mad := medianAbsoluteDeviation(amounts)
+
+	var anomalies []Anomaly
+	for _, op := range ops {
+		score, ratio, ok := robustScore(float64(op.Amount()), center, mad, threshold)
+		if !ok {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			Kind:        AnomalyOperation,
+			CategoryID:  categoryID,
+			AccountID:   op.BankAccountID(),
+			OperationID: op.ID(),
+			Description: op.Description(),
+			Date:        op.Date(),
+			Amount:      op.Amount(),
+			Median:      center,
+			MAD:         mad,
+			Score:       score,
+			Ratio:       ratio,
+			Samples:     len(amounts),
+		})
+	}
+	return anomalies
+}
+
+func monthAnomalies(categoryID domain.ID, ops []*domain.Operation, from time.Time, options AnomalyOptions) []Anomaly {
+	loc := options.Now.Location()
+	totals := make(map[int64]int64)
+	first := options.Now
+	for _, op := range ops {
+		month := bucketStart(op.Date(), GranularityMonth, time.Monday, loc)
+		totals[month.Unix()] += op.Amount()
+		if month.Before(first) {
+			first = month
+		}
+	}
+	if first.Before(from) {
+		first = from
+	}
+
+	var months []time.Time
+	var values []float64
+	for month := first; !month.After(options.Now); month = month.AddDate(0, 1, 0) {
+		months = append(months, month)
+		values = append(values, float64(totals[month.Unix()]))
+	}
+	if len(values) < minMonthSamples+1 {
+		return nil
+	}
+
+	var anomalies []Anomaly
+	others := make([]float64, 0, len(values)-1)
+	for i, value := range values {
+		others = append(others[:0], values[:i]...)
+		others = append(others, values[i+1:]...)
+
+		center, mad := medianAbsoluteDeviation(others)
+		score, ratio, ok := robustScore(value, center, mad, options.Threshold)
+		if !ok {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			Kind:       AnomalyCategoryMonth,
+			CategoryID: categoryID,
+			Date:       months[i],
+			Amount:     int64(value),
+			Median:     center,
+			MAD:        mad,
+			Score:      score,
+			Ratio:      ratio,
+			Samples:    len(others),
+		})
+	}
+	return anomalies
+}
+
+func robustScore(value, center, mad, threshold float64) (float64, float64, bool) {
+	if value <= center {
+		return 0, 0, false
+	}
+
+	ratio := math.Inf(1)
+	if center > 0 {
+		ratio = value / center
+	}
+
+	if mad == 0 {
+		if ratio < flatBaselineRatio {
+			return 0, 0, false
+		}
+		return ratio, ratio, true
+	}
+
+	score := madScale * (value - center) / mad
+	if score < threshold {
+		return 0, 0, false
+	}
+	return score, ratio, true
+}
+
+func medianAbsoluteDeviation(values []float64) (float64, float64) {
+	center := median(values)
+	deviations := make([]float64, 0, len(values))
+	for _, value := range values {
+		deviations = append(deviations, math.Abs(value-center))
+	}
+	return center, median(deviations)
+}
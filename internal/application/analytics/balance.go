@@ -0,0 +1,123 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type StatementLine struct {
+	Operation *domain.Operation
+	Balance   int64
+}
+
+type Statement struct {
+	Opening int64
+	Closing int64
+	Lines   []StatementLine
+}
+
+type BalancePoint struct {
+	Date    time.Time
+	Balance int64
+}
+
+func (service) Statement(current int64, operations []*domain.Operation) (Statement, error) {
+	return buildStatement(current, operations)
+}
+
+func (service) BalanceAsOf(current int64, operations []*domain.Operation, at time.Time) (int64, error) {
+	balance := current
+	for _, op := range operations {
+		if op == nil || !op.Date().After(at) {
+			continue
+		}
+		amount, err := signedAmount(op)
+		if err != nil {
+			return 0, err
+		}
+		balance -= amount
+	}
+	return balance, nil
+}
+
+func (service) BalanceSeries(current int64, operations []*domain.Operation, from, to time.Time) ([]BalancePoint, error) {
+	statement, err := buildStatement(current, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	if to.Before(start) {
+		return nil, nil
+	}
+
+	var points []BalancePoint
+	balance := statement.Opening
+	next := 0
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if len(points) >= maxSeriesBuckets {
+			return nil, ErrTooManyBuckets
+		}
+		end := day.AddDate(0, 0, 1)
+		for next < len(statement.Lines) && statement.Lines[next].Operation.Date().Before(end) {
+			balance = statement.Lines[next].Balance
+			next++
+		}
+		points = append(points, BalancePoint{Date: day, Balance: balance})
+	}
+
+	return points, nil
+}
+
+func buildStatement(current int64, operations []*domain.Operation) (Statement, error) {
+	sorted := make([]*domain.Operation, 0, len(operations))
+	var total int64
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		amount, err := signedAmount(op)
+		if err != nil {
+			return Statement{}, err
+		}
+		total += amount
+		sorted = append(sorted, op)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		di, dj := sorted[i].Date(), sorted[j].Date()
+		if di.Equal(dj) {
+			return sorted[i].ID() < sorted[j].ID()
+		}
+		return di.Before(dj)
+	})
+
+	statement := Statement{
+		Opening: current - total,
+		Closing: current,
+		Lines:   make([]StatementLine, 0, len(sorted)),
+	}
+
+	balance := statement.Opening
+	for _, op := range sorted {
+		amount, _ := signedAmount(op)
+		balance += amount
+		statement.Lines = append(statement.Lines, StatementLine{Operation: op, Balance: balance})
+	}
+
+	return statement, nil
+}
+
+func signedAmount(op *domain.Operation) (int64, error) {
+	switch op.Type() {
+	case domain.OperationTypeIncome:
+		return op.Amount(), nil
+	case domain.OperationTypeExpense:
+		return -op.Amount(), nil
+	default:
+		return 0, fmt.Errorf("analytics: unsupported operation type %q", op.Type())
+	}
+}
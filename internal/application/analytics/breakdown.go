@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type Dimension string
+
+const (
+	ByCategory Dimension = "category"
+	ByAccount  Dimension = "account"
+)
+
+type BreakdownEntry struct {
+	Key    domain.ID
+	Amount int64
+	Count  int
+	Share  float64
+}
+
+type Breakdown struct {
+	Dimension Dimension
+	Totals    Totals
+	Income    []BreakdownEntry
+	Expense   []BreakdownEntry
+}
+
+func (s service) Breakdown(operations []*domain.Operation, dimension Dimension) (Breakdown, error) {
+	return s.BreakdownFromAggregates(operationCells(operations), dimension)
+}
+
+func (service) BreakdownFromAggregates(cells []repository.AggregateCell, dimension Dimension) (Breakdown, error) {
+	var keyOf func(repository.AggregateCell) domain.ID
+	switch dimension {
+	case ByCategory:
+		keyOf = func(cell repository.AggregateCell) domain.ID { return cell.CategoryID }
+	case ByAccount:
+		keyOf = func(cell repository.AggregateCell) domain.ID { return cell.AccountID }
+	default:
+		return Breakdown{}, fmt.Errorf("analytics: unsupported dimension %q", dimension)
+	}
+
+	income := make(map[domain.ID]*BreakdownEntry)
+	expense := make(map[domain.ID]*BreakdownEntry)
+	result := Breakdown{Dimension: dimension}
+
+	for _, cell := range cells {
+		var bucket map[domain.ID]*BreakdownEntry
+		switch cell.Type {
+		case domain.OperationTypeIncome:
+			bucket = income
+			result.Totals.Income += cell.Amount
+		case domain.OperationTypeExpense:
+			bucket = expense
+			result.Totals.Expense += cell.Amount
+		default:
+			return Breakdown{}, fmt.Errorf("analytics: unsupported operation type %q", cell.Type)
+		}
+
+		key := keyOf(cell)
+		entry, ok := bucket[key]
+		if !ok {
+			entry = &BreakdownEntry{Key: key}
+			bucket[key] = entry
+		}
+		entry.Amount += cell.Amount
+		entry.Count += cell.Count
+	}
+
+	result.Totals.Delta = result.Totals.Income - result.Totals.Expense
+	result.Income = breakdownEntries(income, result.Totals.Income)
+	result.Expense = breakdownEntries(expense, result.Totals.Expense)
+	return result, nil
+}
+
+func breakdownEntries(bucket map[domain.ID]*BreakdownEntry, total int64) []BreakdownEntry {
+	if len(bucket) == 0 {
+		return nil
+	}
+
+	entries := make([]BreakdownEntry, 0, len(bucket))
+	for _, entry := range bucket {
+		if total > 0 {
+			entry.Share = float64(entry.Amount) / float64(total)
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Amount == entries[j].Amount {
+			return entries[i].Key < entries[j].Key
+		}
+		return entries[i].Amount > entries[j].Amount
+	})
+
+	return entries
+}
@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type ComparisonEntry struct {
+	CategoryID domain.ID
+	Type       domain.OperationType
+	Current    int64
+	Previous   int64
+	Change     int64
+	Percent    *float64
+}
+
+type Comparison struct {
+	Current  Totals
+	Previous Totals
+	Entries  []ComparisonEntry
+}
+
+func (service) Compare(current, previous []*domain.Operation) (Comparison, error) {
+	var result Comparison
+	entries := make(map[domain.ID]*ComparisonEntry)
+
+	collect := func(operations []*domain.Operation, totals *Totals, pick func(*ComparisonEntry) *int64) error {
+		for _, op := range operations {
+			if op == nil {
+				continue
+			}
+			switch op.Type() {
+			case domain.OperationTypeIncome:
+				totals.Income += op.Amount()
+			case domain.OperationTypeExpense:
+				totals.Expense += op.Amount()
+			default:
+				return fmt.Errorf("analytics: unsupported operation type %q", op.Type())
+			}
+
+			entry, ok := entries[op.CategoryID()]
+			if !ok {
+				entry = &ComparisonEntry{CategoryID: op.CategoryID(), Type: op.Type()}
+				entries[op.CategoryID()] = entry
+			}
+			*pick(entry) += op.Amount()
+		}
+		totals.Delta = totals.Income - totals.Expense
+		return nil
+	}
+
+	if err := collect(current, &result.Current, func(e *ComparisonEntry) *int64 { return &e.Current }); err != nil {
+		return Comparison{}, err
+	}
+	if err := collect(previous, &result.Previous, func(e *ComparisonEntry) *int64 { return &e.Previous }); err != nil {
+		return Comparison{}, err
+	}
+
+	result.Entries = make([]ComparisonEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Change = entry.Current - entry.Previous
+		if entry.Previous != 0 {
+			percent := float64(entry.Change) / float64(entry.Previous) * 100
+			entry.Percent = &percent
+		}
+		result.Entries = append(result.Entries, *entry)
+	}
+
+	sort.Slice(result.Entries, func(i, j int) bool {
+		if result.Entries[i].Change == result.Entries[j].Change {
+			return result.Entries[i].CategoryID < result.Entries[j].CategoryID
+		}
+		return result.Entries[i].Change > result.Entries[j].Change
+	})
+
+	return result, nil
+}
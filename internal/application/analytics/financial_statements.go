@@ -0,0 +1,282 @@
+package analytics
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+const reportDateLayout = "02.01.2006"
+
+type IncomeStatementLine struct {
+	CategoryID domain.ID
+	Name       string
+	Amount     int64
+	Count      int
+}
+
+type IncomeStatementSection struct {
+	Type     domain.OperationType
+	Lines    []IncomeStatementLine
+	Subtotal int64
+	Count    int
+}
+
+type IncomeStatement struct {
+	From    *time.Time
+	To      *time.Time
+	Income  IncomeStatementSection
+	Expense IncomeStatementSection
+	Net     int64
+}
+
+type BalanceSheetLine struct {
+	AccountID domain.ID
+	Name      string
+	Group     string
+	Balance   int64
+}
+
+type BalanceSheet struct {
+	Date        time.Time
+	Assets      []BalanceSheetLine
+	Credit      []BalanceSheetLine
+	TotalAssets int64
+	TotalCredit int64
+	Net         int64
+}
+
+func (service) IncomeStatement(categories []*domain.Category, operations []*domain.Operation) (IncomeStatement, error) {
+	names := make(map[domain.ID]string, len(categories))
+	for _, category := range categories {
+		if category != nil {
+			names[category.ID()] = category.Name()
+		}
+	}
+
+	lines := map[domain.OperationType]map[domain.ID]*IncomeStatementLine{
+		domain.OperationTypeIncome:  {},
+		domain.OperationTypeExpense: {},
+	}
+
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		if _, err := signedAmount(op); err != nil {
+			return IncomeStatement{}, err
+		}
+
+		line, ok := lines[op.Type()][op.CategoryID()]
+		if !ok {
+			name := names[op.CategoryID()]
+			if name == "" {
+				name = op.CategoryID().String()
+			}
+			line = &IncomeStatementLine{CategoryID: op.CategoryID(), Name: name}
+			lines[op.Type()][op.CategoryID()] = line
+		}
+		line.Amount += op.Amount()
+		line.Count++
+	}
+
+	statement := IncomeStatement{
+		Income:  incomeSection(domain.OperationTypeIncome, lines[domain.OperationTypeIncome]),
+		Expense: incomeSection(domain.OperationTypeExpense, lines[domain.OperationTypeExpense]),
+	}
+	statement.Net = statement.Income.Subtotal - statement.Expense.Subtotal
+
+	return statement, nil
+}
+
+func incomeSection(typ domain.OperationType, lines map[domain.ID]*IncomeStatementLine) IncomeStatementSection {
+	section := IncomeStatementSection{
+		Type:  typ,
+		Lines: make([]IncomeStatementLine, 0, len(lines)),
+	}
+	for _, line := range lines {
+		section.Lines = append(section.Lines, *line)
+		section.Subtotal += line.Amount
+		section.Count += line.Count
+	}
+
+	sort.Slice(section.Lines, func(i, j int) bool {
+		if section.Lines[i].Amount == section.Lines[j].Amount {
+			return section.Lines[i].Name < section.Lines[j].Name
+		}
+		return section.Lines[i].Amount > section.Lines[j].Amount
+	})
+
+	return section
+}
+
+func (service) BalanceSheet(
+	accounts []*domain.BankAccount,
+	profiles []*domain.AccountProfile,
+	operations []*domain.Operation,
+	at time.Time,
+) (BalanceSheet, error) {
+	byAccount := make(map[domain.ID]*domain.AccountProfile, len(profiles))
+	for _, profile := range profiles {
+		if profile != nil {
+			byAccount[profile.AccountID()] = profile
+		}
+	}
+
+	balances := make(map[domain.ID]int64, len(accounts))
+	for _, account := range accounts {
+		if account != nil {
+			balances[account.ID()] = account.Balance()
+		}
+	}
+
+	for _, op := range operations {
+		if op == nil || !op.Date().After(at) {
+			continue
+		}
+		if _, known := balances[op.BankAccountID()]; !known {
+			continue
+		}
+		amount, err := signedAmount(op)
+		if err != nil {
+			return BalanceSheet{}, err
+		}
+		balances[op.BankAccountID()] -= amount
+	}
+
+	sheet := BalanceSheet{Date: at}
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+
+		profile := byAccount[account.ID()]
+		if profile == nil {
+			profile = domain.DefaultAccountProfile(account.ID())
+		}
+
+		line := BalanceSheetLine{
+			AccountID: account.ID(),
+			Name:      account.Name(),
+			Group:     profile.Group(),
+			Balance:   balances[account.ID()],
+		}
+		if profile.Kind() == domain.AccountKindCredit {
+			line.Balance = -line.Balance
+			sheet.Credit = append(sheet.Credit, line)
+			sheet.TotalCredit += line.Balance
+		} else {
+			sheet.Assets = append(sheet.Assets, line)
+			sheet.TotalAssets += line.Balance
+		}
+	}
+	sheet.Net = sheet.TotalAssets - sheet.TotalCredit
+
+	sortBalanceLines(sheet.Assets)
+	sortBalanceLines(sheet.Credit)
+
+	return sheet, nil
+}
+
+func sortBalanceLines(lines []BalanceSheetLine) {
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Group != lines[j].Group {
+			return lines[i].Group < lines[j].Group
+		}
+		return lines[i].Name < lines[j].Name
+	})
+}
+
+func (s IncomeStatement) Table() ReportTable {
+	title := "Отчёт о доходах и расходах"
+	if period := formatReportPeriod(s.From, s.To); period != "" {
+		title += " за " + period
+	}
+
+	table := ReportTable{
+		Title:   title,
+		Keys:    []string{"section", "category", "count", "amount"},
+		Columns: []string{"Раздел", "Категория", "Операций", "Сумма"},
+		Numeric: []bool{false, false, true, true},
+	}
+
+	appendSection := func(label string, section IncomeStatementSection) {
+		for _, line := range section.Lines {
+			table.Rows = append(table.Rows, ReportRow{Cells: []string{
+				label,
+				line.Name,
+				strconv.Itoa(line.Count),
+				strconv.FormatInt(line.Amount, 10),
+			}})
+		}
+		table.Rows = append(table.Rows, ReportRow{Total: true, Cells: []string{
+			label,
+			"Итого",
+			strconv.Itoa(section.Count),
+			strconv.FormatInt(section.Subtotal, 10),
+		}})
+	}
+
+	appendSection("Доходы", s.Income)
+	appendSection("Расходы", s.Expense)
+	table.Rows = append(table.Rows, ReportRow{Total: true, Cells: []string{
+		"Результат",
+		"Доходы минус расходы",
+		strconv.Itoa(s.Income.Count + s.Expense.Count),
+		strconv.FormatInt(s.Net, 10),
+	}})
+
+	return table
+}
+
+func (s BalanceSheet) Table() ReportTable {
+	table := ReportTable{
+		Title:   "Баланс на " + s.Date.Format(reportDateLayout),
+		Keys:    []string{"section", "group", "account", "balance"},
+		Columns: []string{"Раздел", "Группа", "Счёт", "Остаток"},
+		Numeric: []bool{false, false, false, true},
+	}
+
+	appendSection := func(label string, lines []BalanceSheetLine, total int64) {
+		for _, line := range lines {
+			table.Rows = append(table.Rows, ReportRow{Cells: []string{
+				label,
+				line.Group,
+				line.Name,
+				strconv.FormatInt(line.Balance, 10),
+			}})
+		}
+		table.Rows = append(table.Rows, ReportRow{Total: true, Cells: []string{
+			label,
+			"",
+			"Итого",
+			strconv.FormatInt(total, 10),
+		}})
+	}
+
+	appendSection("Активы", s.Assets, s.TotalAssets)
+	appendSection("Кредиты", s.Credit, s.TotalCredit)
+	table.Rows = append(table.Rows, ReportRow{Total: true, Cells: []string{
+		"Чистые активы",
+		"",
+		"Активы минус кредиты",
+		strconv.FormatInt(s.Net, 10),
+	}})
+
+	return table
+}
+
+func formatReportPeriod(from, to *time.Time) string {
+	switch {
+	case from != nil && to != nil:
+		return from.Format(reportDateLayout) + " — " + to.Format(reportDateLayout)
+	case from != nil:
+		return "период с " + from.Format(reportDateLayout)
+	case to != nil:
+		return "период по " + to.Format(reportDateLayout)
+	default:
+		return ""
+	}
+}
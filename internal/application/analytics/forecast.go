@@ -0,0 +1,211 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+const defaultForecastLookback = 3
+
+var ErrInvalidForecast = errors.New("analytics: invalid forecast options")
+
+type ForecastOptions struct {
+	Start          time.Time
+	Months         int
+	Threshold      int64
+	LookbackMonths int
+}
+
+type ForecastPoint struct {
+	Date    time.Time
+	Balance int64
+}
+
+type DiscretionaryRate struct {
+	AccountID  domain.ID
+	CategoryID domain.ID
+	Monthly    int64
+}
+
+type AccountForecast struct {
+	AccountID   domain.ID
+	AccountName string
+	Current     int64
+	Points      []ForecastPoint
+	Lowest      ForecastPoint
+	FirstBelow  *ForecastPoint
+}
+
+type Forecast struct {
+	Start         time.Time
+	End           time.Time
+	Threshold     int64
+	Recurring     []RecurringPattern
+	Discretionary []DiscretionaryRate
+	Accounts      []AccountForecast
+	FirstBelow    *ForecastPoint
+	FirstBelowID  domain.ID
+}
+
+func (service) Forecast(accounts []*domain.BankAccount, operations []*domain.Operation, options ForecastOptions) (Forecast, error) {
+	if options.Months <= 0 || options.Start.IsZero() {
+		return Forecast{}, ErrInvalidForecast
+	}
+	lookback := options.LookbackMonths
+	if lookback <= 0 {
+		lookback = defaultForecastLookback
+	}
+
+	start := time.Date(options.Start.Year(), options.Start.Month(), options.Start.Day(), 0, 0, 0, 0, options.Start.Location())
+	end := start.AddDate(0, options.Months, 0)
+	forecast := Forecast{Start: start, End: end, Threshold: options.Threshold}
+
+	var active []RecurringPattern
+	recurringIDs := make(map[domain.ID]struct{})
+	for _, pattern := range detectRecurring(operations, options.Start) {
+		if !pattern.Active {
+			continue
+		}
+		active = append(active, pattern)
+		for _, id := range pattern.OperationIDs {
+			recurringIDs[id] = struct{}{}
+		}
+	}
+	forecast.Recurring = active
+
+	historyFrom := start.AddDate(0, -lookback, 0)
+	historyDays := start.Sub(historyFrom).Hours() / 24
+	type rateKey struct{ account, category domain.ID }
+	spent := make(map[rateKey]int64)
+	for _, op := range operations {
+		if op == nil || op.Type() != domain.OperationTypeExpense {
+			continue
+		}
+		if _, ok := recurringIDs[op.ID()]; ok {
+			continue
+		}
+		if op.Date().Before(historyFrom) || !op.Date().Before(start) {
+			continue
+		}
+		spent[rateKey{op.BankAccountID(), op.CategoryID()}] += op.Amount()
+	}
+
+	dailyByAccount := make(map[domain.ID]float64)
+	for key, total := range spent {
+		daily := float64(total) / historyDays
+		dailyByAccount[key.account] += daily
+		forecast.Discretionary = append(forecast.Discretionary, DiscretionaryRate{
+			AccountID:  key.account,
+			CategoryID: key.category,
+			Monthly:    int64(math.Round(daily * 30.4)),
+		})
+	}
+	sortDiscretionary(forecast.Discretionary)
+
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+
+		projection := AccountForecast{
+			AccountID:   account.ID(),
+			AccountName: account.Name(),
+			Current:     account.Balance(),
+			Lowest:      ForecastPoint{Date: start, Balance: account.Balance()},
+		}
+
+		changes := make(map[int64]int64)
+		for _, pattern := range active {
+			if pattern.AccountID != account.ID() {
+				continue
+			}
+			amount := pattern.Amount
+			if pattern.Type == domain.OperationTypeExpense {
+				amount = -amount
+			}
+			for date := pattern.Next; date.Before(end); date = pattern.Cadence.Next(date) {
+				when := date.In(start.Location())
+				if when.Before(start) {
+					when = start
+				}
+				changes[dayKey(when)] += amount
+			}
+		}
+
+		daily := dailyByAccount[account.ID()]
+		var scheduled int64
+		days := 0
+		for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+			days++
+			scheduled += changes[dayKey(day)]
+			balance := account.Balance() + scheduled - int64(math.Round(daily*float64(days)))
+
+			point := ForecastPoint{Date: day, Balance: balance}
+			projection.Points = append(projection.Points, point)
+			if balance < projection.Lowest.Balance {
+				projection.Lowest = point
+			}
+			if projection.FirstBelow == nil && balance < options.Threshold {
+				below := point
+				projection.FirstBelow = &below
+			}
+		}
+
+		if projection.FirstBelow != nil && (forecast.FirstBelow == nil || projection.FirstBelow.Date.Before(forecast.FirstBelow.Date)) {
+			forecast.FirstBelow = projection.FirstBelow
+			forecast.FirstBelowID = account.ID()
+		}
+
+		forecast.Accounts = append(forecast.Accounts, projection)
+	}
+
+	return forecast, nil
+}
+
+func (f Forecast) Table() ReportTable {
+	table := ReportTable{
+		Title:   "Прогноз остатков",
+		Keys:    []string{"date", "account_id", "account", "balance", "below_threshold"},
+		Columns: []string{"Дата", "ID счёта", "Счёт", "Остаток", "Ниже порога"},
+		Numeric: []bool{false, false, false, true, false},
+	}
+	if !f.Start.IsZero() && !f.End.IsZero() {
+		table.Title += " с " + f.Start.Format(reportDateLayout) + " по " + f.End.Format(reportDateLayout)
+	}
+
+	for _, account := range f.Accounts {
+		for _, point := range account.Points {
+			table.Rows = append(table.Rows, ReportRow{Cells: []string{
+				point.Date.Format("2006-01-02"),
+				account.AccountID.String(),
+				account.AccountName,
+				strconv.FormatInt(point.Balance, 10),
+				strconv.FormatBool(point.Balance < f.Threshold),
+			}})
+		}
+	}
+
+	return table
+}
+
+func sortDiscretionary(rates []DiscretionaryRate) {
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Monthly == rates[j].Monthly {
+			if rates[i].AccountID == rates[j].AccountID {
+				return rates[i].CategoryID < rates[j].CategoryID
+			}
+			return rates[i].AccountID < rates[j].AccountID
+		}
+		return rates[i].Monthly > rates[j].Monthly
+	})
+}
+
+func dayKey(t time.Time) int64 {
+	year, month, day := t.Date()
+	return int64(year)*10000 + int64(month)*100 + int64(day)
+}
@@ -0,0 +1,207 @@
+package analytics
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+const maxNetWorthMonths = 120
+
+type NetWorthOptions struct {
+	End    time.Time
+	Months int
+}
+
+type NetWorthPoint struct {
+	Date   time.Time
+	Assets int64
+	Credit int64
+	Net    int64
+}
+
+type AccountGroupTotal struct {
+	Key      string
+	Accounts int
+	Assets   int64
+	Credit   int64
+	Net      int64
+}
+
+type NetWorth struct {
+	Assets  int64
+	Credit  int64
+	Net     int64
+	ByKind  []AccountGroupTotal
+	ByGroup []AccountGroupTotal
+	Points  []NetWorthPoint
+}
+
+func (o NetWorthOptions) normalized() NetWorthOptions {
+	if o.End.IsZero() {
+		o.End = time.Now()
+	}
+	if o.Months <= 0 {
+		o.Months = 12
+	}
+	if o.Months > maxNetWorthMonths {
+		o.Months = maxNetWorthMonths
+	}
+	return o
+}
+
+func (service) NetWorth(
+	accounts []*domain.BankAccount,
+	profiles []*domain.AccountProfile,
+	operations []*domain.Operation,
+	options NetWorthOptions,
+) (NetWorth, error) {
+	options = options.normalized()
+
+	kinds := make(map[domain.ID]domain.AccountKind, len(profiles))
+	groups := make(map[domain.ID]string, len(profiles))
+	for _, profile := range profiles {
+		if profile == nil {
+			continue
+		}
+		kinds[profile.AccountID()] = profile.Kind()
+		groups[profile.AccountID()] = profile.Group()
+	}
+
+	var result NetWorth
+	byKind := make(map[string]*AccountGroupTotal)
+	byGroup := make(map[string]*AccountGroupTotal)
+	balances := make(map[domain.ID]int64, len(accounts))
+
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+		kind := kinds[account.ID()]
+		if kind == "" {
+			kind = domain.AccountKindAsset
+		}
+		kinds[account.ID()] = kind
+		balances[account.ID()] = account.Balance()
+
+		addToTotal(byKind, string(kind), kind, account.Balance())
+		addToTotal(byGroup, groups[account.ID()], kind, account.Balance())
+		if kind == domain.AccountKindCredit {
+			result.Credit -= account.Balance()
+		} else {
+			result.Assets += account.Balance()
+		}
+	}
+	result.Net = result.Assets - result.Credit
+	result.ByKind = sortedTotals(byKind)
+	result.ByGroup = sortedTotals(byGroup)
+
+	sorted := make([]*domain.Operation, 0, len(operations))
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		if _, known := balances[op.BankAccountID()]; !known {
+			continue
+		}
+		if _, err := signedAmount(op); err != nil {
+			return NetWorth{}, err
+		}
+		sorted = append(sorted, op)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date().After(sorted[j].Date())
+	})
+
+	checkpoints := monthEnds(options.End, options.Months)
+	result.Points = make([]NetWorthPoint, len(checkpoints))
+
+	assets, credit := result.Assets, result.Credit
+	next := 0
+	for i := len(checkpoints) - 1; i >= 0; i-- {
+		at := checkpoints[i]
+		for next < len(sorted) && sorted[next].Date().After(at) {
+			op := sorted[next]
+			amount, _ := signedAmount(op)
+			if kinds[op.BankAccountID()] == domain.AccountKindCredit {
+				credit += amount
+			} else {
+				assets -= amount
+			}
+			next++
+		}
+		result.Points[i] = NetWorthPoint{
+			Date:   at,
+			Assets: assets,
+			Credit: credit,
+			Net:    assets - credit,
+		}
+	}
+
+	return result, nil
+}
+
+func addToTotal(totals map[string]*AccountGroupTotal, key string, kind domain.AccountKind, balance int64) {
+	total, ok := totals[key]
+	if !ok {
+		total = &AccountGroupTotal{Key: key}
+		totals[key] = total
+	}
+	total.Accounts++
+	if kind == domain.AccountKindCredit {
+		total.Credit -= balance
+		total.Net += balance
+	} else {
+		total.Assets += balance
+		total.Net += balance
+	}
+}
+
+func sortedTotals(totals map[string]*AccountGroupTotal) []AccountGroupTotal {
+	result := make([]AccountGroupTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Key == "") != (result[j].Key == "") {
+			return result[j].Key == ""
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func monthEnds(end time.Time, months int) []time.Time {
+	points := make([]time.Time, 0, months)
+	first := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+	for i := months - 1; i >= 0; i-- {
+		at := first.AddDate(0, -i+1, 0).Add(-time.Nanosecond)
+		if at.After(end) {
+			at = end
+		}
+		points = append(points, at)
+	}
+	return points
+}
+
+func (n NetWorth) Table() ReportTable {
+	table := ReportTable{
+		Title:   "Динамика чистых активов",
+		Keys:    []string{"month", "assets", "credit", "net"},
+		Columns: []string{"Месяц", "Активы", "Кредиты", "Чистые активы"},
+		Numeric: []bool{false, true, true, true},
+	}
+
+	for _, point := range n.Points {
+		table.Rows = append(table.Rows, ReportRow{Cells: []string{
+			point.Date.Format("2006-01"),
+			strconv.FormatInt(point.Assets, 10),
+			strconv.FormatInt(point.Credit, 10),
+			strconv.FormatInt(point.Net, 10),
+		}})
+	}
+
+	return table
+}
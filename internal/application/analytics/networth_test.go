@@ -0,0 +1,76 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+func TestNetWorthCreditSpendingLowersNetWorth(t *testing.T) {
+	end := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	spentAt := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		cardBalance int64
+		spending    int64
+		wantCredit  int64
+		wantNet     int64
+		wantBefore  int64
+	}{
+		{name: "from zero", cardBalance: 0, spending: 500, wantCredit: 500, wantNet: 500, wantBefore: 1000},
+		{name: "existing debt", cardBalance: -200, spending: 300, wantCredit: 500, wantNet: 500, wantBefore: 800},
+		{name: "overpaid card", cardBalance: 400, spending: 100, wantCredit: -300, wantNet: 1300, wantBefore: 1400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cash, err := domain.NewBankAccount("CASH", "Наличные", 1000)
+			if err != nil {
+				t.Fatalf("cash: %v", err)
+			}
+			card, err := domain.NewBankAccount("CARD", "Кредитка", tt.cardBalance)
+			if err != nil {
+				t.Fatalf("card: %v", err)
+			}
+			profile, err := domain.NewAccountProfile("CARD", domain.AccountKindCredit, "")
+			if err != nil {
+				t.Fatalf("profile: %v", err)
+			}
+			op, err := domain.NewOperation("OP", domain.OperationTypeExpense, "CARD", "FOOD", tt.spending, spentAt, "")
+			if err != nil {
+				t.Fatalf("operation: %v", err)
+			}
+			if err := card.ApplyOperationAs(domain.AccountKindCredit, op); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+
+			result, err := NewService().NetWorth(
+				[]*domain.BankAccount{cash, card},
+				[]*domain.AccountProfile{profile},
+				[]*domain.Operation{op},
+				NetWorthOptions{End: end, Months: 2},
+			)
+			if err != nil {
+				t.Fatalf("NetWorth: %v", err)
+			}
+
+			if result.Credit != tt.wantCredit {
+				t.Fatalf("credit = %d, want %d", result.Credit, tt.wantCredit)
+			}
+			if result.Net != tt.wantNet {
+				t.Fatalf("net = %d, want %d", result.Net, tt.wantNet)
+			}
+			if got := result.Points[0].Net; got != tt.wantBefore {
+				t.Fatalf("net before spending = %d, want %d", got, tt.wantBefore)
+			}
+			if got := result.Points[1].Net; got != tt.wantNet {
+				t.Fatalf("net after spending = %d, want %d", got, tt.wantNet)
+			}
+			if result.Points[1].Net >= result.Points[0].Net {
+				t.Fatalf("spending on a card did not lower net worth: %+v", result.Points)
+			}
+		})
+	}
+}
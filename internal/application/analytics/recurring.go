@@ -0,0 +1,223 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type Cadence string
+
+const (
+	CadenceWeekly    Cadence = "weekly"
+	CadenceBiweekly  Cadence = "biweekly"
+	CadenceMonthly   Cadence = "monthly"
+	CadenceQuarterly Cadence = "quarterly"
+	CadenceYearly    Cadence = "yearly"
+)
+
+type cadenceRule struct {
+	cadence  Cadence
+	days     float64
+	minDays  float64
+	maxDays  float64
+	minCount int
+}
+
+var cadenceRules = []cadenceRule{
+	{cadence: CadenceWeekly, days: 7, minDays: 5, maxDays: 9, minCount: 3},
+	{cadence: CadenceBiweekly, days: 14, minDays: 12, maxDays: 17, minCount: 3},
+	{cadence: CadenceMonthly, days: 30.4, minDays: 26, maxDays: 35, minCount: 3},
+	{cadence: CadenceQuarterly, days: 91, minDays: 83, maxDays: 98, minCount: 3},
+	{cadence: CadenceYearly, days: 365, minDays: 350, maxDays: 380, minCount: 2},
+}
+
+func (c Cadence) Next(t time.Time) time.Time {
+	switch c {
+	case CadenceWeekly:
+		return t.AddDate(0, 0, 7)
+	case CadenceBiweekly:
+		return t.AddDate(0, 0, 14)
+	case CadenceMonthly:
+		return t.AddDate(0, 1, 0)
+	case CadenceQuarterly:
+		return t.AddDate(0, 3, 0)
+	case CadenceYearly:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t
+	}
+}
+
+func (c Cadence) PerYear() float64 {
+	switch c {
+	case CadenceWeekly:
+		return 52
+	case CadenceBiweekly:
+		return 26
+	case CadenceMonthly:
+		return 12
+	case CadenceQuarterly:
+		return 4
+	case CadenceYearly:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type RecurringPattern struct {
+	Key          string
+	AccountID    domain.ID
+	CategoryID   domain.ID
+	Type         domain.OperationType
+	Description  string
+	Cadence      Cadence
+	Amount       int64
+	Occurrences  int
+	Last         time.Time
+	Next         time.Time
+	Active       bool
+	OperationIDs []domain.ID
+}
+
+func (service) DetectRecurring(operations []*domain.Operation, now time.Time) ([]RecurringPattern, error) {
+	return detectRecurring(operations, now), nil
+}
+
+func detectRecurring(operations []*domain.Operation, now time.Time) []RecurringPattern {
+	groups := make(map[string][]*domain.Operation)
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		key := recurringKey(op)
+		groups[key] = append(groups[key], op)
+	}
+
+	var patterns []RecurringPattern
+	for key, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Date().Before(group[j].Date())
+		})
+
+		pattern, ok := matchCadence(group)
+		if !ok {
+			continue
+		}
+
+		last := group[len(group)-1]
+		pattern.Key = key
+		pattern.AccountID = last.BankAccountID()
+		pattern.CategoryID = last.CategoryID()
+		pattern.Type = last.Type()
+		pattern.Description = strings.TrimSpace(last.Description())
+		pattern.Occurrences = len(group)
+		pattern.Last = last.Date()
+		pattern.Next = pattern.Cadence.Next(pattern.Last)
+		for pattern.Next.Before(now) && pattern.Cadence.Next(pattern.Next).Before(now) {
+			pattern.Next = pattern.Cadence.Next(pattern.Next)
+		}
+		pattern.Active = !pattern.Cadence.Next(pattern.Cadence.Next(pattern.Last)).Before(now)
+		for _, op := range group {
+			pattern.OperationIDs = append(pattern.OperationIDs, op.ID())
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Next.Equal(patterns[j].Next) {
+			return patterns[i].Key < patterns[j].Key
+		}
+		return patterns[i].Next.Before(patterns[j].Next)
+	})
+
+	return patterns
+}
+
+func matchCadence(group []*domain.Operation) (RecurringPattern, bool) {
+	intervals := make([]float64, 0, len(group)-1)
+	for i := 1; i < len(group); i++ {
+		intervals = append(intervals, group[i].Date().Sub(group[i-1].Date()).Hours()/24)
+	}
+	typical := median(intervals)
+
+	for _, rule := range cadenceRules {
+		if len(group) < rule.minCount || typical < rule.minDays || typical > rule.maxDays {
+			continue
+		}
+
+		regular := 0
+		for _, interval := range intervals {
+			if interval >= rule.minDays && interval <= rule.maxDays {
+				regular++
+			}
+		}
+		if regular*3 < len(intervals)*2 {
+			return RecurringPattern{}, false
+		}
+
+		amounts := make([]float64, 0, len(group))
+		var total int64
+		for _, op := range group {
+			amounts = append(amounts, float64(op.Amount()))
+			total += op.Amount()
+		}
+		typicalAmount := median(amounts)
+		stable := 0
+		for _, amount := range amounts {
+			if amount >= typicalAmount*0.75 && amount <= typicalAmount*1.25 {
+				stable++
+			}
+		}
+		if stable*3 < len(amounts)*2 {
+			return RecurringPattern{}, false
+		}
+
+		return RecurringPattern{
+			Cadence: rule.cadence,
+			Amount:  (total + int64(len(group))/2) / int64(len(group)),
+		}, true
+	}
+
+	return RecurringPattern{}, false
+}
+
+func recurringKey(op *domain.Operation) string {
+	description := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, op.Description())
+
+	return strings.Join([]string{
+		op.BankAccountID().String(),
+		op.CategoryID().String(),
+		string(op.Type()),
+		strings.Join(strings.Fields(description), " "),
+	}, "|")
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
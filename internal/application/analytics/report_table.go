@@ -0,0 +1,29 @@
+package analytics
+
+import "errors"
+
+type ReportFormat string
+
+const (
+	ReportFormatCSV      ReportFormat = "csv"
+	ReportFormatMarkdown ReportFormat = "markdown"
+)
+
+var ErrUnsupportedReportFormat = errors.New("analytics: unsupported report format")
+
+type ReportWriter interface {
+	SaveReport(path string, format ReportFormat, table ReportTable) error
+}
+
+type ReportRow struct {
+	Cells []string
+	Total bool
+}
+
+type ReportTable struct {
+	Title   string
+	Keys    []string
+	Columns []string
+	Numeric []bool
+	Rows    []ReportRow
+}
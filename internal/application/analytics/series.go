@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+const maxSeriesBuckets = 10000
+
+var ErrTooManyBuckets = errors.New("analytics: too many buckets for the selected range")
+
+type Granularity string
+
+const (
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+	GranularityYear  Granularity = "year"
+)
+
+type SeriesOptions struct {
+	Granularity Granularity
+	WeekStart   time.Weekday
+	Location    *time.Location
+	From        *time.Time
+	To          *time.Time
+}
+
+type Bucket struct {
+	Start   time.Time
+	End     time.Time
+	Income  int64
+	Expense int64
+	Net     int64
+	Count   int
+}
+
+func (s service) Series(operations []*domain.Operation, options SeriesOptions) ([]Bucket, error) {
+	return s.SeriesFromAggregates(operationCells(operations), options)
+}
+
+func (service) SeriesFromAggregates(cells []repository.AggregateCell, options SeriesOptions) ([]Bucket, error) {
+	switch options.Granularity {
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityYear:
+	default:
+		return nil, fmt.Errorf("analytics: unsupported granularity %q", options.Granularity)
+	}
+
+	loc := options.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var first, last time.Time
+	if options.From != nil {
+		first = *options.From
+	}
+	if options.To != nil {
+		last = *options.To
+	}
+	for _, cell := range cells {
+		date := cell.Day
+		if options.From == nil && (first.IsZero() || date.Before(first)) {
+			first = date
+		}
+		if options.To == nil && (last.IsZero() || date.After(last)) {
+			last = date
+		}
+	}
+	if first.IsZero() || last.IsZero() || last.Before(first) {
+		return nil, nil
+	}
+
+	var buckets []Bucket
+	index := make(map[int64]int)
+	for start := bucketStart(first, options.Granularity, options.WeekStart, loc); !start.After(last); {
+		if len(buckets) >= maxSeriesBuckets {
+			return nil, ErrTooManyBuckets
+		}
+		next := nextBucket(start, options.Granularity)
+		index[start.Unix()] = len(buckets)
+		buckets = append(buckets, Bucket{Start: start, End: next.Add(-time.Nanosecond)})
+		start = next
+	}
+
+	for _, cell := range cells {
+		pos, ok := index[bucketStart(cell.Day, options.Granularity, options.WeekStart, loc).Unix()]
+		if !ok {
+			continue
+		}
+
+		bucket := &buckets[pos]
+		switch cell.Type {
+		case domain.OperationTypeIncome:
+			bucket.Income += cell.Amount
+		case domain.OperationTypeExpense:
+			bucket.Expense += cell.Amount
+		default:
+			return nil, fmt.Errorf("analytics: unsupported operation type %q", cell.Type)
+		}
+		bucket.Count += cell.Count
+	}
+
+	for i := range buckets {
+		buckets[i].Net = buckets[i].Income - buckets[i].Expense
+	}
+
+	return buckets, nil
+}
+
+func bucketStart(t time.Time, granularity Granularity, weekStart time.Weekday, loc *time.Location) time.Time {
+	t = t.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	case GranularityYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	default:
+		return day
+	}
+}
+
+func nextBucket(start time.Time, granularity Granularity) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	case GranularityYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
@@ -2,8 +2,10 @@ package analytics
 
 import (
 	"fmt"
+	"time"
 
 	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
 )
 
 type Totals struct {
@@ -14,6 +16,32 @@ type Totals struct {
 
 type Service interface {
 	NetTotals(operations []*domain.Operation) (Totals, error)
+	TotalsFromAggregates(cells []repository.AggregateCell) (Totals, error)
+	Breakdown(operations []*domain.Operation, dimension Dimension) (Breakdown, error)
+	BreakdownFromAggregates(cells []repository.AggregateCell, dimension Dimension) (Breakdown, error)
+	Series(operations []*domain.Operation, options SeriesOptions) ([]Bucket, error)
+	SeriesFromAggregates(cells []repository.AggregateCell, options SeriesOptions) ([]Bucket, error)
+	Statement(current int64, operations []*domain.Operation) (Statement, error)
+	BalanceAsOf(current int64, operations []*domain.Operation, at time.Time) (int64, error)
+	BalanceSeries(current int64, operations []*domain.Operation, from, to time.Time) ([]BalancePoint, error)
+	DetectRecurring(operations []*domain.Operation, now time.Time) ([]RecurringPattern, error)
+	Forecast(accounts []*domain.BankAccount, operations []*domain.Operation, options ForecastOptions) (Forecast, error)
+	Anomalies(operations []*domain.Operation, options AnomalyOptions) ([]Anomaly, error)
+	Compare(current, previous []*domain.Operation) (Comparison, error)
+	Subscriptions(operations []*domain.Operation, now time.Time, ignored []string) ([]Subscription, error)
+	NetWorth(
+		accounts []*domain.BankAccount,
+		profiles []*domain.AccountProfile,
+		operations []*domain.Operation,
+		options NetWorthOptions,
+	) (NetWorth, error)
+	IncomeStatement(categories []*domain.Category, operations []*domain.Operation) (IncomeStatement, error)
+	BalanceSheet(
+		accounts []*domain.BankAccount,
+		profiles []*domain.AccountProfile,
+		operations []*domain.Operation,
+		at time.Time,
+	) (BalanceSheet, error)
 }
 
 type service struct{}
@@ -0,0 +1,44 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type Subscription struct {
+	RecurringPattern
+	AnnualCost int64
+	Ignored    bool
+}
+
+func (service) Subscriptions(operations []*domain.Operation, now time.Time, ignored []string) ([]Subscription, error) {
+	skip := make(map[string]struct{}, len(ignored))
+	for _, key := range ignored {
+		skip[key] = struct{}{}
+	}
+
+	var subscriptions []Subscription
+	for _, pattern := range detectRecurring(operations, now) {
+		if pattern.Type != domain.OperationTypeExpense || !pattern.Active {
+			continue
+		}
+		_, isIgnored := skip[pattern.Key]
+		subscriptions = append(subscriptions, Subscription{
+			RecurringPattern: pattern,
+			AnnualCost:       int64(math.Round(float64(pattern.Amount) * pattern.Cadence.PerYear())),
+			Ignored:          isIgnored,
+		})
+	}
+
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		if subscriptions[i].Ignored != subscriptions[j].Ignored {
+			return !subscriptions[i].Ignored
+		}
+		return subscriptions[i].AnnualCost > subscriptions[j].AnnualCost
+	})
+
+	return subscriptions, nil
+}
@@ -14,6 +14,10 @@ type Decorators struct {
 	Delete []command.Decorator[command.NoResult]
 	List   []command.Decorator[[]*domain.BankAccount]
 	Get    []command.Decorator[*domain.BankAccount]
+
+	SetProfile []command.Decorator[*domain.AccountProfile]
+	Profile    []command.Decorator[*domain.AccountProfile]
+	Profiles   []command.Decorator[[]*domain.AccountProfile]
 }
 
 type Service struct {
@@ -82,3 +86,37 @@ func (s *Service) Get(id domain.ID) command.Command[*domain.BankAccount] {
 	}
 	return command.Wrap(base, s.decorators.Get...)
 }
+
+func (s *Service) SetProfile(
+	id domain.ID,
+	kind domain.AccountKind,
+	group string,
+) command.Command[*domain.AccountProfile] {
+	base := command.Func[*domain.AccountProfile]{
+		ExecFn: func(_ context.Context) (*domain.AccountProfile, error) {
+			return s.facade.SetAccountProfile(id, kind, group)
+		},
+		NameFn: func() string { return "account.set_profile" },
+	}
+	return command.Wrap(base, s.decorators.SetProfile...)
+}
+
+func (s *Service) Profile(id domain.ID) command.Command[*domain.AccountProfile] {
+	base := command.Func[*domain.AccountProfile]{
+		ExecFn: func(_ context.Context) (*domain.AccountProfile, error) {
+			return s.facade.GetAccountProfile(id)
+		},
+		NameFn: func() string { return "account.profile" },
+	}
+	return command.Wrap(base, s.decorators.Profile...)
+}
+
+func (s *Service) Profiles() command.Command[[]*domain.AccountProfile] {
+	base := command.Func[[]*domain.AccountProfile]{
+		ExecFn: func(_ context.Context) ([]*domain.AccountProfile, error) {
+			return s.facade.ListAccountProfiles()
+		},
+		NameFn: func() string { return "account.profiles" },
+	}
+	return command.Wrap(base, s.decorators.Profiles...)
+}
@@ -2,21 +2,46 @@ package analytics
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	appanalytics "kpo-hw-2/internal/application/analytics"
 	appcommand "kpo-hw-2/internal/application/command"
+	"kpo-hw-2/internal/application/facade"
 	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
 )
 
+var ErrUnavailable = errors.New("analytics: service is not configured")
+
 type Service struct {
-	analytics  appanalytics.Service
-	decorators Decorators
+	analytics     appanalytics.Service
+	accounts      facade.AccountFacade
+	categories    facade.CategoryFacade
+	operations    facade.OperationFacade
+	subscriptions facade.SubscriptionFacade
+	reports       appanalytics.ReportWriter
+	decorators    Decorators
 }
 
-func NewService(analytics appanalytics.Service, decorators Decorators) *Service {
+func NewService(
+	analytics appanalytics.Service,
+	accounts facade.AccountFacade,
+	categories facade.CategoryFacade,
+	operations facade.OperationFacade,
+	subscriptions facade.SubscriptionFacade,
+	reports appanalytics.ReportWriter,
+	decorators Decorators,
+) *Service {
 	return &Service{
-		analytics:  analytics,
-		decorators: decorators,
+		analytics:     analytics,
+		accounts:      accounts,
+		categories:    categories,
+		operations:    operations,
+		subscriptions: subscriptions,
+		reports:       reports,
+		decorators:    decorators,
 	}
 }
 
@@ -34,6 +59,496 @@ func (s *Service) NetTotals(operations []*domain.Operation) appcommand.Command[a
 	return appcommand.Wrap(base, s.decorators.NetTotals...)
 }
 
+func (s *Service) Totals(filter query.OperationFilter) appcommand.Command[appanalytics.Totals] {
+	base := appcommand.Func[appanalytics.Totals]{
+		ExecFn: func(_ context.Context) (appanalytics.Totals, error) {
+			if s.analytics == nil || s.operations == nil {
+				return appanalytics.Totals{}, nil
+			}
+			cells, ok, err := s.aggregates(filter)
+			if err != nil {
+				return appanalytics.Totals{}, err
+			}
+			if ok {
+				return s.analytics.TotalsFromAggregates(cells)
+			}
+			operations, err := s.operations.ListOperationsWithFilter(filter)
+			if err != nil {
+				return appanalytics.Totals{}, err
+			}
+			return s.analytics.NetTotals(operations)
+		},
+		NameFn: func() string { return "analytics.totals" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Totals...)
+}
+
+func (s *Service) Breakdown(filter query.OperationFilter, dimension appanalytics.Dimension) appcommand.Command[appanalytics.Breakdown] {
+	base := appcommand.Func[appanalytics.Breakdown]{
+		ExecFn: func(_ context.Context) (appanalytics.Breakdown, error) {
+			if s.analytics == nil || s.operations == nil {
+				return appanalytics.Breakdown{Dimension: dimension}, nil
+			}
+			cells, ok, err := s.aggregates(filter)
+			if err != nil {
+				return appanalytics.Breakdown{}, err
+			}
+			if ok {
+				return s.analytics.BreakdownFromAggregates(cells, dimension)
+			}
+			operations, err := s.operations.ListOperationsWithFilter(filter)
+			if err != nil {
+				return appanalytics.Breakdown{}, err
+			}
+			return s.analytics.Breakdown(operations, dimension)
+		},
+		NameFn: func() string { return "analytics.breakdown" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Breakdown...)
+}
+
+func (s *Service) Series(filter query.OperationFilter, options appanalytics.SeriesOptions) appcommand.Command[[]appanalytics.Bucket] {
+	base := appcommand.Func[[]appanalytics.Bucket]{
+		ExecFn: func(_ context.Context) ([]appanalytics.Bucket, error) {
+			if s.analytics == nil || s.operations == nil {
+				return nil, nil
+			}
+			if options.From == nil && options.To == nil {
+				options.From, options.To = filter.Period()
+			}
+			if utcDays(options.Location) {
+				cells, ok, err := s.aggregates(filter)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					return s.analytics.SeriesFromAggregates(cells, options)
+				}
+			}
+			operations, err := s.operations.ListOperationsWithFilter(filter)
+			if err != nil {
+				return nil, err
+			}
+			return s.analytics.Series(operations, options)
+		},
+		NameFn: func() string { return "analytics.series" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Series...)
+}
+
+func (s *Service) Statement(accountID domain.ID) appcommand.Command[appanalytics.Statement] {
+	base := appcommand.Func[appanalytics.Statement]{
+		ExecFn: func(_ context.Context) (appanalytics.Statement, error) {
+			current, operations, err := s.accountHistory(accountID)
+			if err != nil {
+				return appanalytics.Statement{}, err
+			}
+			return s.analytics.Statement(current, operations)
+		},
+		NameFn: func() string { return "analytics.statement" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Statement...)
+}
+
+func (s *Service) BalanceAsOf(accountID domain.ID, at time.Time) appcommand.Command[int64] {
+	base := appcommand.Func[int64]{
+		ExecFn: func(_ context.Context) (int64, error) {
+			current, operations, err := s.accountHistory(accountID)
+			if err != nil {
+				return 0, err
+			}
+			return s.analytics.BalanceAsOf(current, operations, at)
+		},
+		NameFn: func() string { return "analytics.balance_as_of" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.BalanceAsOf...)
+}
+
+func (s *Service) BalanceSeries(accountID domain.ID, from, to time.Time) appcommand.Command[[]appanalytics.BalancePoint] {
+	base := appcommand.Func[[]appanalytics.BalancePoint]{
+		ExecFn: func(_ context.Context) ([]appanalytics.BalancePoint, error) {
+			current, operations, err := s.accountHistory(accountID)
+			if err != nil {
+				return nil, err
+			}
+			return s.analytics.BalanceSeries(current, operations, from, to)
+		},
+		NameFn: func() string { return "analytics.balance_series" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.BalanceSeries...)
+}
+
+func (s *Service) Forecast(options appanalytics.ForecastOptions) appcommand.Command[appanalytics.Forecast] {
+	base := appcommand.Func[appanalytics.Forecast]{
+		ExecFn: func(_ context.Context) (appanalytics.Forecast, error) {
+			return s.forecast(options)
+		},
+		NameFn: func() string { return "analytics.forecast" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Forecast...)
+}
+
+func (s *Service) ExportForecast(options appanalytics.ForecastOptions, path string) appcommand.Command[appcommand.NoResult] {
+	base := appcommand.Func[appcommand.NoResult]{
+		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
+			forecast, err := s.forecast(options)
+			if err != nil {
+				return appcommand.NoResult{}, err
+			}
+			return appcommand.NoResult{}, s.saveReport(path, appanalytics.ReportFormatCSV, forecast.Table())
+		},
+		NameFn: func() string { return "analytics.export_forecast" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.ExportForecast...)
+}
+
+func (s *Service) forecast(options appanalytics.ForecastOptions) (appanalytics.Forecast, error) {
+	if s.analytics == nil || s.accounts == nil || s.operations == nil {
+		return appanalytics.Forecast{}, ErrUnavailable
+	}
+
+	accounts, err := s.accounts.ListAccounts()
+	if err != nil {
+		return appanalytics.Forecast{}, err
+	}
+
+	operations, err := s.operations.ListOperationsWithFilter(query.NewOperationFilter())
+	if err != nil {
+		return appanalytics.Forecast{}, err
+	}
+
+	return s.analytics.Forecast(accounts, operations, options)
+}
+
+func (s *Service) Anomalies(options appanalytics.AnomalyOptions) appcommand.Command[[]appanalytics.Anomaly] {
+	base := appcommand.Func[[]appanalytics.Anomaly]{
+		ExecFn: func(_ context.Context) ([]appanalytics.Anomaly, error) {
+			if s.analytics == nil || s.operations == nil {
+				return nil, ErrUnavailable
+			}
+			filter := query.NewOperationFilter().
+				OfType(domain.OperationTypeExpense).
+				From(options.WindowStart())
+			operations, err := s.operations.ListOperationsWithFilter(filter)
+			if err != nil {
+				return nil, err
+			}
+			return s.analytics.Anomalies(operations, options)
+		},
+		NameFn: func() string { return "analytics.anomalies" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Anomalies...)
+}
+
+func (s *Service) Compare(current, previous query.OperationFilter) appcommand.Command[appanalytics.Comparison] {
+	base := appcommand.Func[appanalytics.Comparison]{
+		ExecFn: func(_ context.Context) (appanalytics.Comparison, error) {
+			if s.analytics == nil || s.operations == nil {
+				return appanalytics.Comparison{}, ErrUnavailable
+			}
+			currentOps, err := s.operations.ListOperationsWithFilter(current)
+			if err != nil {
+				return appanalytics.Comparison{}, err
+			}
+			previousOps, err := s.operations.ListOperationsWithFilter(previous)
+			if err != nil {
+				return appanalytics.Comparison{}, err
+			}
+			return s.analytics.Compare(currentOps, previousOps)
+		},
+		NameFn: func() string { return "analytics.compare" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Compare...)
+}
+
+func (s *Service) Subscriptions(now time.Time) appcommand.Command[[]appanalytics.Subscription] {
+	base := appcommand.Func[[]appanalytics.Subscription]{
+		ExecFn: func(_ context.Context) ([]appanalytics.Subscription, error) {
+			if s.analytics == nil || s.operations == nil || s.subscriptions == nil {
+				return nil, ErrUnavailable
+			}
+			operations, err := s.operations.ListOperationsWithFilter(query.NewOperationFilter().OfType(domain.OperationTypeExpense))
+			if err != nil {
+				return nil, err
+			}
+			ignored, err := s.subscriptions.IgnoredSubscriptions()
+			if err != nil {
+				return nil, err
+			}
+			return s.analytics.Subscriptions(operations, now, ignored)
+		},
+		NameFn: func() string { return "analytics.subscriptions" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.Subscriptions...)
+}
+
+func (s *Service) IgnoreSubscription(key string) appcommand.Command[appcommand.NoResult] {
+	base := appcommand.Func[appcommand.NoResult]{
+		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
+			if s.subscriptions == nil {
+				return appcommand.NoResult{}, ErrUnavailable
+			}
+			return appcommand.NoResult{}, s.subscriptions.IgnoreSubscription(key)
+		},
+		NameFn: func() string { return "analytics.ignore_subscription" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.IgnoreSubscription...)
+}
+
+func (s *Service) RestoreSubscription(key string) appcommand.Command[appcommand.NoResult] {
+	base := appcommand.Func[appcommand.NoResult]{
+		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
+			if s.subscriptions == nil {
+				return appcommand.NoResult{}, ErrUnavailable
+			}
+			return appcommand.NoResult{}, s.subscriptions.RestoreSubscription(key)
+		},
+		NameFn: func() string { return "analytics.restore_subscription" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.RestoreSubscription...)
+}
+
+func (s *Service) NetWorth(options appanalytics.NetWorthOptions) appcommand.Command[appanalytics.NetWorth] {
+	base := appcommand.Func[appanalytics.NetWorth]{
+		ExecFn: func(_ context.Context) (appanalytics.NetWorth, error) {
+			return s.netWorth(options)
+		},
+		NameFn: func() string { return "analytics.net_worth" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.NetWorth...)
+}
+
+func (s *Service) ExportNetWorth(options appanalytics.NetWorthOptions, path string) appcommand.Command[appcommand.NoResult] {
+	base := appcommand.Func[appcommand.NoResult]{
+		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
+			netWorth, err := s.netWorth(options)
+			if err != nil {
+				return appcommand.NoResult{}, err
+			}
+			return appcommand.NoResult{}, s.saveReport(path, appanalytics.ReportFormatCSV, netWorth.Table())
+		},
+		NameFn: func() string { return "analytics.export_net_worth" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.ExportNetWorth...)
+}
+
+func (s *Service) netWorth(options appanalytics.NetWorthOptions) (appanalytics.NetWorth, error) {
+	if s.analytics == nil || s.accounts == nil || s.operations == nil {
+		return appanalytics.NetWorth{}, ErrUnavailable
+	}
+
+	accounts, err := s.accounts.ListAccounts()
+	if err != nil {
+		return appanalytics.NetWorth{}, err
+	}
+
+	profiles, err := s.accounts.ListAccountProfiles()
+	if err != nil {
+		return appanalytics.NetWorth{}, err
+	}
+
+	operations, err := s.operations.ListOperationsWithFilter(query.NewOperationFilter())
+	if err != nil {
+		return appanalytics.NetWorth{}, err
+	}
+
+	return s.analytics.NetWorth(accounts, profiles, operations, options)
+}
+
+func (s *Service) IncomeStatement(filter query.OperationFilter) appcommand.Command[appanalytics.IncomeStatement] {
+	base := appcommand.Func[appanalytics.IncomeStatement]{
+		ExecFn: func(_ context.Context) (appanalytics.IncomeStatement, error) {
+			return s.incomeStatement(filter)
+		},
+		NameFn: func() string { return "analytics.income_statement" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.IncomeStatement...)
+}
+
+func (s *Service) ExportIncomeStatement(
+	filter query.OperationFilter,
+	format appanalytics.ReportFormat,
+	path string,
+) appcommand.Command[appcommand.NoResult] {
+	base := appcommand.Func[appcommand.NoResult]{
+		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
+			statement, err := s.incomeStatement(filter)
+			if err != nil {
+				return appcommand.NoResult{}, err
+			}
+			return appcommand.NoResult{}, s.saveReport(path, format, statement.Table())
+		},
+		NameFn: func() string { return "analytics.export_income_statement" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.ExportIncomeStatement...)
+}
+
+func (s *Service) incomeStatement(filter query.OperationFilter) (appanalytics.IncomeStatement, error) {
+	if s.analytics == nil || s.categories == nil || s.operations == nil {
+		return appanalytics.IncomeStatement{}, ErrUnavailable
+	}
+
+	categories, err := s.categories.ListCategories("")
+	if err != nil {
+		return appanalytics.IncomeStatement{}, err
+	}
+
+	operations, err := s.operations.ListOperationsWithFilter(filter)
+	if err != nil {
+		return appanalytics.IncomeStatement{}, err
+	}
+
+	statement, err := s.analytics.IncomeStatement(categories, operations)
+	if err != nil {
+		return appanalytics.IncomeStatement{}, err
+	}
+	statement.From, statement.To = filter.Period()
+
+	return statement, nil
+}
+
+func (s *Service) BalanceSheet(at time.Time) appcommand.Command[appanalytics.BalanceSheet] {
+	base := appcommand.Func[appanalytics.BalanceSheet]{
+		ExecFn: func(_ context.Context) (appanalytics.BalanceSheet, error) {
+			return s.balanceSheet(at)
+		},
+		NameFn: func() string { return "analytics.balance_sheet" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.BalanceSheet...)
+}
+
+func (s *Service) ExportBalanceSheet(
+	at time.Time,
+	format appanalytics.ReportFormat,
+	path string,
+) appcommand.Command[appcommand.NoResult] {
+	base := appcommand.Func[appcommand.NoResult]{
+		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
+			sheet, err := s.balanceSheet(at)
+			if err != nil {
+				return appcommand.NoResult{}, err
+			}
+			return appcommand.NoResult{}, s.saveReport(path, format, sheet.Table())
+		},
+		NameFn: func() string { return "analytics.export_balance_sheet" },
+	}
+
+	return appcommand.Wrap(base, s.decorators.ExportBalanceSheet...)
+}
+
+func (s *Service) balanceSheet(at time.Time) (appanalytics.BalanceSheet, error) {
+	if s.analytics == nil || s.accounts == nil || s.operations == nil {
+		return appanalytics.BalanceSheet{}, ErrUnavailable
+	}
+
+	accounts, err := s.accounts.ListAccounts()
+	if err != nil {
+		return appanalytics.BalanceSheet{}, err
+	}
+
+	profiles, err := s.accounts.ListAccountProfiles()
+	if err != nil {
+		return appanalytics.BalanceSheet{}, err
+	}
+
+	operations, err := s.operations.ListOperationsWithFilter(query.NewOperationFilter().From(at))
+	if err != nil {
+		return appanalytics.BalanceSheet{}, err
+	}
+
+	return s.analytics.BalanceSheet(accounts, profiles, operations, at)
+}
+
+func (s *Service) aggregates(filter query.OperationFilter) ([]repository.AggregateCell, bool, error) {
+	cells, err := s.operations.AggregateOperations(filter)
+	if errors.Is(err, domain.ErrUnsupportedFilter) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return cells, true, nil
+}
+
+func utcDays(location *time.Location) bool {
+	if location == nil {
+		return false
+	}
+	switch location.String() {
+	case "UTC", "Etc/UTC":
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Service) accountHistory(accountID domain.ID) (int64, []*domain.Operation, error) {
+	if s.analytics == nil || s.accounts == nil || s.operations == nil {
+		return 0, nil, ErrUnavailable
+	}
+
+	account, err := s.accounts.GetAccount(accountID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	operations, err := s.operations.ListOperationsWithFilter(query.NewOperationFilter().ForAccount(accountID))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return account.Balance(), operations, nil
+}
+
 type Decorators struct {
 	NetTotals []appcommand.Decorator[appanalytics.Totals]
+	Totals    []appcommand.Decorator[appanalytics.Totals]
+	Breakdown []appcommand.Decorator[appanalytics.Breakdown]
+	Series    []appcommand.Decorator[[]appanalytics.Bucket]
+
+	Statement     []appcommand.Decorator[appanalytics.Statement]
+	BalanceAsOf   []appcommand.Decorator[int64]
+	BalanceSeries []appcommand.Decorator[[]appanalytics.BalancePoint]
+
+	Forecast       []appcommand.Decorator[appanalytics.Forecast]
+	ExportForecast []appcommand.Decorator[appcommand.NoResult]
+	Anomalies      []appcommand.Decorator[[]appanalytics.Anomaly]
+	Compare        []appcommand.Decorator[appanalytics.Comparison]
+
+	Subscriptions       []appcommand.Decorator[[]appanalytics.Subscription]
+	IgnoreSubscription  []appcommand.Decorator[appcommand.NoResult]
+	RestoreSubscription []appcommand.Decorator[appcommand.NoResult]
+
+	NetWorth       []appcommand.Decorator[appanalytics.NetWorth]
+	ExportNetWorth []appcommand.Decorator[appcommand.NoResult]
+
+	IncomeStatement       []appcommand.Decorator[appanalytics.IncomeStatement]
+	ExportIncomeStatement []appcommand.Decorator[appcommand.NoResult]
+	BalanceSheet          []appcommand.Decorator[appanalytics.BalanceSheet]
+	ExportBalanceSheet    []appcommand.Decorator[appcommand.NoResult]
+}
+
+func (s *Service) saveReport(path string, format appanalytics.ReportFormat, table appanalytics.ReportTable) error {
+	if s.reports == nil {
+		return ErrUnavailable
+	}
+	return s.reports.SaveReport(path, format, table)
 }
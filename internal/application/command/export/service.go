@@ -34,13 +34,13 @@ func (s *Service) ListFormats() appcommand.Command[[]appfiles.Format] {
 	return appcommand.Wrap(base, s.decorators.ListFormats...)
 }
 
-func (s *Service) ExportToPath(formatKey, destination string) appcommand.Command[appcommand.NoResult] {
+func (s *Service) ExportToPath(formatKey, destination string, options fileexport.Options) appcommand.Command[appcommand.NoResult] {
 	base := appcommand.Func[appcommand.NoResult]{
 		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
 			if s.exportService == nil {
 				return appcommand.NoResult{}, nil
 			}
-			err := s.exportService.ExportToPath(formatKey, destination)
+			err := s.exportService.ExportToPath(formatKey, destination, options)
 			return appcommand.NoResult{}, err
 		},
 		NameFn: func() string { return "export.to_path" },
@@ -48,13 +48,13 @@ func (s *Service) ExportToPath(formatKey, destination string) appcommand.Command
 	return appcommand.Wrap(base, s.decorators.ExportToPath...)
 }
 
-func (s *Service) Export(formatKey string, writer io.Writer) appcommand.Command[appcommand.NoResult] {
+func (s *Service) Export(formatKey string, writer io.Writer, options fileexport.Options) appcommand.Command[appcommand.NoResult] {
 	base := appcommand.Func[appcommand.NoResult]{
 		ExecFn: func(_ context.Context) (appcommand.NoResult, error) {
 			if s.exportService == nil {
 				return appcommand.NoResult{}, nil
 			}
-			err := s.exportService.Export(formatKey, writer)
+			err := s.exportService.Export(formatKey, writer, options)
 			return appcommand.NoResult{}, err
 		},
 		NameFn: func() string { return "export.write" },
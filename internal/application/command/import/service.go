@@ -6,6 +6,9 @@ import (
 	appcommand "kpo-hw-2/internal/application/command"
 	appfiles "kpo-hw-2/internal/application/files"
 	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+	filesmodel "kpo-hw-2/internal/files/model"
 )
 
 type Service struct {
@@ -33,20 +36,76 @@ func (s *Service) ListFormats() appcommand.Command[[]appfiles.Format] {
 	return appcommand.Wrap(base, s.decorators.ListFormats...)
 }
 
-func (s *Service) ImportFromPath(formatKey, path string) appcommand.Command[fileimport.Result] {
+func (s *Service) Profiles() appcommand.Command[[]filesmodel.StatementProfile] {
+	base := appcommand.Func[[]filesmodel.StatementProfile]{
+		ExecFn: func(_ context.Context) ([]filesmodel.StatementProfile, error) {
+			if s.importService == nil {
+				return nil, nil
+			}
+			return s.importService.Profiles()
+		},
+		NameFn: func() string { return "import.profiles" },
+	}
+	return appcommand.Wrap(base, s.decorators.Profiles...)
+}
+
+func (s *Service) ImportFromPath(formatKey, path string, options fileimport.Options) appcommand.Command[fileimport.Result] {
 	base := appcommand.Func[fileimport.Result]{
 		ExecFn: func(_ context.Context) (fileimport.Result, error) {
 			if s.importService == nil {
 				return fileimport.Result{}, nil
 			}
-			return s.importService.ImportFromPath(formatKey, path)
+			return s.importService.ImportFromPath(formatKey, path, options)
 		},
 		NameFn: func() string { return "import.from_path" },
 	}
 	return appcommand.Wrap(base, s.decorators.ImportFromPath...)
 }
 
+func (s *Service) PreviewFromPath(formatKey, path string, options fileimport.Options) appcommand.Command[fileimport.Preview] {
+	base := appcommand.Func[fileimport.Preview]{
+		ExecFn: func(_ context.Context) (fileimport.Preview, error) {
+			if s.importService == nil {
+				return fileimport.Preview{}, nil
+			}
+			return s.importService.PreviewFromPath(formatKey, path, options)
+		},
+		NameFn: func() string { return "import.preview_from_path" },
+	}
+	return appcommand.Wrap(base, s.decorators.PreviewFromPath...)
+}
+
+func (s *Service) Batches() appcommand.Command[[]repository.ImportBatch] {
+	base := appcommand.Func[[]repository.ImportBatch]{
+		ExecFn: func(_ context.Context) ([]repository.ImportBatch, error) {
+			if s.importService == nil {
+				return nil, nil
+			}
+			return s.importService.Batches()
+		},
+		NameFn: func() string { return "import.batches" },
+	}
+	return appcommand.Wrap(base, s.decorators.Batches...)
+}
+
+func (s *Service) Rollback(id domain.ID) appcommand.Command[repository.ImportBatch] {
+	base := appcommand.Func[repository.ImportBatch]{
+		ExecFn: func(_ context.Context) (repository.ImportBatch, error) {
+			if s.importService == nil {
+				return repository.ImportBatch{}, nil
+			}
+			return s.importService.Rollback(id)
+		},
+		NameFn: func() string { return "import.rollback" },
+	}
+	return appcommand.Wrap(base, s.decorators.Rollback...)
+}
+
 type Decorators struct {
-	ListFormats    []appcommand.Decorator[[]appfiles.Format]
-	ImportFromPath []appcommand.Decorator[fileimport.Result]
+	ListFormats     []appcommand.Decorator[[]appfiles.Format]
+	Profiles        []appcommand.Decorator[[]filesmodel.StatementProfile]
+	ImportFromPath  []appcommand.Decorator[fileimport.Result]
+	PreviewFromPath []appcommand.Decorator[fileimport.Preview]
+	Batches         []appcommand.Decorator[[]repository.ImportBatch]
+	Rollback        []appcommand.Decorator[repository.ImportBatch]
 }
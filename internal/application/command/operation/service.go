@@ -16,6 +16,8 @@ type Decorators struct {
 	Delete []command.Decorator[command.NoResult]
 	List   []command.Decorator[[]*domain.Operation]
 	Get    []command.Decorator[*domain.Operation]
+	Parse  []command.Decorator[query.OperationFilter]
+	Query  []command.Decorator[[]*domain.Operation]
 }
 
 type Service struct {
@@ -101,6 +103,30 @@ func (s *Service) List(filter query.OperationFilter) command.Command[[]*domain.O
 	return command.Wrap(base, s.decorators.List...)
 }
 
+func (s *Service) ParseQuery(expression string) command.Command[query.OperationFilter] {
+	base := command.Func[query.OperationFilter]{
+		ExecFn: func(_ context.Context) (query.OperationFilter, error) {
+			return s.facade.ParseOperationQuery(expression)
+		},
+		NameFn: func() string { return "operation.parse_query" },
+	}
+	return command.Wrap(base, s.decorators.Parse...)
+}
+
+func (s *Service) Query(expression string) command.Command[[]*domain.Operation] {
+	base := command.Func[[]*domain.Operation]{
+		ExecFn: func(_ context.Context) ([]*domain.Operation, error) {
+			filter, err := s.facade.ParseOperationQuery(expression)
+			if err != nil {
+				return nil, err
+			}
+			return s.facade.ListOperationsWithFilter(filter)
+		},
+		NameFn: func() string { return "operation.query" },
+	}
+	return command.Wrap(base, s.decorators.Query...)
+}
+
 func (s *Service) Get(id domain.ID) command.Command[*domain.Operation] {
 	base := command.Func[*domain.Operation]{
 		ExecFn: func(_ context.Context) (*domain.Operation, error) {
@@ -0,0 +1,95 @@
+package view
+
+import (
+	"context"
+
+	"kpo-hw-2/internal/application/command"
+	"kpo-hw-2/internal/application/facade"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+type Decorators struct {
+	Create  []command.Decorator[*query.SavedView]
+	Delete  []command.Decorator[command.NoResult]
+	List    []command.Decorator[[]*query.SavedView]
+	Get     []command.Decorator[*query.SavedView]
+	Resolve []command.Decorator[query.OperationFilter]
+}
+
+type Service struct {
+	facade     facade.SavedViewFacade
+	decorators Decorators
+}
+
+func NewService(f facade.SavedViewFacade, decorators Decorators) *Service {
+	return &Service{
+		facade:     f,
+		decorators: decorators,
+	}
+}
+
+func (s *Service) Create(
+	name string,
+	filter query.OperationFilter,
+	period query.PeriodSpec,
+) command.Command[*query.SavedView] {
+	base := command.Func[*query.SavedView]{
+		ExecFn: func(_ context.Context) (*query.SavedView, error) {
+			return s.facade.CreateView(name, filter, period)
+		},
+		NameFn: func() string { return "view.create" },
+	}
+	return command.Wrap(base, s.decorators.Create...)
+}
+
+func (s *Service) CreateFromQuery(name, expression string) command.Command[*query.SavedView] {
+	base := command.Func[*query.SavedView]{
+		ExecFn: func(_ context.Context) (*query.SavedView, error) {
+			return s.facade.CreateViewFromQuery(name, expression)
+		},
+		NameFn: func() string { return "view.create_from_query" },
+	}
+	return command.Wrap(base, s.decorators.Create...)
+}
+
+func (s *Service) Delete(id domain.ID) command.Command[command.NoResult] {
+	base := command.Func[command.NoResult]{
+		ExecFn: func(_ context.Context) (command.NoResult, error) {
+			err := s.facade.DeleteView(id)
+			return command.NoResult{}, err
+		},
+		NameFn: func() string { return "view.delete" },
+	}
+	return command.Wrap(base, s.decorators.Delete...)
+}
+
+func (s *Service) List() command.Command[[]*query.SavedView] {
+	base := command.Func[[]*query.SavedView]{
+		ExecFn: func(_ context.Context) ([]*query.SavedView, error) {
+			return s.facade.ListViews()
+		},
+		NameFn: func() string { return "view.list" },
+	}
+	return command.Wrap(base, s.decorators.List...)
+}
+
+func (s *Service) Get(id domain.ID) command.Command[*query.SavedView] {
+	base := command.Func[*query.SavedView]{
+		ExecFn: func(_ context.Context) (*query.SavedView, error) {
+			return s.facade.GetView(id)
+		},
+		NameFn: func() string { return "view.get" },
+	}
+	return command.Wrap(base, s.decorators.Get...)
+}
+
+func (s *Service) Resolve(id domain.ID) command.Command[query.OperationFilter] {
+	base := command.Func[query.OperationFilter]{
+		ExecFn: func(_ context.Context) (query.OperationFilter, error) {
+			return s.facade.ResolveView(id)
+		},
+		NameFn: func() string { return "view.resolve" },
+	}
+	return command.Wrap(base, s.decorators.Resolve...)
+}
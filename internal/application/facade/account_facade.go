@@ -6,7 +6,12 @@ type AccountFacade interface {
 	CreateAccount(name string) (*domain.BankAccount, error)
 	CreateAccountWithID(id domain.ID, name string, balance int64) (*domain.BankAccount, error)
 	UpdateAccount(id domain.ID, name string, balance int64) (*domain.BankAccount, error)
+	CreateAccountWithProfile(id domain.ID, name string, balance int64, kind domain.AccountKind, group string) (*domain.BankAccount, error)
+	UpdateAccountWithProfile(id domain.ID, name string, balance int64, kind domain.AccountKind, group string) (*domain.BankAccount, error)
 	DeleteAccount(id domain.ID) error
 	ListAccounts() ([]*domain.BankAccount, error)
 	GetAccount(id domain.ID) (*domain.BankAccount, error)
+	SetAccountProfile(id domain.ID, kind domain.AccountKind, group string) (*domain.AccountProfile, error)
+	GetAccountProfile(id domain.ID) (*domain.AccountProfile, error)
+	ListAccountProfiles() ([]*domain.AccountProfile, error)
 }
@@ -1,6 +1,8 @@
 package facade
 
 import (
+	"errors"
+
 	"kpo-hw-2/internal/domain"
 	domainfactory "kpo-hw-2/internal/domain/factory"
 	"kpo-hw-2/internal/domain/repository"
@@ -9,15 +11,18 @@ import (
 type accountFacade struct {
 	factory  domainfactory.BankAccountFactory
 	accounts repository.AccountRepository
+	profiles repository.AccountProfileRepository
 }
 
 func NewAccountFacade(
 	accountFactory domainfactory.BankAccountFactory,
 	accountRepo repository.AccountRepository,
+	profileRepo repository.AccountProfileRepository,
 ) AccountFacade {
 	return &accountFacade{
 		factory:  accountFactory,
 		accounts: accountRepo,
+		profiles: profileRepo,
 	}
 }
 
@@ -40,6 +45,10 @@ func (f *accountFacade) CreateAccountWithID(id domain.ID, name string, balance i
 		return nil, err
 	}
 
+	if err := f.checkBalance(account); err != nil {
+		return nil, err
+	}
+
 	if err := f.accounts.Create(account); err != nil {
 		return nil, err
 	}
@@ -53,6 +62,10 @@ func (f *accountFacade) UpdateAccount(id domain.ID, name string, balance int64)
 		return nil, err
 	}
 
+	if err := f.checkBalance(account); err != nil {
+		return nil, err
+	}
+
 	if err := f.accounts.Update(account); err != nil {
 		return nil, err
 	}
@@ -60,12 +73,97 @@ func (f *accountFacade) UpdateAccount(id domain.ID, name string, balance int64)
 	return account, nil
 }
 
+func (f *accountFacade) CreateAccountWithProfile(
+	id domain.ID,
+	name string,
+	balance int64,
+	kind domain.AccountKind,
+	group string,
+) (*domain.BankAccount, error) {
+	account, profile, err := f.rebuildWithProfile(id, name, balance, kind, group)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.accounts.Create(account); err != nil {
+		return nil, err
+	}
+
+	if err := f.profiles.Save(profile); err != nil {
+		_ = f.accounts.Delete(id)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (f *accountFacade) UpdateAccountWithProfile(
+	id domain.ID,
+	name string,
+	balance int64,
+	kind domain.AccountKind,
+	group string,
+) (*domain.BankAccount, error) {
+	account, profile, err := f.rebuildWithProfile(id, name, balance, kind, group)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := f.accounts.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.accounts.Update(account); err != nil {
+		return nil, err
+	}
+
+	if err := f.profiles.Save(profile); err != nil {
+		_ = f.accounts.Update(previous)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (f *accountFacade) rebuildWithProfile(
+	id domain.ID,
+	name string,
+	balance int64,
+	kind domain.AccountKind,
+	group string,
+) (*domain.BankAccount, *domain.AccountProfile, error) {
+	account, err := f.factory.Rebuild(id, name, balance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := domain.NewAccountProfile(id, kind, group)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := account.CheckBalance(kind); err != nil {
+		return nil, nil, err
+	}
+
+	return account, profile, nil
+}
+
 func (f *accountFacade) DeleteAccount(id domain.ID) error {
 	if id == "" {
 		return domain.ErrInvalidBankAccount
 	}
 
-	return f.accounts.Delete(id)
+	if err := f.accounts.Delete(id); err != nil {
+		return err
+	}
+
+	if err := f.profiles.Delete(id); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	return nil
 }
 
 func (f *accountFacade) ListAccounts() ([]*domain.BankAccount, error) {
@@ -80,4 +178,64 @@ func (f *accountFacade) GetAccount(id domain.ID) (*domain.BankAccount, error) {
 	return f.accounts.Get(id)
 }
 
+func (f *accountFacade) SetAccountProfile(id domain.ID, kind domain.AccountKind, group string) (*domain.AccountProfile, error) {
+	account, err := f.GetAccount(id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := domain.NewAccountProfile(id, kind, group)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := account.CheckBalance(kind); err != nil {
+		return nil, err
+	}
+
+	if err := f.profiles.Save(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (f *accountFacade) GetAccountProfile(id domain.ID) (*domain.AccountProfile, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidBankAccount
+	}
+
+	profile, err := f.profiles.Get(id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultAccountProfile(id), nil
+	}
+	return profile, err
+}
+
+func (f *accountFacade) ListAccountProfiles() ([]*domain.AccountProfile, error) {
+	accounts, err := f.accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*domain.AccountProfile, 0, len(accounts))
+	for _, account := range accounts {
+		profile, err := f.GetAccountProfile(account.ID())
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func (f *accountFacade) checkBalance(account *domain.BankAccount) error {
+	profile, err := f.GetAccountProfile(account.ID())
+	if err != nil {
+		return err
+	}
+	return account.CheckBalance(profile.Kind())
+}
+
 var _ AccountFacade = (*accountFacade)(nil)
@@ -5,6 +5,7 @@ import (
 
 	"kpo-hw-2/internal/domain"
 	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
 )
 
 type OperationFacade interface {
@@ -16,6 +17,16 @@ type OperationFacade interface {
 		date time.Time,
 		description string,
 	) (*domain.Operation, error)
+	CreateOperationWithID(
+		id domain.ID,
+		typ domain.OperationType,
+		accountID domain.ID,
+		categoryID domain.ID,
+		amount int64,
+		date time.Time,
+		description string,
+		updatedAt time.Time,
+	) (*domain.Operation, error)
 	CreateOperationWithoutBalance(
 		id domain.ID,
 		typ domain.OperationType,
@@ -24,6 +35,7 @@ type OperationFacade interface {
 		amount int64,
 		date time.Time,
 		description string,
+		updatedAt time.Time,
 	) (*domain.Operation, error)
 	UpdateOperation(
 		id domain.ID,
@@ -34,7 +46,30 @@ type OperationFacade interface {
 		date time.Time,
 		description string,
 	) (*domain.Operation, error)
+	ReplaceOperation(
+		id domain.ID,
+		typ domain.OperationType,
+		accountID domain.ID,
+		categoryID domain.ID,
+		amount int64,
+		date time.Time,
+		description string,
+		updatedAt time.Time,
+	) (*domain.Operation, error)
+	UpdateOperationWithoutBalance(
+		id domain.ID,
+		typ domain.OperationType,
+		accountID domain.ID,
+		categoryID domain.ID,
+		amount int64,
+		date time.Time,
+		description string,
+		updatedAt time.Time,
+	) (*domain.Operation, error)
 	DeleteOperation(id domain.ID) error
+	DeleteOperationWithoutBalance(id domain.ID) error
 	ListOperationsWithFilter(filter query.OperationFilter) ([]*domain.Operation, error)
+	AggregateOperations(filter query.OperationFilter) ([]repository.AggregateCell, error)
+	ParseOperationQuery(expression string) (query.OperationFilter, error)
 	GetOperation(id domain.ID) (*domain.Operation, error)
 }
@@ -1,6 +1,7 @@
 package facade
 
 import (
+	"errors"
 	"time"
 
 	"kpo-hw-2/internal/domain"
@@ -14,6 +15,8 @@ type operationFacade struct {
 	operations repository.OperationRepository
 	accounts   repository.AccountRepository
 	categories repository.CategoryRepository
+	profiles   repository.AccountProfileRepository
+	aggregates repository.OperationAggregateRepository
 }
 
 func NewOperationFacade(
@@ -21,12 +24,16 @@ func NewOperationFacade(
 	operationRepo repository.OperationRepository,
 	accountRepo repository.AccountRepository,
 	categoryRepo repository.CategoryRepository,
+	profileRepo repository.AccountProfileRepository,
+	aggregateRepo repository.OperationAggregateRepository,
 ) OperationFacade {
 	return &operationFacade{
 		factory:    operationFactory,
 		operations: operationRepo,
 		accounts:   accountRepo,
 		categories: categoryRepo,
+		profiles:   profileRepo,
+		aggregates: aggregateRepo,
 	}
 }
 
@@ -44,6 +51,7 @@ func (f *operationFacade) CreateOperation(
 		},
 		accountID,
 		categoryID,
+		time.Now(),
 	)
 	if err != nil {
 		return nil, err
@@ -58,6 +66,48 @@ func (f *operationFacade) CreateOperation(
 		return nil, err
 	}
 
+	if err := f.syncAggregates(nil, context.operation); err != nil {
+		return nil, err
+	}
+
+	return context.operation, nil
+}
+
+func (f *operationFacade) CreateOperationWithID(
+	id domain.ID,
+	typ domain.OperationType,
+	accountID domain.ID,
+	categoryID domain.ID,
+	amount int64,
+	date time.Time,
+	description string,
+	updatedAt time.Time,
+) (*domain.Operation, error) {
+	context, err := f.buildOperationContext(
+		func() (*domain.Operation, error) {
+			return f.factory.Rebuild(id, typ, accountID, categoryID, amount, date, description)
+		},
+		accountID,
+		categoryID,
+		updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.applyBalance(context.account, context.operation); err != nil {
+		return nil, err
+	}
+
+	if err := f.operations.Create(context.operation); err != nil {
+		_ = f.revertBalanceWithAccount(context.account, context.operation)
+		return nil, err
+	}
+
+	if err := f.syncAggregates(nil, context.operation); err != nil {
+		return nil, err
+	}
+
 	return context.operation, nil
 }
 
@@ -69,6 +119,7 @@ func (f *operationFacade) CreateOperationWithoutBalance(
 	amount int64,
 	date time.Time,
 	description string,
+	updatedAt time.Time,
 ) (*domain.Operation, error) {
 	context, err := f.buildOperationContext(
 		func() (*domain.Operation, error) {
@@ -76,6 +127,7 @@ func (f *operationFacade) CreateOperationWithoutBalance(
 		},
 		accountID,
 		categoryID,
+		updatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -85,6 +137,10 @@ func (f *operationFacade) CreateOperationWithoutBalance(
 		return nil, err
 	}
 
+	if err := f.syncAggregates(nil, context.operation); err != nil {
+		return nil, err
+	}
+
 	return context.operation, nil
 }
 
@@ -96,6 +152,19 @@ func (f *operationFacade) UpdateOperation(
 	amount int64,
 	date time.Time,
 	description string,
+) (*domain.Operation, error) {
+	return f.ReplaceOperation(id, typ, accountID, categoryID, amount, date, description, time.Now())
+}
+
+func (f *operationFacade) ReplaceOperation(
+	id domain.ID,
+	typ domain.OperationType,
+	accountID domain.ID,
+	categoryID domain.ID,
+	amount int64,
+	date time.Time,
+	description string,
+	updatedAt time.Time,
 ) (*domain.Operation, error) {
 	existing, err := f.operations.Get(id)
 	if err != nil {
@@ -108,6 +177,7 @@ func (f *operationFacade) UpdateOperation(
 		},
 		accountID,
 		categoryID,
+		updatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -122,6 +192,48 @@ func (f *operationFacade) UpdateOperation(
 		return nil, err
 	}
 
+	if err := f.syncAggregates(existing, context.operation); err != nil {
+		return nil, err
+	}
+
+	return context.operation, nil
+}
+
+func (f *operationFacade) UpdateOperationWithoutBalance(
+	id domain.ID,
+	typ domain.OperationType,
+	accountID domain.ID,
+	categoryID domain.ID,
+	amount int64,
+	date time.Time,
+	description string,
+	updatedAt time.Time,
+) (*domain.Operation, error) {
+	existing, err := f.operations.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	context, err := f.buildOperationContext(
+		func() (*domain.Operation, error) {
+			return f.factory.Rebuild(id, typ, accountID, categoryID, amount, date, description)
+		},
+		accountID,
+		categoryID,
+		updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.operations.Update(context.operation); err != nil {
+		return nil, err
+	}
+
+	if err := f.syncAggregates(existing, context.operation); err != nil {
+		return nil, err
+	}
+
 	return context.operation, nil
 }
 
@@ -144,9 +256,58 @@ func (f *operationFacade) DeleteOperation(id domain.ID) error {
 		return err
 	}
 
+	return f.syncAggregates(existing, nil)
+}
+
+func (f *operationFacade) DeleteOperationWithoutBalance(id domain.ID) error {
+	if id == "" {
+		return domain.ErrInvalidOperation
+	}
+
+	existing, err := f.operations.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err := f.operations.Delete(id); err != nil {
+		return err
+	}
+
+	return f.syncAggregates(existing, nil)
+}
+
+func (f *operationFacade) syncAggregates(removed, added *domain.Operation) error {
+	var err error
+	if removed != nil {
+		err = f.aggregates.Remove(removed)
+	}
+	if err == nil && added != nil {
+		err = f.aggregates.Add(added)
+	}
+	if err == nil {
+		return nil
+	}
+
+	for _, op := range []*domain.Operation{removed, added} {
+		if op == nil {
+			continue
+		}
+		if err := f.rebuildAggregateDay(op.Date()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (f *operationFacade) rebuildAggregateDay(at time.Time) error {
+	filter := query.NewOperationFilter().Between(at.Add(-24*time.Hour), at.Add(24*time.Hour))
+	operations, err := f.operations.ListByFilter(filter)
+	if err != nil {
+		return err
+	}
+	return f.aggregates.Rebuild(at, operations)
+}
+
 type operationContext struct {
 	operation *domain.Operation
 	account   *domain.BankAccount
@@ -157,11 +318,16 @@ func (f *operationFacade) buildOperationContext(
 	builder func() (*domain.Operation, error),
 	accountID domain.ID,
 	categoryID domain.ID,
+	updatedAt time.Time,
 ) (*operationContext, error) {
 	op, err := builder()
 	if err != nil {
 		return nil, err
 	}
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+	op.Touch(updatedAt)
 
 	if accountID != "" && op.BankAccountID() != accountID {
 		return nil, domain.ErrInvalidOperation
@@ -189,12 +355,17 @@ func (f *operationFacade) buildOperationContext(
 }
 
 func (f *operationFacade) applyBalance(account *domain.BankAccount, operation *domain.Operation) error {
-	if err := account.ApplyOperation(operation); err != nil {
+	kind, err := f.accountKind(account.ID())
+	if err != nil {
+		return err
+	}
+
+	if err := account.ApplyOperationAs(kind, operation); err != nil {
 		return err
 	}
 
 	if err := f.accounts.Update(account); err != nil {
-		_ = account.RevertOperation(operation)
+		_ = account.RevertOperationAs(kind, operation)
 		return err
 	}
 
@@ -218,18 +389,38 @@ func (f *operationFacade) revertBalance(operation *domain.Operation) error {
 }
 
 func (f *operationFacade) revertBalanceWithAccount(account *domain.BankAccount, operation *domain.Operation) error {
-	if err := account.RevertOperation(operation); err != nil {
+	kind, err := f.accountKind(account.ID())
+	if err != nil {
+		return err
+	}
+
+	if err := account.RevertOperationAs(kind, operation); err != nil {
 		return err
 	}
 
 	if err := f.accounts.Update(account); err != nil {
-		_ = account.ApplyOperation(operation)
+		_ = account.ApplyOperationAs(kind, operation)
 		return err
 	}
 
 	return nil
 }
 
+func (f *operationFacade) accountKind(id domain.ID) (domain.AccountKind, error) {
+	if f.profiles == nil {
+		return domain.AccountKindAsset, nil
+	}
+
+	profile, err := f.profiles.Get(id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.AccountKindAsset, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return profile.Kind(), nil
+}
+
 func (f *operationFacade) updateBalanceForMove(oldOp, newOp *domain.Operation) error {
 	if err := f.revertBalance(oldOp); err != nil {
 		return err
@@ -249,16 +440,35 @@ func (f *operationFacade) ListOperationsWithFilter(filter query.OperationFilter)
 		return nil, domain.ErrInvalidOperation
 	}
 
-	switch typ := filter.Type(); typ {
-	case "":
-	case domain.OperationTypeIncome, domain.OperationTypeExpense:
-	default:
-		return nil, domain.ErrInvalidOperation
+	for _, typ := range filter.Types().Values() {
+		switch typ {
+		case domain.OperationTypeIncome, domain.OperationTypeExpense:
+		default:
+			return nil, domain.ErrInvalidOperation
+		}
 	}
 
 	return f.operations.ListByFilter(filter)
 }
 
+func (f *operationFacade) AggregateOperations(filter query.OperationFilter) ([]repository.AggregateCell, error) {
+	return f.aggregates.Cells(filter)
+}
+
+func (f *operationFacade) ParseOperationQuery(expression string) (query.OperationFilter, error) {
+	accounts, err := f.accounts.List()
+	if err != nil {
+		return query.OperationFilter{}, err
+	}
+
+	categories, err := f.categories.ListAll()
+	if err != nil {
+		return query.OperationFilter{}, err
+	}
+
+	return query.ParseOperationFilter(expression, query.NewNameIndex(accounts, categories), time.Now())
+}
+
 func (f *operationFacade) GetOperation(id domain.ID) (*domain.Operation, error) {
 	if id == "" {
 		return nil, domain.ErrInvalidOperation
@@ -0,0 +1,246 @@
+package facade
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	domainfactory "kpo-hw-2/internal/domain/factory"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+	"kpo-hw-2/internal/infrastructure/id"
+	"kpo-hw-2/internal/infrastructure/repository/memory"
+)
+
+type facadeFixture struct {
+	accounts   AccountFacade
+	categories CategoryFacade
+	operations OperationFacade
+}
+
+func newFacadeFixture() facadeFixture {
+	ids := id.NewULIDGenerator()
+	accountRepo := memory.NewAccountRepository()
+	categoryRepo := memory.NewCategoryRepository()
+	profileRepo := memory.NewAccountProfileRepository()
+
+	return facadeFixture{
+		accounts:   NewAccountFacade(domainfactory.NewBankAccountFactory(ids), accountRepo, profileRepo),
+		categories: NewCategoryFacade(domainfactory.NewCategoryFactory(ids), categoryRepo),
+		operations: NewOperationFacade(
+			domainfactory.NewOperationFactory(ids),
+			memory.NewOperationRepository(),
+			accountRepo,
+			categoryRepo,
+			profileRepo,
+			memory.NewOperationAggregateRepository(),
+		),
+	}
+}
+
+func TestCreateOperationRespectsAccountKind(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        domain.AccountKind
+		balance     int64
+		amount      int64
+		wantBalance int64
+		wantErr     error
+	}{
+		{name: "asset within balance", kind: domain.AccountKindAsset, balance: 1000, amount: 400, wantBalance: 600},
+		{name: "asset overdraft", kind: domain.AccountKindAsset, balance: 1000, amount: 1500, wantBalance: 1000, wantErr: domain.ErrInsufficientFunds},
+		{name: "credit card spending", kind: domain.AccountKindCredit, balance: 0, amount: 1500, wantBalance: -1500},
+		{name: "credit card with debt", kind: domain.AccountKindCredit, balance: -200, amount: 300, wantBalance: -500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFacadeFixture()
+			account, err := f.accounts.CreateAccount("Счёт")
+			if err != nil {
+				t.Fatalf("create account: %v", err)
+			}
+			if _, err := f.accounts.SetAccountProfile(account.ID(), tt.kind, ""); err != nil {
+				t.Fatalf("set profile: %v", err)
+			}
+			if _, err := f.accounts.UpdateAccount(account.ID(), account.Name(), tt.balance); err != nil {
+				t.Fatalf("update account: %v", err)
+			}
+			category, err := f.categories.CreateCategory("Еда", domain.OperationTypeExpense)
+			if err != nil {
+				t.Fatalf("create category: %v", err)
+			}
+
+			_, err = f.operations.CreateOperation(domain.OperationTypeExpense, account.ID(), category.ID(), tt.amount, time.Now(), "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateOperation error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := f.accounts.GetAccount(account.ID())
+			if err != nil {
+				t.Fatalf("get account: %v", err)
+			}
+			if stored.Balance() != tt.wantBalance {
+				t.Fatalf("balance = %d, want %d", stored.Balance(), tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestAccountFacadeRejectsNegativeAssetBalance(t *testing.T) {
+	f := newFacadeFixture()
+	account, err := f.accounts.CreateAccount("Карта")
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+
+	if _, err := f.accounts.UpdateAccount(account.ID(), account.Name(), -100); !errors.Is(err, domain.ErrInvalidBankAccount) {
+		t.Fatalf("negative asset balance error = %v, want %v", err, domain.ErrInvalidBankAccount)
+	}
+
+	if _, err := f.accounts.SetAccountProfile(account.ID(), domain.AccountKindCredit, ""); err != nil {
+		t.Fatalf("set credit profile: %v", err)
+	}
+	if _, err := f.accounts.UpdateAccount(account.ID(), account.Name(), -100); err != nil {
+		t.Fatalf("negative credit balance: %v", err)
+	}
+
+	if _, err := f.accounts.SetAccountProfile(account.ID(), domain.AccountKindAsset, ""); !errors.Is(err, domain.ErrInvalidBankAccount) {
+		t.Fatalf("switch indebted card to asset error = %v, want %v", err, domain.ErrInvalidBankAccount)
+	}
+}
+
+type flakyAggregates struct {
+	repository.OperationAggregateRepository
+	failAdd    bool
+	failRemove bool
+}
+
+func (a *flakyAggregates) Add(operation *domain.Operation) error {
+	if a.failAdd {
+		return errAggregateFailure
+	}
+	return a.OperationAggregateRepository.Add(operation)
+}
+
+func (a *flakyAggregates) Remove(operation *domain.Operation) error {
+	if a.failRemove {
+		return errAggregateFailure
+	}
+	return a.OperationAggregateRepository.Remove(operation)
+}
+
+var errAggregateFailure = errors.New("aggregate failure")
+
+func TestOperationAggregatesStayConsistentOnFailure(t *testing.T) {
+	day := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		failAdd    bool
+		failRemove bool
+	}{
+		{name: "healthy"},
+		{name: "add fails", failAdd: true},
+		{name: "remove fails", failRemove: true},
+		{name: "both fail", failAdd: true, failRemove: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := id.NewULIDGenerator()
+			accountRepo := memory.NewAccountRepository()
+			categoryRepo := memory.NewCategoryRepository()
+			operationRepo := memory.NewOperationRepository()
+			aggregates := &flakyAggregates{OperationAggregateRepository: memory.NewOperationAggregateRepository()}
+
+			accounts := NewAccountFacade(domainfactory.NewBankAccountFactory(ids), accountRepo, memory.NewAccountProfileRepository())
+			categories := NewCategoryFacade(domainfactory.NewCategoryFactory(ids), categoryRepo)
+			operations := NewOperationFacade(
+				domainfactory.NewOperationFactory(ids),
+				operationRepo,
+				accountRepo,
+				categoryRepo,
+				nil,
+				aggregates,
+			)
+
+			account, err := accounts.CreateAccountWithID("A", "Счёт", 10000)
+			if err != nil {
+				t.Fatalf("create account: %v", err)
+			}
+			food, err := categories.CreateCategoryWithID("FOOD", "Еда", domain.OperationTypeExpense)
+			if err != nil {
+				t.Fatalf("create category: %v", err)
+			}
+			cafe, err := categories.CreateCategoryWithID("CAFE", "Кафе", domain.OperationTypeExpense)
+			if err != nil {
+				t.Fatalf("create category: %v", err)
+			}
+
+			keep, err := operations.CreateOperation(domain.OperationTypeExpense, account.ID(), food.ID(), 100, day, "")
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			aggregates.failAdd, aggregates.failRemove = tt.failAdd, tt.failRemove
+
+			moved, err := operations.CreateOperation(domain.OperationTypeExpense, account.ID(), food.ID(), 200, day, "")
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			assertAggregatesMatch(t, "after create", operationRepo, aggregates)
+
+			if _, err := operations.UpdateOperation(moved.ID(), domain.OperationTypeExpense, account.ID(), cafe.ID(), 300, day.AddDate(0, 0, 1), ""); err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			assertAggregatesMatch(t, "after update", operationRepo, aggregates)
+
+			if err := operations.DeleteOperation(keep.ID()); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			assertAggregatesMatch(t, "after delete", operationRepo, aggregates)
+		})
+	}
+}
+
+func assertAggregatesMatch(
+	t *testing.T,
+	stage string,
+	operations repository.OperationRepository,
+	aggregates repository.OperationAggregateRepository,
+) {
+	t.Helper()
+
+	stored, err := operations.ListByFilter(query.NewOperationFilter())
+	if err != nil {
+		t.Fatalf("%s: list operations: %v", stage, err)
+	}
+	want := make(map[string]int64)
+	for _, op := range stored {
+		want[aggregateCellKey(op.BankAccountID(), op.CategoryID(), op.Date())] += op.Amount()
+	}
+
+	cells, err := aggregates.Cells(query.NewOperationFilter())
+	if err != nil {
+		t.Fatalf("%s: cells: %v", stage, err)
+	}
+	got := make(map[string]int64)
+	for _, cell := range cells {
+		got[aggregateCellKey(cell.AccountID, cell.CategoryID, cell.Day)] += cell.Amount
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: cells = %v, want %v", stage, got, want)
+	}
+	for key, amount := range want {
+		if got[key] != amount {
+			t.Fatalf("%s: cell %s = %d, want %d", stage, key, got[key], amount)
+		}
+	}
+}
+
+func aggregateCellKey(account, category domain.ID, at time.Time) string {
+	return account.String() + "/" + category.String() + "/" + at.UTC().Format("2006-01-02")
+}
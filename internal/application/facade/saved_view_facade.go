@@ -0,0 +1,17 @@
+package facade
+
+import (
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+type SavedViewFacade interface {
+	CreateView(name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error)
+	CreateViewFromQuery(name, expression string) (*query.SavedView, error)
+	CreateViewWithID(id domain.ID, name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error)
+	UpdateView(id domain.ID, name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error)
+	DeleteView(id domain.ID) error
+	ListViews() ([]*query.SavedView, error)
+	GetView(id domain.ID) (*query.SavedView, error)
+	ResolveView(id domain.ID) (query.OperationFilter, error)
+}
@@ -0,0 +1,130 @@
+package facade
+
+import (
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	domainfactory "kpo-hw-2/internal/domain/factory"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type savedViewFacade struct {
+	factory    domainfactory.SavedViewFactory
+	views      repository.SavedViewRepository
+	accounts   repository.AccountRepository
+	categories repository.CategoryRepository
+}
+
+func NewSavedViewFacade(
+	viewFactory domainfactory.SavedViewFactory,
+	viewRepo repository.SavedViewRepository,
+	accountRepo repository.AccountRepository,
+	categoryRepo repository.CategoryRepository,
+) SavedViewFacade {
+	return &savedViewFacade{
+		factory:    viewFactory,
+		views:      viewRepo,
+		accounts:   accountRepo,
+		categories: categoryRepo,
+	}
+}
+
+func (f *savedViewFacade) CreateView(name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error) {
+	view, err := f.factory.Create(name, filter, period)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.views.Create(view); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (f *savedViewFacade) CreateViewFromQuery(name, expression string) (*query.SavedView, error) {
+	accounts, err := f.accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := f.categories.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, period, err := query.ParseOperationQuery(expression, query.NewNameIndex(accounts, categories), time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.CreateView(name, filter, period)
+}
+
+func (f *savedViewFacade) CreateViewWithID(
+	id domain.ID,
+	name string,
+	filter query.OperationFilter,
+	period query.PeriodSpec,
+) (*query.SavedView, error) {
+	view, err := f.factory.Rebuild(id, name, filter, period)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.views.Create(view); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (f *savedViewFacade) UpdateView(
+	id domain.ID,
+	name string,
+	filter query.OperationFilter,
+	period query.PeriodSpec,
+) (*query.SavedView, error) {
+	view, err := f.factory.Rebuild(id, name, filter, period)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.views.Update(view); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (f *savedViewFacade) DeleteView(id domain.ID) error {
+	if id == "" {
+		return domain.ErrInvalidSavedView
+	}
+
+	return f.views.Delete(id)
+}
+
+func (f *savedViewFacade) ListViews() ([]*query.SavedView, error) {
+	return f.views.List()
+}
+
+func (f *savedViewFacade) GetView(id domain.ID) (*query.SavedView, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidSavedView
+	}
+
+	return f.views.Get(id)
+}
+
+func (f *savedViewFacade) ResolveView(id domain.ID) (query.OperationFilter, error) {
+	view, err := f.GetView(id)
+	if err != nil {
+		return query.OperationFilter{}, err
+	}
+
+	return view.Filter(time.Now()), nil
+}
+
+var _ SavedViewFacade = (*savedViewFacade)(nil)
@@ -0,0 +1,7 @@
+package facade
+
+type SubscriptionFacade interface {
+	IgnoredSubscriptions() ([]string, error)
+	IgnoreSubscription(key string) error
+	RestoreSubscription(key string) error
+}
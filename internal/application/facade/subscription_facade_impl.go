@@ -0,0 +1,33 @@
+package facade
+
+import (
+	"strings"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type subscriptionFacade struct {
+	ignored repository.IgnoredPatternRepository
+}
+
+func NewSubscriptionFacade(ignoredRepo repository.IgnoredPatternRepository) SubscriptionFacade {
+	return &subscriptionFacade{
+		ignored: ignoredRepo,
+	}
+}
+
+func (f *subscriptionFacade) IgnoredSubscriptions() ([]string, error) {
+	return f.ignored.List()
+}
+
+func (f *subscriptionFacade) IgnoreSubscription(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return domain.ErrNotFound
+	}
+	return f.ignored.Add(key)
+}
+
+func (f *subscriptionFacade) RestoreSubscription(key string) error {
+	return f.ignored.Remove(key)
+}
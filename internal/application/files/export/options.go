@@ -0,0 +1,31 @@
+package export
+
+import "kpo-hw-2/internal/domain/query"
+
+type Entities struct {
+	Accounts   bool
+	Categories bool
+	Operations bool
+	Views      bool
+}
+
+func AllEntities() Entities {
+	return Entities{Accounts: true, Categories: true, Operations: true, Views: true}
+}
+
+func (e Entities) any() bool {
+	return e.Accounts || e.Categories || e.Operations || e.Views
+}
+
+type Options struct {
+	Filter   query.OperationFilter
+	Entities Entities
+}
+
+func DefaultOptions() Options {
+	return Options{Filter: query.NewOperationFilter(), Entities: AllEntities()}
+}
+
+func (o Options) Partial() bool {
+	return !o.Filter.IsEmpty()
+}
@@ -3,11 +3,10 @@ package export
 import (
 	"errors"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"kpo-hw-2/internal/application/files"
+	"kpo-hw-2/internal/domain"
 	"kpo-hw-2/internal/domain/query"
 	"kpo-hw-2/internal/domain/repository"
 )
@@ -16,12 +15,17 @@ var (
 	ErrUnknownFormat = errors.New("export: unknown format")
 	ErrInvalidWriter = errors.New("export: invalid writer")
 	ErrInvalidPath   = errors.New("export: invalid destination path")
+	ErrNoEntities    = errors.New("export: no entities selected")
 )
 
+const operationPageSize = 1000
+
 type Service struct {
 	accounts   repository.AccountRepository
+	profiles   repository.AccountProfileRepository
 	categories repository.CategoryRepository
 	operations repository.OperationRepository
+	views      repository.SavedViewRepository
 
 	exporters map[string]Exporter
 	order     []files.Format
@@ -29,8 +33,10 @@ type Service struct {
 
 func NewService(
 	accountRepo repository.AccountRepository,
+	profileRepo repository.AccountProfileRepository,
 	categoryRepo repository.CategoryRepository,
 	operationRepo repository.OperationRepository,
+	viewRepo repository.SavedViewRepository,
 	exporters []Exporter,
 ) *Service {
 	registry := make(map[string]Exporter)
@@ -53,8 +59,10 @@ func NewService(
 
 	return &Service{
 		accounts:   accountRepo,
+		profiles:   profileRepo,
 		categories: categoryRepo,
 		operations: operationRepo,
+		views:      viewRepo,
 		exporters:  registry,
 		order:      order,
 	}
@@ -74,7 +82,7 @@ func (s *Service) FormatFor(key string) (files.Format, bool) {
 	return exp.Format(), true
 }
 
-func (s *Service) Export(formatKey string, writer io.Writer) error {
+func (s *Service) Export(formatKey string, writer io.Writer, options Options) error {
 	if writer == nil {
 		return ErrInvalidWriter
 	}
@@ -83,51 +91,87 @@ func (s *Service) Export(formatKey string, writer io.Writer) error {
 	if !ok {
 		return ErrUnknownFormat
 	}
+	if !options.Entities.any() {
+		return ErrNoEntities
+	}
 
-	visitor, err := exp.NewVisitor(writer)
+	scope, err := s.newExportScope(options)
 	if err != nil {
 		return err
 	}
 
-	if err := s.exportAccounts(visitor); err != nil {
+	visitor, err := exp.NewVisitor(writer)
+	if err != nil {
 		return err
 	}
-	if err := s.exportCategories(visitor); err != nil {
-		return err
+
+	if options.Entities.Accounts {
+		if err := s.exportAccounts(visitor, scope); err != nil {
+			return err
+		}
 	}
-	if err := s.exportOperations(visitor); err != nil {
-		return err
+	if options.Entities.Categories {
+		if err := s.exportCategories(visitor, scope); err != nil {
+			return err
+		}
+	}
+	if options.Entities.Operations {
+		if err := s.eachOperation(options.Filter, visitor.VisitOperation); err != nil {
+			return err
+		}
+	}
+	if options.Entities.Views {
+		if err := s.exportViews(visitor, scope); err != nil {
+			return err
+		}
 	}
 
 	return visitor.Finalize()
 }
 
-func (s *Service) ExportToPath(formatKey, path string) (err error) {
+func (s *Service) ExportToPath(formatKey, path string, options Options) error {
 	if strings.TrimSpace(path) == "" {
 		return ErrInvalidPath
 	}
-
-	if dir := filepath.Dir(path); dir != "" && dir != "." {
-		if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
-			return mkErr
-		}
+	if _, ok := s.exporters[formatKey]; !ok {
+		return ErrUnknownFormat
+	}
+	if !options.Entities.any() {
+		return ErrNoEntities
 	}
 
-	file, createErr := os.Create(path)
-	if createErr != nil {
-		return createErr
+	return files.WriteFile(path, func(writer io.Writer) error {
+		return s.Export(formatKey, writer, options)
+	})
+}
+
+func (s *Service) eachOperation(filter query.OperationFilter, visit func(*domain.Operation) error) error {
+	if s.operations == nil {
+		return nil
 	}
-	defer func() {
-		if cerr := file.Close(); err == nil {
-			err = cerr
-		}
-	}()
 
-	err = s.Export(formatKey, file)
-	return err
+	var after *domain.Operation
+	for {
+		page, err := s.operations.ListPage(filter, after, operationPageSize)
+		if err != nil {
+			return err
+		}
+		for _, operation := range page {
+			if operation == nil {
+				continue
+			}
+			if err := visit(operation); err != nil {
+				return err
+			}
+		}
+		if len(page) < operationPageSize {
+			return nil
+		}
+		after = page[len(page)-1]
+	}
 }
 
-func (s *Service) exportAccounts(visitor Visitor) error {
+func (s *Service) exportAccounts(visitor Visitor, scope exportScope) error {
 	if s.accounts == nil {
 		return nil
 	}
@@ -137,17 +181,32 @@ func (s *Service) exportAccounts(visitor Visitor) error {
 		return err
 	}
 	for _, account := range accounts {
-		if account == nil {
+		if account == nil || !scope.hasAccount(account.ID()) {
 			continue
 		}
-		if err := visitor.VisitBankAccount(account); err != nil {
+		profile, err := s.accountProfile(account.ID())
+		if err != nil {
+			return err
+		}
+		if err := visitor.VisitBankAccount(account, profile); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *Service) exportCategories(visitor Visitor) error {
+func (s *Service) accountProfile(id domain.ID) (*domain.AccountProfile, error) {
+	if s.profiles == nil {
+		return domain.DefaultAccountProfile(id), nil
+	}
+	profile, err := s.profiles.Get(id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultAccountProfile(id), nil
+	}
+	return profile, err
+}
+
+func (s *Service) exportCategories(visitor Visitor, scope exportScope) error {
 	if s.categories == nil {
 		return nil
 	}
@@ -157,7 +216,7 @@ func (s *Service) exportCategories(visitor Visitor) error {
 		return err
 	}
 	for _, category := range categories {
-		if category == nil {
+		if category == nil || !scope.hasCategory(category.ID()) {
 			continue
 		}
 		if err := visitor.VisitCategory(category); err != nil {
@@ -167,22 +226,99 @@ func (s *Service) exportCategories(visitor Visitor) error {
 	return nil
 }
 
-func (s *Service) exportOperations(visitor Visitor) error {
-	if s.operations == nil {
+func (s *Service) exportViews(visitor Visitor, scope exportScope) error {
+	if s.views == nil {
 		return nil
 	}
 
-	operations, err := s.operations.ListByFilter(query.NewOperationFilter())
+	views, err := s.views.List()
 	if err != nil {
 		return err
 	}
-	for _, operation := range operations {
-		if operation == nil {
+	for _, view := range views {
+		if view == nil || !scope.coversView(view) {
 			continue
 		}
-		if err := visitor.VisitOperation(operation); err != nil {
+		if err := visitor.VisitSavedView(view); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+type exportScope struct {
+	partial    bool
+	accounts   map[domain.ID]struct{}
+	categories map[domain.ID]struct{}
+}
+
+func (s *Service) newExportScope(options Options) (exportScope, error) {
+	if !options.Partial() {
+		return exportScope{}, nil
+	}
+
+	scope := exportScope{
+		partial:    true,
+		accounts:   make(map[domain.ID]struct{}),
+		categories: make(map[domain.ID]struct{}),
+	}
+	err := s.eachOperation(options.Filter, func(operation *domain.Operation) error {
+		scope.accounts[operation.BankAccountID()] = struct{}{}
+		scope.categories[operation.CategoryID()] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return exportScope{}, err
+	}
+
+	if accounts := options.Filter.Accounts(); !accounts.IsExclude() {
+		for _, id := range accounts.Values() {
+			scope.accounts[id] = struct{}{}
+		}
+	}
+	if categories := options.Filter.Categories(); !categories.IsExclude() {
+		for _, id := range categories.Values() {
+			scope.categories[id] = struct{}{}
+		}
+	}
+
+	return scope, nil
+}
+
+func (s exportScope) hasAccount(id domain.ID) bool {
+	if !s.partial {
+		return true
+	}
+	_, ok := s.accounts[id]
+	return ok
+}
+
+func (s exportScope) hasCategory(id domain.ID) bool {
+	if !s.partial {
+		return true
+	}
+	_, ok := s.categories[id]
+	return ok
+}
+
+func (s exportScope) coversView(view *query.SavedView) bool {
+	if !s.partial {
+		return true
+	}
+	criteria := view.Criteria()
+	if accounts := criteria.Accounts(); !accounts.IsExclude() {
+		for _, id := range accounts.Values() {
+			if !s.hasAccount(id) {
+				return false
+			}
+		}
+	}
+	if categories := criteria.Categories(); !categories.IsExclude() {
+		for _, id := range categories.Values() {
+			if !s.hasCategory(id) {
+				return false
+			}
+		}
+	}
+	return true
+}
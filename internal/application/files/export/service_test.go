@@ -0,0 +1,224 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/application/files"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/infrastructure/repository/memory"
+)
+
+type recordingVisitor struct {
+	accounts   []domain.ID
+	categories []domain.ID
+	operations []domain.ID
+	views      []domain.ID
+}
+
+func (v *recordingVisitor) VisitBankAccount(account *domain.BankAccount, _ *domain.AccountProfile) error {
+	v.accounts = append(v.accounts, account.ID())
+	return nil
+}
+
+func (v *recordingVisitor) VisitCategory(category *domain.Category) error {
+	v.categories = append(v.categories, category.ID())
+	return nil
+}
+
+func (v *recordingVisitor) VisitOperation(operation *domain.Operation) error {
+	v.operations = append(v.operations, operation.ID())
+	return nil
+}
+
+func (v *recordingVisitor) VisitSavedView(view *query.SavedView) error {
+	v.views = append(v.views, view.ID())
+	return nil
+}
+
+func (v *recordingVisitor) Finalize() error { return nil }
+
+type recordingExporter struct {
+	visitor *recordingVisitor
+}
+
+func (e *recordingExporter) Format() files.Format {
+	return files.Format{Key: "record", Title: "record"}
+}
+
+func (e *recordingExporter) NewVisitor(io.Writer) (Visitor, error) {
+	e.visitor = &recordingVisitor{}
+	return e.visitor, nil
+}
+
+type exportFixture struct {
+	service    *Service
+	exporter   *recordingExporter
+	operations []domain.ID
+}
+
+func newExportFixture(t *testing.T, count int) exportFixture {
+	t.Helper()
+
+	accounts := memory.NewAccountRepository()
+	categories := memory.NewCategoryRepository()
+	operations := memory.NewOperationRepository()
+	views := memory.NewSavedViewRepository()
+
+	for _, id := range []domain.ID{"card", "cash"} {
+		account, err := domain.NewBankAccount(id, string(id), 0)
+		if err != nil {
+			t.Fatalf("NewBankAccount: %v", err)
+		}
+		if err := accounts.Create(account); err != nil {
+			t.Fatalf("create account: %v", err)
+		}
+	}
+	for _, id := range []domain.ID{"food", "rent"} {
+		category, err := domain.NewCategory(id, domain.OperationTypeExpense, string(id))
+		if err != nil {
+			t.Fatalf("NewCategory: %v", err)
+		}
+		if err := categories.Create(category); err != nil {
+			t.Fatalf("create category: %v", err)
+		}
+	}
+
+	savedViews := []struct {
+		id     domain.ID
+		filter query.OperationFilter
+	}{
+		{id: "card-view", filter: query.NewOperationFilter().ForAccount("card")},
+		{id: "cash-view", filter: query.NewOperationFilter().ForAccount("cash")},
+		{id: "not-cash-view", filter: query.NewOperationFilter().ExcludeAccounts("cash").ExcludeCategories("rent")},
+	}
+	for _, v := range savedViews {
+		view, err := query.NewSavedView(v.id, string(v.id), v.filter, query.PeriodSpec{})
+		if err != nil {
+			t.Fatalf("NewSavedView: %v", err)
+		}
+		if err := views.Create(view); err != nil {
+			t.Fatalf("create view: %v", err)
+		}
+	}
+
+	f := exportFixture{exporter: &recordingExporter{}}
+	day := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		id := domain.ID(fmt.Sprintf("op%05d", i))
+		op, err := domain.NewOperation(id, domain.OperationTypeExpense, "card", "food", int64(i+1), day.AddDate(0, 0, i%40), "")
+		if err != nil {
+			t.Fatalf("NewOperation: %v", err)
+		}
+		if err := operations.Create(op); err != nil {
+			t.Fatalf("create operation: %v", err)
+		}
+		f.operations = append(f.operations, id)
+	}
+
+	f.service = NewService(accounts, nil, categories, operations, views, []Exporter{f.exporter})
+	return f
+}
+
+func TestExportPagesAllOperations(t *testing.T) {
+	for _, count := range []int{0, 1, operationPageSize, operationPageSize + 1, 2*operationPageSize + 37} {
+		t.Run(fmt.Sprint(count), func(t *testing.T) {
+			f := newExportFixture(t, count)
+			if err := f.service.Export("record", io.Discard, DefaultOptions()); err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+
+			got := f.exporter.visitor.operations
+			if len(got) != count {
+				t.Fatalf("exported %d operations, want %d", len(got), count)
+			}
+			seen := make(map[domain.ID]struct{}, len(got))
+			for _, id := range got {
+				if _, ok := seen[id]; ok {
+					t.Fatalf("operation %s exported twice", id)
+				}
+				seen[id] = struct{}{}
+			}
+			if len(f.exporter.visitor.accounts) != 2 || len(f.exporter.visitor.categories) != 2 {
+				t.Fatalf("exported %d accounts, %d categories, want 2, 2",
+					len(f.exporter.visitor.accounts), len(f.exporter.visitor.categories))
+			}
+		})
+	}
+}
+
+func TestPartialExportScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     query.OperationFilter
+		entities   Entities
+		accounts   []domain.ID
+		categories []domain.ID
+		operations int
+		views      []domain.ID
+		wantErr    error
+	}{
+		{
+			name:       "amount filter keeps referenced entities",
+			filter:     query.NewOperationFilter().MaxAmount(1500),
+			entities:   AllEntities(),
+			accounts:   []domain.ID{"card"},
+			categories: []domain.ID{"food"},
+			operations: 1500,
+			views:      []domain.ID{"card-view", "not-cash-view"},
+		},
+		{
+			name:       "selected account without operations",
+			filter:     query.NewOperationFilter().ForAccount("cash"),
+			entities:   AllEntities(),
+			accounts:   []domain.ID{"cash"},
+			categories: nil,
+			operations: 0,
+			views:      []domain.ID{"cash-view", "not-cash-view"},
+		},
+		{
+			name:       "operations only",
+			filter:     query.NewOperationFilter().MinAmount(2001),
+			entities:   Entities{Operations: true},
+			operations: 2500 - 2000,
+		},
+		{
+			name:    "nothing selected",
+			filter:  query.NewOperationFilter(),
+			wantErr: ErrNoEntities,
+		},
+	}
+
+	f := newExportFixture(t, 2500)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.exporter.visitor = nil
+			err := f.service.Export("record", io.Discard, Options{Filter: tt.filter, Entities: tt.entities})
+			if err != tt.wantErr {
+				t.Fatalf("Export error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			visitor := f.exporter.visitor
+			if !slices.Equal(visitor.accounts, tt.accounts) {
+				t.Errorf("accounts = %v, want %v", visitor.accounts, tt.accounts)
+			}
+			if !slices.Equal(visitor.categories, tt.categories) {
+				t.Errorf("categories = %v, want %v", visitor.categories, tt.categories)
+			}
+			if len(visitor.operations) != tt.operations {
+				t.Errorf("operations = %d, want %d", len(visitor.operations), tt.operations)
+			}
+			slices.Sort(visitor.views)
+			if !slices.Equal(visitor.views, tt.views) {
+				t.Errorf("views = %v, want %v", visitor.views, tt.views)
+			}
+		})
+	}
+}
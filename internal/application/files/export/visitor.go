@@ -1,10 +1,18 @@
 package export
 
-import "kpo-hw-2/internal/domain"
+import (
+	"errors"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+var ErrVisitOrder = errors.New("export: entities must be visited as accounts, categories, operations, views")
 
 type Visitor interface {
-	VisitBankAccount(*domain.BankAccount) error
+	VisitBankAccount(*domain.BankAccount, *domain.AccountProfile) error
 	VisitCategory(*domain.Category) error
 	VisitOperation(*domain.Operation) error
+	VisitSavedView(*query.SavedView) error
 	Finalize() error
 }
@@ -0,0 +1,220 @@
+package fileimport
+
+import (
+	"kpo-hw-2/internal/domain"
+)
+
+type BalanceCheck struct {
+	AccountID domain.ID
+	Name      string
+	Opening   int64
+	Imported  int64
+	Balance   int64
+	Stated    int64
+	HasStated bool
+}
+
+func (c BalanceCheck) Mismatch() bool {
+	return c.HasStated && c.Stated != c.Balance
+}
+
+type balanceEntry struct {
+	check   BalanceCheck
+	account *domain.BankAccount
+	kind    domain.AccountKind
+	derived bool
+	steps   []int64
+}
+
+type balanceLedger struct {
+	accounts func(domain.ID) (*domain.BankAccount, error)
+	kinds    func(domain.ID) domain.AccountKind
+	entries  map[domain.ID]*balanceEntry
+	order    []domain.ID
+}
+
+func newBalanceLedger(
+	accounts func(domain.ID) (*domain.BankAccount, error),
+	kinds func(domain.ID) domain.AccountKind,
+) *balanceLedger {
+	return &balanceLedger{
+		accounts: accounts,
+		kinds:    kinds,
+		entries:  make(map[domain.ID]*balanceEntry),
+	}
+}
+
+func (l *balanceLedger) kind(id domain.ID) domain.AccountKind {
+	if l.kinds == nil {
+		return domain.AccountKindAsset
+	}
+	return l.kinds(id)
+}
+
+func (l *balanceLedger) open(id domain.ID, name string, opening, stated int64) {
+	account, err := domain.NewBankAccount(id, name, opening)
+	if err != nil {
+		return
+	}
+	if _, exists := l.entries[id]; !exists {
+		l.order = append(l.order, id)
+	}
+	l.entries[id] = &balanceEntry{
+		check: BalanceCheck{
+			AccountID: id,
+			Name:      account.Name(),
+			Opening:   opening,
+			Balance:   opening,
+			Stated:    stated,
+			HasStated: true,
+		},
+		account: account,
+		kind:    l.kind(id),
+	}
+}
+
+func (l *balanceLedger) openStated(id domain.ID, name string, stated int64) {
+	l.open(id, name, 0, stated)
+	if entry, ok := l.entries[id]; ok {
+		entry.derived = true
+	}
+}
+
+func (l *balanceLedger) openImplied(id domain.ID, name string) {
+	l.open(id, name, 0, 0)
+	if entry, ok := l.entries[id]; ok {
+		entry.check.HasStated = false
+	}
+}
+
+func (l *balanceLedger) entry(id domain.ID) (*balanceEntry, error) {
+	if entry, ok := l.entries[id]; ok {
+		return entry, nil
+	}
+	if l.accounts == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	stored, err := l.accounts(id)
+	if err != nil {
+		return nil, err
+	}
+	account, err := domain.NewBankAccount(stored.ID(), stored.Name(), stored.Balance())
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &balanceEntry{
+		check: BalanceCheck{
+			AccountID: id,
+			Name:      stored.Name(),
+			Opening:   stored.Balance(),
+			Balance:   stored.Balance(),
+		},
+		account: account,
+		kind:    l.kind(id),
+	}
+	l.entries[id] = entry
+	l.order = append(l.order, id)
+	return entry, nil
+}
+
+func (l *balanceLedger) apply(op *domain.Operation) error {
+	entry, err := l.entry(op.BankAccountID())
+	if err != nil {
+		return err
+	}
+	if entry.derived {
+		return entry.step(op, 1)
+	}
+	if err := entry.account.ApplyOperationAs(entry.kind, op); err != nil {
+		return err
+	}
+	entry.sync()
+	return nil
+}
+
+func (l *balanceLedger) move(previous, next *domain.Operation) error {
+	from, err := l.entry(previous.BankAccountID())
+	if err != nil {
+		return err
+	}
+	if from.derived {
+		if err := from.step(previous, -1); err != nil {
+			return err
+		}
+		if err := l.apply(next); err != nil {
+			from.steps = from.steps[:len(from.steps)-1]
+			return err
+		}
+		return nil
+	}
+	if err := from.account.RevertOperationAs(from.kind, previous); err != nil {
+		return err
+	}
+	if err := l.apply(next); err != nil {
+		_ = from.account.ApplyOperationAs(from.kind, previous)
+		return err
+	}
+	from.sync()
+	return nil
+}
+
+func (l *balanceLedger) finish() {
+	for _, id := range l.order {
+		entry := l.entries[id]
+		if !entry.derived {
+			continue
+		}
+		var imported, lowest int64
+		for _, step := range entry.steps {
+			imported += step
+			if imported < lowest {
+				lowest = imported
+			}
+		}
+		opening := entry.check.Stated - imported
+		if !entry.kind.AllowsNegativeBalance() && opening+lowest < 0 {
+			opening = -lowest
+		}
+		entry.check.Opening = opening
+		entry.check.Imported = imported
+		entry.check.Balance = opening + imported
+	}
+}
+
+func (l *balanceLedger) opening(id domain.ID) int64 {
+	if entry, ok := l.entries[id]; ok {
+		return entry.check.Opening
+	}
+	return 0
+}
+
+func (l *balanceLedger) checks() []BalanceCheck {
+	out := make([]BalanceCheck, 0, len(l.order))
+	for _, id := range l.order {
+		entry := l.entries[id]
+		if entry.check.Imported == 0 && !entry.check.HasStated {
+			continue
+		}
+		out = append(out, entry.check)
+	}
+	return out
+}
+
+func (e *balanceEntry) step(op *domain.Operation, sign int64) error {
+	switch op.Type() {
+	case domain.OperationTypeIncome:
+		e.steps = append(e.steps, sign*op.Amount())
+	case domain.OperationTypeExpense:
+		e.steps = append(e.steps, -sign*op.Amount())
+	default:
+		return domain.ErrInvalidOperation
+	}
+	return nil
+}
+
+func (e *balanceEntry) sync() {
+	e.check.Balance = e.account.Balance()
+	e.check.Imported = e.check.Balance - e.check.Opening
+}
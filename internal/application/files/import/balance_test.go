@@ -0,0 +1,90 @@
+package fileimport
+
+import (
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+func TestBalanceLedgerDerivesOpening(t *testing.T) {
+	day := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	operation := func(typ domain.OperationType, amount int64) *domain.Operation {
+		op, err := domain.NewOperation(mustID(t), typ, "acc", "cat", amount, day, "")
+		if err != nil {
+			t.Fatalf("new operation: %v", err)
+		}
+		return op
+	}
+
+	cases := []struct {
+		name       string
+		kind       domain.AccountKind
+		stated     int64
+		operations []*domain.Operation
+		opening    int64
+		mismatch   bool
+	}{
+		{
+			name:   "income and expense",
+			kind:   domain.AccountKindAsset,
+			stated: 42000,
+			operations: []*domain.Operation{
+				operation(domain.OperationTypeIncome, 50000),
+				operation(domain.OperationTypeExpense, 8000),
+			},
+			opening: 0,
+		},
+		{
+			name:       "no operations",
+			kind:       domain.AccountKindAsset,
+			stated:     551,
+			operations: nil,
+			opening:    551,
+		},
+		{
+			name:   "asset cannot dip below zero",
+			kind:   domain.AccountKindAsset,
+			stated: 0,
+			operations: []*domain.Operation{
+				operation(domain.OperationTypeExpense, 300),
+				operation(domain.OperationTypeIncome, 100),
+			},
+			opening:  300,
+			mismatch: true,
+		},
+		{
+			name:   "credit opens in debt",
+			kind:   domain.AccountKindCredit,
+			stated: -500,
+			operations: []*domain.Operation{
+				operation(domain.OperationTypeExpense, 300),
+			},
+			opening: -200,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ledger := newBalanceLedger(nil, func(domain.ID) domain.AccountKind { return tc.kind })
+			ledger.openStated("acc", "счёт", tc.stated)
+			for _, op := range tc.operations {
+				if err := ledger.apply(op); err != nil {
+					t.Fatalf("apply: %v", err)
+				}
+			}
+			ledger.finish()
+
+			if got := ledger.opening("acc"); got != tc.opening {
+				t.Fatalf("opening = %d, want %d", got, tc.opening)
+			}
+			checks := ledger.checks()
+			if len(checks) != 1 {
+				t.Fatalf("checks = %d, want 1", len(checks))
+			}
+			if checks[0].Mismatch() != tc.mismatch {
+				t.Fatalf("mismatch = %v, want %v (%+v)", checks[0].Mismatch(), tc.mismatch, checks[0])
+			}
+		})
+	}
+}
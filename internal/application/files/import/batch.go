@@ -0,0 +1,277 @@
+package fileimport
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+var (
+	ErrImportAborted      = errors.New("import: aborted, applied changes were rolled back")
+	ErrRollbackFailed     = errors.New("import: rollback failed")
+	ErrBatchInUse         = errors.New("import: batch records are referenced by later operations")
+	ErrBatchesUnavailable = errors.New("import: batch history is not configured")
+	ErrRollbackConflict   = errors.New("import: record changed after import")
+)
+
+func (s *Service) Batches() ([]repository.ImportBatch, error) {
+	if s.batches == nil {
+		return nil, nil
+	}
+	return s.batches.List()
+}
+
+func (s *Service) Rollback(id domain.ID) (repository.ImportBatch, error) {
+	if s.batches == nil {
+		return repository.ImportBatch{}, ErrBatchesUnavailable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, err := s.batches.Get(id)
+	if err != nil {
+		return repository.ImportBatch{}, err
+	}
+
+	if err := s.checkBatchConflicts(batch); err != nil {
+		return repository.ImportBatch{}, err
+	}
+	if err := s.checkBatchReferences(batch); err != nil {
+		return repository.ImportBatch{}, err
+	}
+
+	remaining, err := s.undoChanges(batch.Changes)
+	if err != nil {
+		batch.Changes = batch.Changes[:remaining]
+		if saveErr := s.batches.Save(batch); saveErr != nil {
+			return repository.ImportBatch{}, errors.Join(fmt.Errorf("%w: %w", ErrRollbackFailed, err), saveErr)
+		}
+		return repository.ImportBatch{}, fmt.Errorf("%w: %w", ErrRollbackFailed, err)
+	}
+
+	if err := s.batches.Delete(id); err != nil {
+		return repository.ImportBatch{}, err
+	}
+
+	return batch, nil
+}
+
+func (s *Service) discard(changes []repository.ImportChange, cause error) error {
+	if _, err := s.undoChanges(changes); err != nil {
+		return errors.Join(cause, fmt.Errorf("%w: %w", ErrRollbackFailed, err))
+	}
+	return cause
+}
+
+func (s *Service) undoChanges(changes []repository.ImportChange) (int, error) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		if err := s.undo(changes[i]); err != nil {
+			return i + 1, err
+		}
+	}
+	return 0, nil
+}
+
+func (s *Service) undo(change repository.ImportChange) error {
+	created := change.Action == repository.ImportCreated
+
+	switch {
+	case change.Operation != nil:
+		op := change.Operation
+		remove := s.operations.DeleteOperationWithoutBalance
+		update := s.operations.UpdateOperationWithoutBalance
+		create := s.operations.CreateOperationWithoutBalance
+		if change.Balanced {
+			remove = s.operations.DeleteOperation
+			update = s.operations.ReplaceOperation
+			create = s.operations.CreateOperationWithID
+		}
+		if created {
+			return ignoreMissing(remove(op.ID()))
+		}
+		_, err := update(op.ID(), op.Type(), op.BankAccountID(), op.CategoryID(), op.Amount(), op.Date(), op.Description(), op.UpdatedAt())
+		if errors.Is(err, domain.ErrNotFound) {
+			_, err = create(op.ID(), op.Type(), op.BankAccountID(), op.CategoryID(), op.Amount(), op.Date(), op.Description(), op.UpdatedAt())
+		}
+		return err
+	case change.View != nil:
+		view := change.View
+		if created {
+			return ignoreMissing(s.views.DeleteView(view.ID()))
+		}
+		_, err := s.views.UpdateView(view.ID(), view.Name(), view.Criteria(), view.Period())
+		if errors.Is(err, domain.ErrNotFound) {
+			_, err = s.views.CreateViewWithID(view.ID(), view.Name(), view.Criteria(), view.Period())
+		}
+		return err
+	case change.Category != nil:
+		category := change.Category
+		if created {
+			return ignoreMissing(s.categories.DeleteCategory(category.ID()))
+		}
+		_, err := s.categories.UpdateCategory(category.ID(), category.Name(), category.Type())
+		if errors.Is(err, domain.ErrNotFound) {
+			_, err = s.categories.CreateCategoryWithID(category.ID(), category.Name(), category.Type())
+		}
+		return err
+	case change.Account != nil:
+		account := change.Account
+		if created {
+			return ignoreMissing(s.accounts.DeleteAccount(account.ID()))
+		}
+		if profile := change.Profile; profile != nil {
+			_, err := s.accounts.UpdateAccountWithProfile(account.ID(), account.Name(), account.Balance(), profile.Kind(), profile.Group())
+			if errors.Is(err, domain.ErrNotFound) {
+				_, err = s.accounts.CreateAccountWithProfile(account.ID(), account.Name(), account.Balance(), profile.Kind(), profile.Group())
+			}
+			return err
+		}
+		_, err := s.accounts.UpdateAccount(account.ID(), account.Name(), account.Balance())
+		if errors.Is(err, domain.ErrNotFound) {
+			_, err = s.accounts.CreateAccountWithID(account.ID(), account.Name(), account.Balance())
+		}
+		return err
+	default:
+		return nil
+	}
+}
+
+func (s *Service) checkBatchReferences(batch repository.ImportBatch) error {
+	if s.operations == nil {
+		return nil
+	}
+
+	owned := make(map[domain.ID]struct{})
+	var accountIDs, categoryIDs []domain.ID
+	for _, change := range batch.Changes {
+		if change.Operation != nil {
+			owned[change.Operation.ID()] = struct{}{}
+		}
+		if change.Action != repository.ImportCreated {
+			continue
+		}
+		switch {
+		case change.Account != nil:
+			accountIDs = append(accountIDs, change.Account.ID())
+		case change.Category != nil:
+			categoryIDs = append(categoryIDs, change.Category.ID())
+		}
+	}
+
+	filters := make([]query.OperationFilter, 0, 2)
+	if len(accountIDs) > 0 {
+		filters = append(filters, query.NewOperationFilter().ForAccounts(accountIDs...))
+	}
+	if len(categoryIDs) > 0 {
+		filters = append(filters, query.NewOperationFilter().ForCategories(categoryIDs...))
+	}
+
+	for _, filter := range filters {
+		ops, err := s.operations.ListOperationsWithFilter(filter)
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if _, ok := owned[op.ID()]; !ok {
+				return ErrBatchInUse
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) checkBatchConflicts(batch repository.ImportBatch) error {
+	var conflicts []error
+	for _, change := range batch.Changes {
+		if !change.Recorded {
+			continue
+		}
+		current, err := s.currentState(change)
+		if err != nil {
+			return err
+		}
+		if sameState(current, change.Applied) {
+			continue
+		}
+		if change.Action == repository.ImportCreated && current == (repository.ImportState{}) {
+			continue
+		}
+		entity, id := changeSubject(change)
+		conflicts = append(conflicts, fmt.Errorf("%w: %s %s", ErrRollbackConflict, entity, id))
+	}
+	return errors.Join(conflicts...)
+}
+
+func (s *Service) currentState(change repository.ImportChange) (repository.ImportState, error) {
+	var state repository.ImportState
+	var err error
+	switch {
+	case change.Operation != nil:
+		state.Operation, err = s.operations.GetOperation(change.Operation.ID())
+	case change.View != nil:
+		state.View, err = s.views.GetView(change.View.ID())
+	case change.Category != nil:
+		state.Category, err = s.categories.GetCategory(change.Category.ID())
+	case change.Account != nil:
+		state.Account, err = s.accounts.GetAccount(change.Account.ID())
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		return repository.ImportState{}, nil
+	}
+	return state, err
+}
+
+func sameState(a, b repository.ImportState) bool {
+	switch {
+	case a.Operation != nil || b.Operation != nil:
+		return a.Operation != nil && b.Operation != nil && sameOperation(a.Operation, b.Operation)
+	case a.View != nil || b.View != nil:
+		return a.View != nil && b.View != nil && reflect.DeepEqual(*a.View, *b.View)
+	case a.Category != nil || b.Category != nil:
+		return a.Category != nil && b.Category != nil &&
+			a.Category.Name() == b.Category.Name() && a.Category.Type() == b.Category.Type()
+	case a.Account != nil || b.Account != nil:
+		return a.Account != nil && b.Account != nil &&
+			a.Account.Name() == b.Account.Name() && a.Account.Balance() == b.Account.Balance()
+	default:
+		return true
+	}
+}
+
+func sameOperation(a, b *domain.Operation) bool {
+	return a.Type() == b.Type() &&
+		a.BankAccountID() == b.BankAccountID() &&
+		a.CategoryID() == b.CategoryID() &&
+		a.Amount() == b.Amount() &&
+		a.Date().Equal(b.Date()) &&
+		a.Description() == b.Description() &&
+		a.UpdatedAt().Equal(b.UpdatedAt())
+}
+
+func changeSubject(change repository.ImportChange) (Entity, domain.ID) {
+	switch {
+	case change.Operation != nil:
+		return EntityOperation, change.Operation.ID()
+	case change.View != nil:
+		return EntityView, change.View.ID()
+	case change.Category != nil:
+		return EntityCategory, change.Category.ID()
+	case change.Account != nil:
+		return EntityAccount, change.Account.ID()
+	default:
+		return "", ""
+	}
+}
+
+func ignoreMissing(err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	return err
+}
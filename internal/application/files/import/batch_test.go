@@ -0,0 +1,213 @@
+package fileimport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+type rollbackFixture struct {
+	*fixture
+	account  domain.ID
+	category domain.ID
+	updated  domain.ID
+	created  domain.ID
+	newCat   domain.ID
+	date     time.Time
+}
+
+func newRollbackFixture(t *testing.T) rollbackFixture {
+	t.Helper()
+
+	r := rollbackFixture{
+		account:  mustID(t),
+		category: mustID(t),
+		updated:  mustID(t),
+		created:  mustID(t),
+		newCat:   mustID(t),
+		date:     time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	payload := filesmodel.Payload{
+		Accounts:   []filesmodel.Account{{ID: r.account.String(), Name: "Карта после импорта", Balance: 700}},
+		Categories: []filesmodel.Category{{ID: r.newCat.String(), Type: "expense", Name: "Кафе"}},
+		Operations: []filesmodel.Operation{
+			{ID: r.updated.String(), Type: "expense", BankAccountID: r.account.String(), CategoryID: r.category.String(), Amount: 300, Date: r.date},
+			{ID: r.created.String(), Type: "expense", BankAccountID: r.account.String(), CategoryID: r.newCat.String(), Amount: 50, Date: r.date},
+		},
+	}
+	r.fixture = newFixture(t, payloadImporter{payload: payload})
+
+	if _, err := r.accounts.CreateAccountWithID(r.account, "Карта", 1000); err != nil {
+		t.Fatalf("account: %v", err)
+	}
+	if _, err := r.categories.CreateCategoryWithID(r.category, "Еда", domain.OperationTypeExpense); err != nil {
+		t.Fatalf("category: %v", err)
+	}
+	if _, err := r.operations.CreateOperationWithoutBalance(r.updated, domain.OperationTypeExpense, r.account, r.category, 100, r.date, "до импорта", time.Now()); err != nil {
+		t.Fatalf("operation: %v", err)
+	}
+
+	return r
+}
+
+func TestRollbackDetectsChangesAfterImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     Mode
+		edit     func(t *testing.T, r rollbackFixture)
+		conflict bool
+	}{
+		{name: "untouched", edit: func(*testing.T, rollbackFixture) {}},
+		{name: "untouched opening balance", mode: ModeOpeningBalance, edit: func(*testing.T, rollbackFixture) {}},
+		{
+			name: "updated account edited",
+			edit: func(t *testing.T, r rollbackFixture) {
+				mustDo(t, updateErr(r.accounts.UpdateAccount(r.account, "Карта", 650)))
+			},
+			conflict: true,
+		},
+		{
+			name: "updated operation edited",
+			edit: func(t *testing.T, r rollbackFixture) {
+				mustDo(t, updateErr(r.operations.UpdateOperationWithoutBalance(r.updated, domain.OperationTypeExpense, r.account, r.category, 300, r.date, "правка", time.Now())))
+			},
+			conflict: true,
+		},
+		{
+			name: "updated operation deleted",
+			edit: func(t *testing.T, r rollbackFixture) {
+				mustDo(t, r.operations.DeleteOperationWithoutBalance(r.updated))
+			},
+			conflict: true,
+		},
+		{
+			name: "created operation edited",
+			edit: func(t *testing.T, r rollbackFixture) {
+				mustDo(t, updateErr(r.operations.UpdateOperationWithoutBalance(r.created, domain.OperationTypeExpense, r.account, r.newCat, 75, r.date, "", time.Now())))
+			},
+			conflict: true,
+		},
+		{
+			name: "created operation deleted",
+			edit: func(t *testing.T, r rollbackFixture) {
+				mustDo(t, r.operations.DeleteOperationWithoutBalance(r.created))
+			},
+			conflict: false,
+		},
+		{
+			name: "created category renamed",
+			edit: func(t *testing.T, r rollbackFixture) {
+				mustDo(t, updateErr(r.categories.UpdateCategory(r.newCat, "Рестораны", domain.OperationTypeExpense)))
+			},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRollbackFixture(t)
+			options := DefaultOptions()
+			options.Strategy = StrategyOverwrite
+			if tt.mode != "" {
+				options.Mode = tt.mode
+			}
+			result, err := r.service.Import("payload", strings.NewReader(""), options)
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+
+			tt.edit(t, r)
+
+			_, err = r.service.Rollback(result.BatchID)
+			if tt.conflict {
+				if !errors.Is(err, ErrRollbackConflict) {
+					t.Fatalf("Rollback error = %v, want %v", err, ErrRollbackConflict)
+				}
+				if _, err := r.service.batches.Get(result.BatchID); err != nil {
+					t.Fatalf("batch removed after refused rollback: %v", err)
+				}
+				if _, err := r.categories.GetCategory(r.newCat); err != nil {
+					t.Fatalf("refused rollback removed a created record: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Rollback: %v", err)
+			}
+
+			account, err := r.accounts.GetAccount(r.account)
+			if err != nil {
+				t.Fatalf("account: %v", err)
+			}
+			if account.Name() != "Карта" || account.Balance() != 1000 {
+				t.Errorf("account = %s/%d, want restored Карта/1000", account.Name(), account.Balance())
+			}
+			op, err := r.operations.GetOperation(r.updated)
+			if err != nil {
+				t.Fatalf("operation: %v", err)
+			}
+			if op.Amount() != 100 || op.Description() != "до импорта" {
+				t.Errorf("operation = %d %q, want restored 100 %q", op.Amount(), op.Description(), "до импорта")
+			}
+			if _, err := r.operations.GetOperation(r.created); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("created operation still present: %v", err)
+			}
+			if _, err := r.categories.GetCategory(r.newCat); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("created category still present: %v", err)
+			}
+		})
+	}
+}
+
+func TestRollbackRestoresAccountProfile(t *testing.T) {
+	accountID := mustID(t)
+	payload := filesmodel.Payload{
+		Accounts: []filesmodel.Account{{ID: accountID.String(), Name: "Кредитка", Balance: -200, Kind: "credit", Group: "Карты"}},
+	}
+	f := newFixture(t, payloadImporter{payload: payload})
+	if _, err := f.accounts.CreateAccountWithID(accountID, "Карта", 100); err != nil {
+		t.Fatalf("account: %v", err)
+	}
+
+	options := DefaultOptions()
+	options.Strategy = StrategyOverwrite
+	result, err := f.service.Import("payload", strings.NewReader(""), options)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.UpdatedAccounts != 1 {
+		t.Fatalf("updated %d accounts, want 1 (%+v)", result.UpdatedAccounts, result.Records)
+	}
+
+	if _, err := f.service.Rollback(result.BatchID); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	account, err := f.accounts.GetAccount(accountID)
+	if err != nil {
+		t.Fatalf("account: %v", err)
+	}
+	profile, err := f.accounts.GetAccountProfile(accountID)
+	if err != nil {
+		t.Fatalf("profile: %v", err)
+	}
+	if account.Name() != "Карта" || account.Balance() != 100 || profile.Kind() != domain.AccountKindAsset || profile.Group() != "" {
+		t.Errorf("account = %s/%d %s/%q, want restored Карта/100 asset", account.Name(), account.Balance(), profile.Kind(), profile.Group())
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func updateErr[T any](_ T, err error) error {
+	return err
+}
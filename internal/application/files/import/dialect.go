@@ -0,0 +1,25 @@
+package fileimport
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+var ErrDialectUnsupported = errors.New("import: format does not support encoding and delimiter settings")
+
+type Dialect struct {
+	Encoding  string
+	Delimiter string
+}
+
+func (d Dialect) IsZero() bool {
+	return strings.TrimSpace(d.Encoding) == "" && d.Delimiter == ""
+}
+
+type DialectImporter interface {
+	Importer
+	ParseWithDialect(reader io.Reader, dialect Dialect) (filesmodel.Payload, error)
+}
@@ -0,0 +1,52 @@
+package fileimport
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+type dialectImporter struct {
+	payloadImporter
+	received *Dialect
+}
+
+func (i dialectImporter) ParseWithDialect(_ io.Reader, dialect Dialect) (filesmodel.Payload, error) {
+	*i.received = dialect
+	return i.payload, nil
+}
+
+func TestParsePassesDialect(t *testing.T) {
+	dialect := Dialect{Encoding: "windows-1251", Delimiter: ";"}
+
+	cases := []struct {
+		name     string
+		importer Importer
+		dialect  Dialect
+		wantErr  error
+		want     Dialect
+	}{
+		{name: "dialect importer", importer: dialectImporter{received: new(Dialect)}, dialect: dialect, want: dialect},
+		{name: "plain importer without dialect", importer: payloadImporter{}},
+		{name: "plain importer with dialect", importer: payloadImporter{}, dialect: dialect, wantErr: ErrDialectUnsupported},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := newFixture(t, tc.importer)
+			options := DefaultOptions()
+			options.Dialect = tc.dialect
+
+			_, err := fx.service.Preview("payload", strings.NewReader(""), options)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("preview error = %v, want %v", err, tc.wantErr)
+			}
+			if importer, ok := tc.importer.(dialectImporter); ok && *importer.received != tc.want {
+				t.Fatalf("dialect = %+v, want %+v", *importer.received, tc.want)
+			}
+		})
+	}
+}
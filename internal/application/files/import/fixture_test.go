@@ -0,0 +1,77 @@
+package fileimport
+
+import (
+	"io"
+	"testing"
+
+	"kpo-hw-2/internal/application/facade"
+	"kpo-hw-2/internal/application/files"
+	"kpo-hw-2/internal/domain"
+	domainfactory "kpo-hw-2/internal/domain/factory"
+	"kpo-hw-2/internal/domain/repository"
+	filesmodel "kpo-hw-2/internal/files/model"
+	"kpo-hw-2/internal/infrastructure/id"
+	"kpo-hw-2/internal/infrastructure/repository/memory"
+)
+
+type fixture struct {
+	service    *Service
+	accounts   facade.AccountFacade
+	categories facade.CategoryFacade
+	operations facade.OperationFacade
+	views      facade.SavedViewFacade
+}
+
+func newFixture(t *testing.T, importers ...Importer) *fixture {
+	t.Helper()
+
+	ids := id.NewULIDGenerator()
+	accountRepo := memory.NewAccountRepository()
+	categoryRepo := memory.NewCategoryRepository()
+	profileRepo := memory.NewAccountProfileRepository()
+
+	accounts := facade.NewAccountFacade(domainfactory.NewBankAccountFactory(ids), accountRepo, profileRepo)
+	categories := facade.NewCategoryFacade(domainfactory.NewCategoryFactory(ids), categoryRepo)
+	operations := facade.NewOperationFacade(
+		domainfactory.NewOperationFactory(ids),
+		memory.NewOperationRepository(),
+		accountRepo,
+		categoryRepo,
+		profileRepo,
+		memory.NewOperationAggregateRepository(),
+	)
+	views := facade.NewSavedViewFacade(domainfactory.NewSavedViewFactory(ids), memory.NewSavedViewRepository(), accountRepo, categoryRepo)
+
+	return &fixture{
+		service:    NewService(accounts, categories, operations, views, ids, memory.NewImportBatchRepository(), nil, importers),
+		accounts:   accounts,
+		categories: categories,
+		operations: operations,
+		views:      views,
+	}
+}
+
+func mustID(t *testing.T) domain.ID {
+	t.Helper()
+	value, err := id.NewULIDGenerator().NewID()
+	if err != nil {
+		t.Fatalf("new id: %v", err)
+	}
+	return value
+}
+
+type payloadImporter struct {
+	payload filesmodel.Payload
+}
+
+func (payloadImporter) Format() files.Format {
+	return files.Format{Key: "payload", Title: "payload"}
+}
+
+func (i payloadImporter) Parse(io.Reader) (filesmodel.Payload, error) {
+	return i.payload, nil
+}
+
+func repositoryBatch() repository.ImportBatch {
+	return repository.ImportBatch{Format: "payload"}
+}
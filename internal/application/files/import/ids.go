@@ -0,0 +1,46 @@
+package fileimport
+
+import "kpo-hw-2/internal/domain"
+
+type IDAssignments map[string]domain.ID
+
+type idAllocator struct {
+	ids      domain.IDGenerator
+	reuse    IDAssignments
+	assigned IDAssignments
+}
+
+func newIDAllocator(ids domain.IDGenerator, reuse IDAssignments) *idAllocator {
+	return &idAllocator{
+		ids:      ids,
+		reuse:    reuse,
+		assigned: make(IDAssignments),
+	}
+}
+
+func (a *idAllocator) id(key string) (domain.ID, error) {
+	if id, ok := a.assigned[key]; ok {
+		return id, nil
+	}
+	id, ok := a.reuse[key]
+	if !ok {
+		if a.ids == nil {
+			return "", ErrNoIDGenerator
+		}
+		fresh, err := a.ids.NewID()
+		if err != nil {
+			return "", err
+		}
+		id = fresh
+	}
+	a.assigned[key] = id
+	return id, nil
+}
+
+func renamedKey(entity Entity, id domain.ID) string {
+	return string(entity) + ":" + id.String()
+}
+
+func linkedKey(entity Entity, name string) string {
+	return string(entity) + "-name:" + name
+}
@@ -0,0 +1,115 @@
+package fileimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+func TestPreviewAndImportAssignSameIDs(t *testing.T) {
+	date := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		seed     bool
+		payload  func(account, category, operation, view domain.ID) filesmodel.Payload
+	}{
+		{
+			name:     "import as new",
+			strategy: StrategyImportAsNew,
+			seed:     true,
+			payload: func(account, category, operation, view domain.ID) filesmodel.Payload {
+				return filesmodel.Payload{
+					Accounts:   []filesmodel.Account{{ID: account.String(), Name: "Карта", Balance: 100}},
+					Categories: []filesmodel.Category{{ID: category.String(), Type: "expense", Name: "Еда"}},
+					Operations: []filesmodel.Operation{{
+						ID: operation.String(), Type: "expense", BankAccountID: account.String(),
+						CategoryID: category.String(), Amount: 10, Date: date,
+					}},
+					Views: []filesmodel.SavedView{{ID: view.String(), Name: "Все"}},
+				}
+			},
+		},
+		{
+			name:     "linked by name",
+			strategy: StrategySkip,
+			payload: func(_, _, operation, _ domain.ID) filesmodel.Payload {
+				return filesmodel.Payload{Operations: []filesmodel.Operation{{
+					ID: operation.String(), Type: "income", AccountName: "Новый счёт",
+					CategoryName: "Зарплата", Amount: 500, Date: date,
+				}}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, category, operation, view := mustID(t), mustID(t), mustID(t), mustID(t)
+			payload := tt.payload(account, category, operation, view)
+			f := newFixture(t, payloadImporter{payload: payload})
+
+			if tt.seed {
+				seedPayload(t, f, payload)
+			}
+
+			options := DefaultOptions()
+			options.Strategy = tt.strategy
+			preview, err := f.service.Preview("payload", strings.NewReader(""), options)
+			if err != nil {
+				t.Fatalf("Preview: %v", err)
+			}
+			if len(preview.IDs) == 0 {
+				t.Fatalf("preview assigned no ids")
+			}
+
+			options.IDs = preview.IDs
+			result, err := f.service.Import("payload", strings.NewReader(""), options)
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+
+			if len(result.Records) != len(preview.Records) {
+				t.Fatalf("import has %d records, preview %d", len(result.Records), len(preview.Records))
+			}
+			for i, record := range result.Records {
+				if record.NewID != preview.Records[i].NewID {
+					t.Errorf("record %s new id = %q, preview showed %q", record.ID, record.NewID, preview.Records[i].NewID)
+				}
+			}
+			for key, id := range preview.IDs {
+				if !storedID(f, key, id) {
+					t.Errorf("id %s for %s was previewed but not stored", id, key)
+				}
+			}
+		})
+	}
+}
+
+func seedPayload(t *testing.T, f *fixture, payload filesmodel.Payload) {
+	t.Helper()
+	if _, err := f.service.applyPayload(payload, DefaultOptions(), repositoryBatch()); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+func storedID(f *fixture, key string, id domain.ID) bool {
+	switch {
+	case strings.HasPrefix(key, string(EntityAccount)):
+		_, err := f.accounts.GetAccount(id)
+		return err == nil
+	case strings.HasPrefix(key, string(EntityCategory)):
+		_, err := f.categories.GetCategory(id)
+		return err == nil
+	case strings.HasPrefix(key, string(EntityOperation)):
+		_, err := f.operations.GetOperation(id)
+		return err == nil
+	case strings.HasPrefix(key, string(EntityView)):
+		_, err := f.views.GetView(id)
+		return err == nil
+	}
+	return false
+}
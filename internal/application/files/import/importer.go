@@ -1,11 +1,59 @@
 package fileimport
 
 import (
+	"io"
+
 	"kpo-hw-2/internal/application/files"
 	filesmodel "kpo-hw-2/internal/files/model"
 )
 
 type Importer interface {
 	Format() files.Format
-	Parse(data []byte) (filesmodel.Payload, error)
+	Parse(reader io.Reader) (filesmodel.Payload, error)
+}
+
+type RecordVisitor interface {
+	VisitAccount(account filesmodel.Account) error
+	VisitCategory(category filesmodel.Category) error
+	VisitOperation(operation filesmodel.Operation) error
+	VisitView(view filesmodel.SavedView) error
+}
+
+type StreamImporter interface {
+	Importer
+	Stream(reader io.Reader, visitor RecordVisitor) error
+}
+
+type PayloadCollector struct {
+	Payload filesmodel.Payload
+}
+
+func Collect(stream func(RecordVisitor) error) (filesmodel.Payload, error) {
+	collector := &PayloadCollector{}
+	if err := stream(collector); err != nil {
+		return filesmodel.Payload{}, err
+	}
+	return collector.Payload, nil
 }
+
+func (c *PayloadCollector) VisitAccount(account filesmodel.Account) error {
+	c.Payload.Accounts = append(c.Payload.Accounts, account)
+	return nil
+}
+
+func (c *PayloadCollector) VisitCategory(category filesmodel.Category) error {
+	c.Payload.Categories = append(c.Payload.Categories, category)
+	return nil
+}
+
+func (c *PayloadCollector) VisitOperation(operation filesmodel.Operation) error {
+	c.Payload.Operations = append(c.Payload.Operations, operation)
+	return nil
+}
+
+func (c *PayloadCollector) VisitView(view filesmodel.SavedView) error {
+	c.Payload.Views = append(c.Payload.Views, view)
+	return nil
+}
+
+var _ RecordVisitor = (*PayloadCollector)(nil)
@@ -0,0 +1,129 @@
+package fileimport
+
+import (
+	"strings"
+
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+func (s *Service) linkNames(payload filesmodel.Payload, allocator *idAllocator) (filesmodel.Payload, map[domain.ID]struct{}) {
+	implied := make(map[domain.ID]struct{})
+	if !hasNamedReferences(payload) {
+		return payload, implied
+	}
+
+	accounts := make(map[string]string)
+	for _, dto := range payload.Accounts {
+		addName(accounts, nameKey("", dto.Name), dto.ID)
+	}
+	if s.accounts != nil {
+		if stored, err := s.accounts.ListAccounts(); err == nil {
+			for _, account := range stored {
+				addName(accounts, nameKey("", account.Name()), account.ID().String())
+			}
+		}
+	}
+
+	categories := make(map[string]string)
+	for _, dto := range payload.Categories {
+		addName(categories, nameKey(dto.Type, dto.Name), dto.ID)
+	}
+	if s.categories != nil {
+		if stored, err := s.categories.ListCategories(""); err == nil {
+			for _, category := range stored {
+				addName(categories, nameKey(string(category.Type()), category.Name()), category.ID().String())
+			}
+		}
+	}
+
+	linked := payload
+	linked.Accounts = append([]filesmodel.Account(nil), payload.Accounts...)
+	linked.Categories = make([]filesmodel.Category, 0, len(payload.Categories))
+	linked.Operations = append([]filesmodel.Operation(nil), payload.Operations...)
+
+	for _, dto := range payload.Categories {
+		if strings.TrimSpace(dto.ID) != "" || strings.TrimSpace(dto.Name) == "" {
+			linked.Categories = append(linked.Categories, dto)
+			continue
+		}
+		key := nameKey(dto.Type, dto.Name)
+		if _, ok := categories[key]; ok {
+			continue
+		}
+		if fresh, err := allocator.id(linkedKey(EntityCategory, key)); err == nil {
+			dto.ID = fresh.String()
+			categories[key] = dto.ID
+		}
+		linked.Categories = append(linked.Categories, dto)
+	}
+
+	for i := range linked.Operations {
+		op := &linked.Operations[i]
+
+		if strings.TrimSpace(op.BankAccountID) == "" && strings.TrimSpace(op.AccountName) != "" {
+			key := nameKey("", op.AccountName)
+			id, ok := accounts[key]
+			if !ok {
+				if fresh, err := allocator.id(linkedKey(EntityAccount, key)); err == nil {
+					id = fresh.String()
+					accounts[key] = id
+					implied[fresh] = struct{}{}
+					linked.Accounts = append(linked.Accounts, filesmodel.Account{
+						ID:   id,
+						Name: strings.TrimSpace(op.AccountName),
+					})
+				}
+			}
+			op.BankAccountID = id
+		}
+
+		typ := domain.OperationType(strings.ToLower(strings.TrimSpace(op.Type)))
+		if strings.TrimSpace(op.CategoryID) == "" && strings.TrimSpace(op.CategoryName) != "" {
+			key := nameKey(string(typ), op.CategoryName)
+			id, ok := categories[key]
+			if !ok && (typ == domain.OperationTypeIncome || typ == domain.OperationTypeExpense) {
+				if fresh, err := allocator.id(linkedKey(EntityCategory, key)); err == nil {
+					id = fresh.String()
+					categories[key] = id
+					linked.Categories = append(linked.Categories, filesmodel.Category{
+						ID:   id,
+						Type: string(typ),
+						Name: strings.TrimSpace(op.CategoryName),
+					})
+				}
+			}
+			op.CategoryID = id
+		}
+	}
+
+	return linked, implied
+}
+
+func hasNamedReferences(payload filesmodel.Payload) bool {
+	for _, category := range payload.Categories {
+		if strings.TrimSpace(category.ID) == "" && strings.TrimSpace(category.Name) != "" {
+			return true
+		}
+	}
+	for _, op := range payload.Operations {
+		if op.AccountName != "" || op.CategoryName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func nameKey(typ, name string) string {
+	return strings.ToLower(strings.TrimSpace(typ)) + "/" + strings.ToLower(strings.TrimSpace(name))
+}
+
+func addName(index map[string]string, key, id string) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return
+	}
+	if _, exists := index[key]; !exists {
+		index[key] = id
+	}
+}
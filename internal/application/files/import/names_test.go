@@ -0,0 +1,78 @@
+package fileimport
+
+import (
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+	"kpo-hw-2/internal/infrastructure/id"
+)
+
+func TestLinkNamesResolvesNamedCategories(t *testing.T) {
+	date := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		stored     string
+		categories []filesmodel.Category
+		wantNew    int
+		wantStored bool
+	}{
+		{
+			name:       "unused category is created",
+			categories: []filesmodel.Category{{Type: "income", Name: "Подарки"}, {Type: "expense", Name: "Еда"}},
+			wantNew:    2,
+		},
+		{
+			name:       "stored category is reused",
+			stored:     "Еда",
+			categories: []filesmodel.Category{{Type: "expense", Name: "еда"}},
+			wantStored: true,
+		},
+		{
+			name:       "repeated name is declared once",
+			categories: []filesmodel.Category{{Type: "expense", Name: "Еда"}, {Type: "expense", Name: "Еда"}},
+			wantNew:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			var stored domain.ID
+			if tt.stored != "" {
+				category, err := f.categories.CreateCategory(tt.stored, domain.OperationTypeExpense)
+				if err != nil {
+					t.Fatalf("create category: %v", err)
+				}
+				stored = category.ID()
+			}
+
+			payload := filesmodel.Payload{
+				Categories: tt.categories,
+				Operations: []filesmodel.Operation{{
+					ID: mustID(t).String(), Type: "expense", AccountName: "Карта",
+					CategoryName: "Еда", Amount: 10, Date: date,
+				}},
+			}
+			linked, _ := f.service.linkNames(payload, newIDAllocator(id.NewULIDGenerator(), nil))
+
+			if len(linked.Categories) != tt.wantNew {
+				t.Fatalf("categories = %+v, want %d", linked.Categories, tt.wantNew)
+			}
+			for _, category := range linked.Categories {
+				if category.ID == "" {
+					t.Fatalf("category %q has no id", category.Name)
+				}
+			}
+			got := linked.Operations[0].CategoryID
+			if tt.wantStored && got != stored.String() {
+				t.Fatalf("operation category = %s, want stored %s", got, stored)
+			}
+			if !tt.wantStored && got == "" {
+				t.Fatal("operation category is not linked")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package fileimport
+
+import "errors"
+
+type Mode string
+
+const (
+	ModeSnapshot       Mode = "snapshot"
+	ModeOpeningBalance Mode = "opening_balance"
+)
+
+var ErrUnknownMode = errors.New("import: unknown balance mode")
+
+func Modes() []Mode {
+	return []Mode{ModeSnapshot, ModeOpeningBalance}
+}
+
+func (m Mode) Valid() bool {
+	switch m {
+	case ModeSnapshot, ModeOpeningBalance:
+		return true
+	default:
+		return false
+	}
+}
+
+type Options struct {
+	Strategy Strategy
+	Mode     Mode
+	Profile  string
+	Dialect  Dialect
+	IDs      IDAssignments
+}
+
+func DefaultOptions() Options {
+	return Options{Strategy: StrategySkip, Mode: ModeSnapshot}
+}
+
+func (o Options) validate() error {
+	if !o.Strategy.Valid() {
+		return ErrUnknownStrategy
+	}
+	if !o.Mode.Valid() {
+		return ErrUnknownMode
+	}
+	return nil
+}
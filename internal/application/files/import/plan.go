@@ -0,0 +1,600 @@
+package fileimport
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+type Entity string
+
+const (
+	EntityAccount   Entity = "account"
+	EntityCategory  Entity = "category"
+	EntityOperation Entity = "operation"
+	EntityView      Entity = "view"
+)
+
+type Outcome string
+
+const (
+	OutcomeCreate           Outcome = "create"
+	OutcomeUpdate           Outcome = "update"
+	OutcomeDuplicate        Outcome = "duplicate"
+	OutcomeInvalid          Outcome = "invalid"
+	OutcomeMissingReference Outcome = "missing_reference"
+)
+
+var (
+	ErrEmptyID         = errors.New("import: record has no id")
+	ErrRepeatedID      = errors.New("import: record id repeats in file")
+	ErrExistingRecord  = errors.New("import: record already exists")
+	ErrStoredNewer     = errors.New("import: stored record is newer")
+	ErrNoTimestamp     = errors.New("import: record has no update timestamp")
+	ErrMissingAccount  = errors.New("import: referenced account not found")
+	ErrMissingCategory = errors.New("import: referenced category not found")
+	ErrNoIDGenerator   = errors.New("import: id generator is not configured")
+	ErrAmountRounded   = errors.New("import: fractional amount rounded to whole units")
+)
+
+type RecordOutcome struct {
+	Entity  Entity
+	ID      string
+	NewID   string
+	Label   string
+	Outcome Outcome
+	Err     error
+	Warning error
+}
+
+type Preview struct {
+	Records  []RecordOutcome
+	Balances []BalanceCheck
+	IDs      IDAssignments
+}
+
+func (p Preview) Count(entity Entity, outcome Outcome) int {
+	return countRecords(p.Records, entity, outcome)
+}
+
+type plannedRecord struct {
+	RecordOutcome
+	apply func() (repository.ImportChange, error)
+}
+
+type importPlan struct {
+	records  []plannedRecord
+	balances []BalanceCheck
+	ids      IDAssignments
+}
+
+func (p importPlan) outcomes() []RecordOutcome {
+	out := make([]RecordOutcome, len(p.records))
+	for i, record := range p.records {
+		out[i] = record.RecordOutcome
+	}
+	return out
+}
+
+func (s *Service) planPayload(payload filesmodel.Payload, options Options) importPlan {
+	var plan importPlan
+
+	allocator := newIDAllocator(s.ids, options.IDs)
+	payload, implied := s.linkNames(payload, allocator)
+	strategy := options.Strategy
+	balanced := options.Mode == ModeOpeningBalance
+	kinds := make(map[domain.ID]domain.AccountKind)
+	ledger := newBalanceLedger(s.storedAccount, func(id domain.ID) domain.AccountKind {
+		if kind, ok := kinds[id]; ok {
+			return kind
+		}
+		return s.accountKind(id)
+	})
+
+	accounts := make(map[domain.ID]bool)
+	categories := make(map[domain.ID]domain.OperationType)
+	accountIDs := make(map[domain.ID]domain.ID)
+	categoryIDs := make(map[domain.ID]domain.ID)
+
+	if s.accounts != nil {
+		seen := make(map[domain.ID]struct{})
+		for _, dto := range payload.Accounts {
+			dto := dto
+			id := domain.ID(strings.TrimSpace(dto.ID))
+			name := strings.TrimSpace(dto.Name)
+			record := plannedRecord{RecordOutcome: RecordOutcome{
+				Entity:  EntityAccount,
+				ID:      dto.ID,
+				Label:   name,
+				Warning: roundingWarning(dto.Rounded),
+			}}
+
+			if _, repeated := seen[id]; repeated && id != "" {
+				record.reject(OutcomeDuplicate, ErrRepeatedID)
+				plan.records = append(plan.records, record)
+				continue
+			}
+
+			candidate, err := domain.NewBankAccount(id, dto.Name, dto.Balance)
+			if err != nil {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			kind, group, err := importedProfile(dto)
+			if err != nil {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			seen[id] = struct{}{}
+
+			if existing, err := s.accounts.GetAccount(id); err == nil {
+				switch strategy.resolve(false) {
+				case resolutionUpdate:
+					previous, err := s.accounts.GetAccountProfile(id)
+					if err != nil {
+						record.reject(OutcomeInvalid, err)
+						plan.records = append(plan.records, record)
+						continue
+					}
+					if kind == "" {
+						kind = previous.Kind()
+					}
+					if group == "" && dto.Kind == "" {
+						group = previous.Group()
+					}
+					if err := candidate.CheckBalance(kind); err != nil && !balanced {
+						record.reject(OutcomeInvalid, invalidReason(id, err))
+						plan.records = append(plan.records, record)
+						continue
+					}
+					kinds[id] = kind
+					accounts[id] = true
+					ledger.openStated(id, name, dto.Balance)
+					record.Outcome = OutcomeUpdate
+					record.apply = func() (repository.ImportChange, error) {
+						balance := dto.Balance
+						if balanced {
+							balance = ledger.opening(id)
+						}
+						_, err := s.accounts.UpdateAccountWithProfile(id, name, balance, kind, group)
+						return repository.ImportChange{
+							Action:  repository.ImportUpdated,
+							Account: existing,
+							Profile: previous,
+						}, err
+					}
+					plan.records = append(plan.records, record)
+					continue
+				case resolutionRename:
+					newID, err := allocator.id(renamedKey(EntityAccount, id))
+					if err != nil {
+						record.reject(OutcomeInvalid, err)
+						plan.records = append(plan.records, record)
+						continue
+					}
+					accountIDs[id] = newID
+					id = newID
+					record.NewID = newID.String()
+				default:
+					ledger.open(id, existing.Name(), existing.Balance(), dto.Balance)
+					record.reject(OutcomeDuplicate, strategy.keepReason(false))
+					plan.records = append(plan.records, record)
+					continue
+				}
+			}
+
+			if kind == "" {
+				kind = domain.AccountKindAsset
+			}
+			if err := candidate.CheckBalance(kind); err != nil && !balanced {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			kinds[id] = kind
+			accounts[id] = true
+
+			if _, ok := implied[id]; ok {
+				ledger.openImplied(id, name)
+			} else {
+				ledger.openStated(id, name, dto.Balance)
+			}
+			record.Outcome = OutcomeCreate
+			record.apply = func() (repository.ImportChange, error) {
+				balance := dto.Balance
+				if balanced {
+					balance = ledger.opening(id)
+				}
+				account, err := s.accounts.CreateAccountWithProfile(id, name, balance, kind, group)
+				return repository.ImportChange{Action: repository.ImportCreated, Account: account}, err
+			}
+			plan.records = append(plan.records, record)
+		}
+	}
+
+	if s.categories != nil {
+		seen := make(map[domain.ID]struct{})
+		for _, dto := range payload.Categories {
+			dto := dto
+			id := domain.ID(strings.TrimSpace(dto.ID))
+			typ := domain.OperationType(strings.ToLower(strings.TrimSpace(dto.Type)))
+			name := strings.TrimSpace(dto.Name)
+			record := plannedRecord{RecordOutcome: RecordOutcome{
+				Entity: EntityCategory,
+				ID:     dto.ID,
+				Label:  name,
+			}}
+
+			if _, repeated := seen[id]; repeated && id != "" {
+				record.reject(OutcomeDuplicate, ErrRepeatedID)
+				plan.records = append(plan.records, record)
+				continue
+			}
+
+			if _, err := domain.NewCategory(id, typ, dto.Name); err != nil {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			seen[id] = struct{}{}
+
+			if existing, err := s.categories.GetCategory(id); err == nil {
+				switch strategy.resolve(false) {
+				case resolutionUpdate:
+					categories[id] = typ
+					record.Outcome = OutcomeUpdate
+					record.apply = func() (repository.ImportChange, error) {
+						_, err := s.categories.UpdateCategory(id, name, typ)
+						return repository.ImportChange{Action: repository.ImportUpdated, Category: existing}, err
+					}
+					plan.records = append(plan.records, record)
+					continue
+				case resolutionRename:
+					newID, err := allocator.id(renamedKey(EntityCategory, id))
+					if err != nil {
+						categories[id] = existing.Type()
+						record.reject(OutcomeInvalid, err)
+						plan.records = append(plan.records, record)
+						continue
+					}
+					categoryIDs[id] = newID
+					id = newID
+					record.NewID = newID.String()
+				default:
+					categories[id] = existing.Type()
+					record.reject(OutcomeDuplicate, strategy.keepReason(false))
+					plan.records = append(plan.records, record)
+					continue
+				}
+			}
+
+			categories[id] = typ
+			record.Outcome = OutcomeCreate
+			record.apply = func() (repository.ImportChange, error) {
+				category, err := s.categories.CreateCategoryWithID(id, name, typ)
+				return repository.ImportChange{Action: repository.ImportCreated, Category: category}, err
+			}
+			plan.records = append(plan.records, record)
+		}
+	}
+
+	if s.operations != nil {
+		seen := make(map[domain.ID]struct{})
+		operations := payload.Operations
+		if balanced {
+			operations = append([]filesmodel.Operation(nil), operations...)
+			sort.SliceStable(operations, func(i, j int) bool {
+				return operations[i].Date.Before(operations[j].Date)
+			})
+		}
+		for _, dto := range operations {
+			dto := dto
+			id := domain.ID(strings.TrimSpace(dto.ID))
+			typ := domain.OperationType(strings.ToLower(strings.TrimSpace(dto.Type)))
+			accountID := remapID(accountIDs, domain.ID(strings.TrimSpace(dto.BankAccountID)))
+			categoryID := remapID(categoryIDs, domain.ID(strings.TrimSpace(dto.CategoryID)))
+			description := strings.TrimSpace(dto.Description)
+			record := plannedRecord{RecordOutcome: RecordOutcome{
+				Entity:  EntityOperation,
+				ID:      dto.ID,
+				Label:   operationLabel(dto),
+				Warning: roundingWarning(dto.Rounded),
+			}}
+
+			if _, repeated := seen[id]; repeated && id != "" {
+				record.reject(OutcomeDuplicate, ErrRepeatedID)
+				plan.records = append(plan.records, record)
+				continue
+			}
+
+			op, err := domain.NewOperation(id, typ, accountID, categoryID, dto.Amount, dto.Date, dto.Description)
+			if err != nil {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			seen[id] = struct{}{}
+
+			if !s.accountKnown(accounts, accountID) {
+				record.reject(OutcomeMissingReference, ErrMissingAccount)
+				plan.records = append(plan.records, record)
+				continue
+			}
+
+			categoryType, ok := s.categoryType(categories, categoryID)
+			if !ok {
+				record.reject(OutcomeMissingReference, ErrMissingCategory)
+				plan.records = append(plan.records, record)
+				continue
+			}
+			if categoryType != typ {
+				record.reject(OutcomeInvalid, domain.ErrOperationTypeMismatch)
+				plan.records = append(plan.records, record)
+				continue
+			}
+
+			if existing, err := s.operations.GetOperation(id); err == nil {
+				switch strategy.resolve(!dto.UpdatedAt.IsZero() && dto.UpdatedAt.After(existing.UpdatedAt())) {
+				case resolutionUpdate:
+					if balanced {
+						if err := ledger.move(existing, op); err != nil {
+							record.reject(OutcomeInvalid, err)
+							plan.records = append(plan.records, record)
+							continue
+						}
+					}
+					record.Outcome = OutcomeUpdate
+					record.apply = func() (repository.ImportChange, error) {
+						update := s.operations.UpdateOperationWithoutBalance
+						if balanced {
+							update = s.operations.ReplaceOperation
+						}
+						_, err := update(id, typ, accountID, categoryID, dto.Amount, dto.Date, description, dto.UpdatedAt)
+						return repository.ImportChange{
+							Action:    repository.ImportUpdated,
+							Operation: existing,
+							Balanced:  balanced,
+						}, err
+					}
+					plan.records = append(plan.records, record)
+					continue
+				case resolutionRename:
+					newID, err := allocator.id(renamedKey(EntityOperation, id))
+					if err != nil {
+						record.reject(OutcomeInvalid, err)
+						plan.records = append(plan.records, record)
+						continue
+					}
+					id = newID
+					record.NewID = newID.String()
+				default:
+					record.reject(OutcomeDuplicate, strategy.keepReason(!dto.UpdatedAt.IsZero()))
+					plan.records = append(plan.records, record)
+					continue
+				}
+			}
+
+			if balanced {
+				if err := ledger.apply(op); err != nil {
+					record.reject(OutcomeInvalid, err)
+					plan.records = append(plan.records, record)
+					continue
+				}
+			}
+
+			record.Outcome = OutcomeCreate
+			record.apply = func() (repository.ImportChange, error) {
+				create := s.operations.CreateOperationWithoutBalance
+				if balanced {
+					create = s.operations.CreateOperationWithID
+				}
+				operation, err := create(id, typ, accountID, categoryID, dto.Amount, dto.Date, description, dto.UpdatedAt)
+				return repository.ImportChange{
+					Action:    repository.ImportCreated,
+					Operation: operation,
+					Balanced:  balanced,
+				}, err
+			}
+			plan.records = append(plan.records, record)
+		}
+	}
+
+	if s.views != nil {
+		seen := make(map[domain.ID]struct{})
+		for _, dto := range payload.Views {
+			dto := dto
+			dto.Accounts = remapValues(accountIDs, dto.Accounts)
+			dto.Categories = remapValues(categoryIDs, dto.Categories)
+			id := domain.ID(strings.TrimSpace(dto.ID))
+			record := plannedRecord{RecordOutcome: RecordOutcome{
+				Entity: EntityView,
+				ID:     dto.ID,
+				Label:  strings.TrimSpace(dto.Name),
+			}}
+
+			if _, repeated := seen[id]; repeated && id != "" {
+				record.reject(OutcomeDuplicate, ErrRepeatedID)
+				plan.records = append(plan.records, record)
+				continue
+			}
+
+			criteria := viewCriteria(dto)
+			period, err := viewPeriod(dto)
+			if err != nil {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			if _, err := query.NewSavedView(id, dto.Name, criteria, period); err != nil {
+				record.reject(OutcomeInvalid, invalidReason(id, err))
+				plan.records = append(plan.records, record)
+				continue
+			}
+			seen[id] = struct{}{}
+
+			if existing, err := s.views.GetView(id); err == nil {
+				switch strategy.resolve(false) {
+				case resolutionUpdate:
+					record.Outcome = OutcomeUpdate
+					record.apply = func() (repository.ImportChange, error) {
+						_, err := s.views.UpdateView(id, dto.Name, criteria, period)
+						return repository.ImportChange{Action: repository.ImportUpdated, View: existing}, err
+					}
+					plan.records = append(plan.records, record)
+					continue
+				case resolutionRename:
+					newID, err := allocator.id(renamedKey(EntityView, id))
+					if err != nil {
+						record.reject(OutcomeInvalid, err)
+						plan.records = append(plan.records, record)
+						continue
+					}
+					id = newID
+					record.NewID = newID.String()
+				default:
+					record.reject(OutcomeDuplicate, strategy.keepReason(false))
+					plan.records = append(plan.records, record)
+					continue
+				}
+			}
+
+			record.Outcome = OutcomeCreate
+			record.apply = func() (repository.ImportChange, error) {
+				view, err := s.views.CreateViewWithID(id, dto.Name, criteria, period)
+				return repository.ImportChange{Action: repository.ImportCreated, View: view}, err
+			}
+			plan.records = append(plan.records, record)
+		}
+	}
+
+	if balanced {
+		ledger.finish()
+		plan.balances = ledger.checks()
+	}
+	plan.ids = allocator.assigned
+
+	return plan
+}
+
+func importedProfile(dto filesmodel.Account) (domain.AccountKind, string, error) {
+	kind := domain.AccountKind(strings.ToLower(strings.TrimSpace(dto.Kind)))
+	switch kind {
+	case "", domain.AccountKindAsset, domain.AccountKindCredit:
+		return kind, strings.TrimSpace(dto.Group), nil
+	default:
+		return "", "", domain.ErrInvalidAccountProfile
+	}
+}
+
+func (r *plannedRecord) reject(outcome Outcome, err error) {
+	r.Outcome = outcome
+	r.Err = err
+}
+
+func (s *Service) accountKnown(planned map[domain.ID]bool, id domain.ID) bool {
+	if planned[id] {
+		return true
+	}
+	if s.accounts == nil {
+		return false
+	}
+	_, err := s.accounts.GetAccount(id)
+	return err == nil
+}
+
+func (s *Service) categoryType(planned map[domain.ID]domain.OperationType, id domain.ID) (domain.OperationType, bool) {
+	if typ, ok := planned[id]; ok {
+		return typ, true
+	}
+	if s.categories == nil {
+		return "", false
+	}
+	category, err := s.categories.GetCategory(id)
+	if err != nil {
+		return "", false
+	}
+	return category.Type(), true
+}
+
+func (s *Service) storedAccount(id domain.ID) (*domain.BankAccount, error) {
+	if s.accounts == nil {
+		return nil, domain.ErrNotFound
+	}
+	return s.accounts.GetAccount(id)
+}
+
+func (s *Service) accountKind(id domain.ID) domain.AccountKind {
+	if s.accounts == nil {
+		return domain.AccountKindAsset
+	}
+	profile, err := s.accounts.GetAccountProfile(id)
+	if err != nil {
+		return domain.AccountKindAsset
+	}
+	return profile.Kind()
+}
+
+func (s *Service) freshID() (domain.ID, error) {
+	if s.ids == nil {
+		return "", ErrNoIDGenerator
+	}
+	return s.ids.NewID()
+}
+
+func remapID(mapping map[domain.ID]domain.ID, id domain.ID) domain.ID {
+	if mapped, ok := mapping[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+func remapValues(mapping map[domain.ID]domain.ID, values []string) []string {
+	if len(mapping) == 0 || len(values) == 0 {
+		return values
+	}
+	out := make([]string, len(values))
+	for i, value := range values {
+		out[i] = remapID(mapping, domain.ID(strings.TrimSpace(value))).String()
+	}
+	return out
+}
+
+func invalidReason(id domain.ID, err error) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	return err
+}
+
+func operationLabel(dto filesmodel.Operation) string {
+	label := fmt.Sprintf("%s %d %s", dto.Date.Format("2006-01-02"), dto.Amount, strings.TrimSpace(dto.Type))
+	if description := strings.TrimSpace(dto.Description); description != "" {
+		label += " " + description
+	}
+	return label
+}
+
+func roundingWarning(rounded bool) error {
+	if rounded {
+		return ErrAmountRounded
+	}
+	return nil
+}
+
+func countRecords(records []RecordOutcome, entity Entity, outcome Outcome) int {
+	count := 0
+	for _, record := range records {
+		if record.Entity == entity && record.Outcome == outcome {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,24 @@
+package fileimport
+
+import (
+	"errors"
+	"io"
+
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+var (
+	ErrUnknownProfile     = errors.New("import: unknown statement profile")
+	ErrProfileRequired    = errors.New("import: format requires a statement profile")
+	ErrProfileUnsupported = errors.New("import: format does not support statement profiles")
+)
+
+type ProfileStore interface {
+	List() ([]filesmodel.StatementProfile, error)
+	Get(name string) (filesmodel.StatementProfile, error)
+}
+
+type ProfileImporter interface {
+	Importer
+	ParseWithProfile(reader io.Reader, profile filesmodel.StatementProfile) (filesmodel.Payload, error)
+}
@@ -2,14 +2,19 @@ package fileimport
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"kpo-hw-2/internal/application/facade"
 	"kpo-hw-2/internal/application/files"
 	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
 	filesmodel "kpo-hw-2/internal/files/model"
 )
 
@@ -17,31 +22,53 @@ var (
 	ErrUnknownFormat = errors.New("import: unknown format")
 	ErrInvalidSource = errors.New("import: invalid source")
 	ErrInvalidPath   = errors.New("import: invalid source path")
+	ErrInvalidPeriod = errors.New("import: invalid view period")
 )
 
 type Result struct {
 	CreatedAccounts   int
 	CreatedCategories int
 	CreatedOperations int
+	CreatedViews      int
+
+	UpdatedAccounts   int
+	UpdatedCategories int
+	UpdatedOperations int
+	UpdatedViews      int
 
 	SkippedAccounts   int
 	SkippedCategories int
 	SkippedOperations int
+	SkippedViews      int
+
+	Records  []RecordOutcome
+	Balances []BalanceCheck
+	BatchID  domain.ID
 }
 
 type Service struct {
 	accounts   facade.AccountFacade
 	categories facade.CategoryFacade
 	operations facade.OperationFacade
+	views      facade.SavedViewFacade
+	ids        domain.IDGenerator
+	batches    repository.ImportBatchRepository
+	profiles   ProfileStore
 
 	importers map[string]Importer
 	order     []files.Format
+
+	mu sync.Mutex
 }
 
 func NewService(
 	accountFacade facade.AccountFacade,
 	categoryFacade facade.CategoryFacade,
 	operationFacade facade.OperationFacade,
+	viewFacade facade.SavedViewFacade,
+	idGenerator domain.IDGenerator,
+	batchRepo repository.ImportBatchRepository,
+	profileStore ProfileStore,
 	importers []Importer,
 ) *Service {
 	registry := make(map[string]Importer)
@@ -66,6 +93,10 @@ func NewService(
 		accounts:   accountFacade,
 		categories: categoryFacade,
 		operations: operationFacade,
+		views:      viewFacade,
+		ids:        idGenerator,
+		batches:    batchRepo,
+		profiles:   profileStore,
 		importers:  registry,
 		order:      order,
 	}
@@ -85,7 +116,7 @@ func (s *Service) FormatFor(key string) (files.Format, bool) {
 	return imp.Format(), true
 }
 
-func (s *Service) ImportFromPath(formatKey, path string) (Result, error) {
+func (s *Service) ImportFromPath(formatKey, path string, options Options) (Result, error) {
 	if strings.TrimSpace(path) == "" {
 		return Result{}, ErrInvalidPath
 	}
@@ -96,133 +127,248 @@ func (s *Service) ImportFromPath(formatKey, path string) (Result, error) {
 	}
 	defer file.Close()
 
-	return s.Import(formatKey, file)
+	return s.importFrom(formatKey, path, file, options)
 }
 
-func (s *Service) Import(formatKey string, reader io.Reader) (Result, error) {
-	if reader == nil {
-		return Result{}, ErrInvalidSource
-	}
+func (s *Service) Import(formatKey string, reader io.Reader, options Options) (Result, error) {
+	return s.importFrom(formatKey, "", reader, options)
+}
 
-	importer, ok := s.importers[formatKey]
-	if !ok {
-		return Result{}, ErrUnknownFormat
+func (s *Service) importFrom(formatKey, source string, reader io.Reader, options Options) (Result, error) {
+	if err := options.validate(); err != nil {
+		return Result{}, err
 	}
 
-	data, err := io.ReadAll(reader)
+	payload, err := s.parse(formatKey, reader, options)
 	if err != nil {
 		return Result{}, err
 	}
 
-	payload, err := importer.Parse(data)
+	batch := repository.ImportBatch{
+		Format:   formatKey,
+		Source:   source,
+		Strategy: string(options.Strategy),
+		Mode:     string(options.Mode),
+	}
+	return s.applyPayload(payload, options, batch)
+}
+
+func (s *Service) PreviewFromPath(formatKey, path string, options Options) (Preview, error) {
+	if strings.TrimSpace(path) == "" {
+		return Preview{}, ErrInvalidPath
+	}
+
+	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
-		return Result{}, err
+		return Preview{}, err
 	}
+	defer file.Close()
 
-	return s.applyPayload(payload)
+	return s.Preview(formatKey, file, options)
 }
 
-func (s *Service) applyPayload(payload filesmodel.Payload) (Result, error) {
-	var result Result
+func (s *Service) Preview(formatKey string, reader io.Reader, options Options) (Preview, error) {
+	if err := options.validate(); err != nil {
+		return Preview{}, err
+	}
 
-	accountIDs := make(map[string]domain.ID)
-	categoryIDs := make(map[string]domain.ID)
+	payload, err := s.parse(formatKey, reader, options)
+	if err != nil {
+		return Preview{}, err
+	}
 
-	if s.accounts != nil {
-		for _, dto := range payload.Accounts {
-			name := strings.TrimSpace(dto.Name)
-			id := domain.ID(strings.TrimSpace(dto.ID))
-			if id == "" {
-				result.SkippedAccounts++
-				continue
-			}
+	plan := s.planPayload(payload, options)
+	return Preview{Records: plan.outcomes(), Balances: plan.balances, IDs: plan.ids}, nil
+}
 
-			account, err := s.accounts.CreateAccountWithID(id, name, dto.Balance)
-			if err != nil {
-				if errors.Is(err, domain.ErrAlreadyExists) {
-					accountIDs[dto.ID] = id
-					result.SkippedAccounts++
-					continue
-				}
+func (s *Service) Profiles() ([]filesmodel.StatementProfile, error) {
+	if s.profiles == nil {
+		return nil, nil
+	}
+	return s.profiles.List()
+}
 
-				result.SkippedAccounts++
-				continue
-			}
+func (s *Service) parse(formatKey string, reader io.Reader, options Options) (filesmodel.Payload, error) {
+	if reader == nil {
+		return filesmodel.Payload{}, ErrInvalidSource
+	}
 
-			accountIDs[dto.ID] = account.ID()
-			result.CreatedAccounts++
-		}
+	importer, ok := s.importers[formatKey]
+	if !ok {
+		return filesmodel.Payload{}, ErrUnknownFormat
 	}
 
-	if s.categories != nil {
-		for _, dto := range payload.Categories {
-			name := strings.TrimSpace(dto.Name)
-			typ := domain.OperationType(strings.ToLower(strings.TrimSpace(dto.Type)))
-			id := domain.ID(strings.TrimSpace(dto.ID))
-			if id == "" {
-				result.SkippedCategories++
-				continue
+	profiled, supportsProfiles := importer.(ProfileImporter)
+	if strings.TrimSpace(options.Profile) == "" {
+		if options.Dialect.IsZero() {
+			if streamer, ok := importer.(StreamImporter); ok {
+				return Collect(func(visitor RecordVisitor) error {
+					return streamer.Stream(reader, visitor)
+				})
 			}
+			return importer.Parse(reader)
+		}
+		dialected, ok := importer.(DialectImporter)
+		if !ok {
+			return filesmodel.Payload{}, ErrDialectUnsupported
+		}
+		return dialected.ParseWithDialect(reader, options.Dialect)
+	}
+	if !supportsProfiles {
+		return filesmodel.Payload{}, ErrProfileUnsupported
+	}
+	if s.profiles == nil {
+		return filesmodel.Payload{}, ErrUnknownProfile
+	}
 
-			category, err := s.categories.CreateCategoryWithID(id, name, typ)
-			if err != nil {
-				if errors.Is(err, domain.ErrAlreadyExists) {
-					categoryIDs[dto.ID] = id
-					result.SkippedCategories++
-					continue
-				}
+	profile, err := s.profiles.Get(options.Profile)
+	if err != nil {
+		return filesmodel.Payload{}, err
+	}
+	if encoding := strings.TrimSpace(options.Dialect.Encoding); encoding != "" {
+		profile.Encoding = encoding
+	}
+	if options.Dialect.Delimiter != "" {
+		profile.Delimiter = options.Dialect.Delimiter
+	}
 
-				result.SkippedCategories++
-				continue
-			}
+	return profiled.ParseWithProfile(reader, profile)
+}
+
+func (s *Service) applyPayload(payload filesmodel.Payload, options Options, batch repository.ImportBatch) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-			categoryIDs[dto.ID] = category.ID()
-			result.CreatedCategories++
+	if s.batches != nil {
+		id, err := s.freshID()
+		if err != nil {
+			return Result{}, err
 		}
+		batch.ID = id
 	}
 
-	if s.operations != nil {
-		for _, dto := range payload.Operations {
-			typ := domain.OperationType(strings.ToLower(strings.TrimSpace(dto.Type)))
-			id := domain.ID(strings.TrimSpace(dto.ID))
-			if id == "" {
-				result.SkippedOperations++
-				continue
-			}
-
-			accountID, ok := accountIDs[dto.BankAccountID]
-			if !ok {
-				result.SkippedOperations++
-				continue
-			}
+	plan := s.planPayload(payload, options)
 
-			categoryID, ok := categoryIDs[dto.CategoryID]
-			if !ok {
-				result.SkippedOperations++
-				continue
-			}
+	for i := range plan.records {
+		record := &plan.records[i]
+		if record.Outcome != OutcomeCreate && record.Outcome != OutcomeUpdate {
+			continue
+		}
+		change, err := record.apply()
+		if err != nil {
+			abort := fmt.Errorf("%w: %s %s: %w", ErrImportAborted, record.Entity, record.ID, err)
+			return Result{}, s.discard(batch.Changes, abort)
+		}
+		batch.Changes = append(batch.Changes, change)
+	}
 
-			if _, err := s.operations.CreateOperationWithoutBalance(
-				id,
-				typ,
-				accountID,
-				categoryID,
-				dto.Amount,
-				dto.Date,
-				strings.TrimSpace(dto.Description),
-			); err != nil {
-				if errors.Is(err, domain.ErrAlreadyExists) {
-					result.SkippedOperations++
-					continue
-				}
-
-				result.SkippedOperations++
-				continue
-			}
+	result := newResult(plan.outcomes())
+	result.Balances = plan.balances
+	if s.batches == nil {
+		return result, nil
+	}
 
-			result.CreatedOperations++
+	for i := range batch.Changes {
+		state, err := s.currentState(batch.Changes[i])
+		if err != nil {
+			return Result{}, s.discard(batch.Changes, err)
 		}
+		batch.Changes[i].Applied = state
+		batch.Changes[i].Recorded = true
 	}
 
+	batch.CreatedAt = time.Now()
+	if err := s.batches.Save(batch); err != nil {
+		return Result{}, s.discard(batch.Changes, err)
+	}
+	result.BatchID = batch.ID
+
 	return result, nil
 }
+
+func newResult(records []RecordOutcome) Result {
+	result := Result{Records: records}
+	for _, record := range records {
+		switch record.Entity {
+		case EntityAccount:
+			tally(record.Outcome, &result.CreatedAccounts, &result.UpdatedAccounts, &result.SkippedAccounts)
+		case EntityCategory:
+			tally(record.Outcome, &result.CreatedCategories, &result.UpdatedCategories, &result.SkippedCategories)
+		case EntityOperation:
+			tally(record.Outcome, &result.CreatedOperations, &result.UpdatedOperations, &result.SkippedOperations)
+		case EntityView:
+			tally(record.Outcome, &result.CreatedViews, &result.UpdatedViews, &result.SkippedViews)
+		}
+	}
+	return result
+}
+
+func tally(outcome Outcome, created, updated, skipped *int) {
+	switch outcome {
+	case OutcomeCreate:
+		*created++
+	case OutcomeUpdate:
+		*updated++
+	default:
+		*skipped++
+	}
+}
+
+func viewCriteria(dto filesmodel.SavedView) query.OperationFilter {
+	filter := query.NewOperationFilter().
+		WithAccounts(idSelector(dto.Accounts, dto.ExcludeAccounts)).
+		WithCategories(idSelector(dto.Categories, dto.ExcludeCategories))
+
+	types := make([]domain.OperationType, 0, len(dto.Types))
+	for _, typ := range dto.Types {
+		types = append(types, domain.OperationType(strings.ToLower(strings.TrimSpace(typ))))
+	}
+	if dto.ExcludeTypes {
+		filter = filter.ExcludeTypes(types...)
+	} else {
+		filter = filter.OfTypes(types...)
+	}
+
+	if dto.MinAmount != nil {
+		filter = filter.MinAmount(*dto.MinAmount)
+	}
+	if dto.MaxAmount != nil {
+		filter = filter.MaxAmount(*dto.MaxAmount)
+	}
+
+	return filter.Containing(dto.Texts...)
+}
+
+func idSelector(values []string, exclude bool) query.Selector[domain.ID] {
+	ids := make([]domain.ID, 0, len(values))
+	for _, value := range values {
+		ids = append(ids, domain.ID(strings.TrimSpace(value)))
+	}
+	if exclude {
+		return query.Exclude(ids...)
+	}
+	return query.Include(ids...)
+}
+
+func viewPeriod(dto filesmodel.SavedView) (query.PeriodSpec, error) {
+	switch kind := query.PeriodKind(strings.TrimSpace(dto.Period)); kind {
+	case query.PeriodAll:
+		return query.AllTime(), nil
+	case query.PeriodCustom:
+		if dto.From == nil && dto.To == nil {
+			return query.PeriodSpec{}, ErrInvalidPeriod
+		}
+		return query.CustomPeriod(dto.From, dto.To), nil
+	case query.PeriodLastDays:
+		if dto.PeriodDays <= 0 {
+			return query.PeriodSpec{}, ErrInvalidPeriod
+		}
+		return query.LastDays(dto.PeriodDays), nil
+	default:
+		period := query.RelativePeriod(kind)
+		if period.Kind() != kind {
+			return query.PeriodSpec{}, ErrInvalidPeriod
+		}
+		return period, nil
+	}
+}
@@ -0,0 +1,191 @@
+package fileimport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+func TestViewPeriod(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		view    filesmodel.SavedView
+		kind    query.PeriodKind
+		wantErr bool
+	}{
+		{name: "all time", view: filesmodel.SavedView{}, kind: query.PeriodAll},
+		{name: "relative", view: filesmodel.SavedView{Period: "this_month"}, kind: query.PeriodThisMonth},
+		{name: "last days", view: filesmodel.SavedView{Period: "last_days", PeriodDays: 7}, kind: query.PeriodLastDays},
+		{name: "custom", view: filesmodel.SavedView{Period: "custom", From: &from}, kind: query.PeriodCustom},
+		{name: "unknown kind", view: filesmodel.SavedView{Period: "fortnight"}, wantErr: true},
+		{name: "zero days", view: filesmodel.SavedView{Period: "last_days"}, wantErr: true},
+		{name: "negative days", view: filesmodel.SavedView{Period: "last_days", PeriodDays: -3}, wantErr: true},
+		{name: "custom without bounds", view: filesmodel.SavedView{Period: "custom"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, err := viewPeriod(tt.view)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPeriod) {
+					t.Fatalf("viewPeriod() error = %v, want %v", err, ErrInvalidPeriod)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("viewPeriod() error = %v", err)
+			}
+			if period.Kind() != tt.kind {
+				t.Fatalf("viewPeriod() kind = %q, want %q", period.Kind(), tt.kind)
+			}
+		})
+	}
+}
+
+func TestPreviewMarksCorruptViewPeriodInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	payload := filesmodel.Payload{Views: []filesmodel.SavedView{
+		{ID: mustID(t).String(), Name: "Неделя", Period: "fortnight"},
+		{ID: mustID(t).String(), Name: "Ноль дней", Period: "last_days"},
+		{ID: mustID(t).String(), Name: "Месяц", Period: "this_month"},
+	}}
+
+	plan := f.service.planPayload(payload, DefaultOptions())
+	records := plan.outcomes()
+	want := []Outcome{OutcomeInvalid, OutcomeInvalid, OutcomeCreate}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i, record := range records {
+		if record.Outcome != want[i] {
+			t.Errorf("record %q outcome = %q, want %q", record.Label, record.Outcome, want[i])
+		}
+	}
+	if !errors.Is(records[0].Err, ErrInvalidPeriod) {
+		t.Errorf("record error = %v, want %v", records[0].Err, ErrInvalidPeriod)
+	}
+}
+
+func TestPreviewWarnsAboutRoundedAmounts(t *testing.T) {
+	f := newFixture(t)
+
+	accountID := mustID(t).String()
+	categoryID := mustID(t).String()
+	date := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)
+	payload := filesmodel.Payload{
+		Accounts:   []filesmodel.Account{{ID: accountID, Name: "Текущий", Balance: 83431, Rounded: true}},
+		Categories: []filesmodel.Category{{ID: categoryID, Type: "expense", Name: "Продукты"}},
+		Operations: []filesmodel.Operation{
+			{ID: mustID(t).String(), Type: "expense", BankAccountID: accountID, CategoryID: categoryID, Amount: 1250, Date: date, Rounded: true},
+			{ID: mustID(t).String(), Type: "expense", BankAccountID: accountID, CategoryID: categoryID, Amount: 320, Date: date},
+		},
+	}
+
+	records := f.service.planPayload(payload, DefaultOptions()).outcomes()
+	want := []error{ErrAmountRounded, nil, ErrAmountRounded, nil}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i, record := range records {
+		if record.Outcome != OutcomeCreate {
+			t.Errorf("record %q outcome = %q, want %q", record.Label, record.Outcome, OutcomeCreate)
+		}
+		if !errors.Is(record.Warning, want[i]) {
+			t.Errorf("record %q warning = %v, want %v", record.Label, record.Warning, want[i])
+		}
+	}
+}
+
+func TestNegativeStatedBalanceByMode(t *testing.T) {
+	tests := []struct {
+		mode     Mode
+		want     Outcome
+		balances int
+	}{
+		{mode: ModeSnapshot, want: OutcomeInvalid},
+		{mode: ModeOpeningBalance, want: OutcomeCreate, balances: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			f := newFixture(t)
+			payload := filesmodel.Payload{Accounts: []filesmodel.Account{{ID: mustID(t).String(), Name: "Карта", Balance: -300}}}
+
+			options := DefaultOptions()
+			options.Mode = tt.mode
+			plan := f.service.planPayload(payload, options)
+			records := plan.outcomes()
+			if len(records) != 1 || records[0].Outcome != tt.want {
+				t.Fatalf("records = %+v, want outcome %q", records, tt.want)
+			}
+			if len(plan.balances) != tt.balances {
+				t.Fatalf("balances = %+v, want %d", plan.balances, tt.balances)
+			}
+			if tt.balances > 0 && !plan.balances[0].Mismatch() {
+				t.Fatalf("negative asset balance should be reported as a mismatch: %+v", plan.balances[0])
+			}
+		})
+	}
+}
+
+func TestAccountKindDecidesValidity(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		account   Outcome
+		operation Outcome
+	}{
+		{name: "credit", kind: "credit", account: OutcomeCreate, operation: OutcomeCreate},
+		{name: "asset", kind: "", account: OutcomeInvalid, operation: OutcomeMissingReference},
+		{name: "unknown kind", kind: "loan", account: OutcomeInvalid, operation: OutcomeMissingReference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountID, categoryID := mustID(t), mustID(t)
+			payload := filesmodel.Payload{
+				Accounts:   []filesmodel.Account{{ID: accountID.String(), Name: "Кредитка", Balance: -500, Kind: tt.kind, Group: "Карты"}},
+				Categories: []filesmodel.Category{{ID: categoryID.String(), Type: "expense", Name: "Еда"}},
+				Operations: []filesmodel.Operation{{
+					ID:            mustID(t).String(),
+					Type:          "expense",
+					BankAccountID: accountID.String(),
+					CategoryID:    categoryID.String(),
+					Amount:        500,
+					Date:          time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+				}},
+			}
+			f := newFixture(t, payloadImporter{payload: payload})
+
+			result, err := f.service.Import("payload", strings.NewReader(""), DefaultOptions())
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if got := result.Records[0].Outcome; got != tt.account {
+				t.Errorf("account outcome = %q, want %q", got, tt.account)
+			}
+			if got := result.Records[2].Outcome; got != tt.operation {
+				t.Errorf("operation outcome = %q, want %q", got, tt.operation)
+			}
+
+			if tt.account != OutcomeCreate {
+				return
+			}
+			profile, err := f.accounts.GetAccountProfile(accountID)
+			if err != nil {
+				t.Fatalf("profile: %v", err)
+			}
+			if profile.Kind() != domain.AccountKindCredit || profile.Group() != "Карты" {
+				t.Errorf("profile = %s/%q, want credit/Карты", profile.Kind(), profile.Group())
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package fileimport
+
+import "errors"
+
+type Strategy string
+
+const (
+	StrategySkip        Strategy = "skip"
+	StrategyOverwrite   Strategy = "overwrite"
+	StrategyKeepNewest  Strategy = "keep_newest"
+	StrategyImportAsNew Strategy = "import_as_new"
+)
+
+var ErrUnknownStrategy = errors.New("import: unknown conflict strategy")
+
+func Strategies() []Strategy {
+	return []Strategy{StrategySkip, StrategyOverwrite, StrategyKeepNewest, StrategyImportAsNew}
+}
+
+func (s Strategy) Valid() bool {
+	switch s {
+	case StrategySkip, StrategyOverwrite, StrategyKeepNewest, StrategyImportAsNew:
+		return true
+	default:
+		return false
+	}
+}
+
+type resolution int
+
+const (
+	resolutionKeep resolution = iota
+	resolutionUpdate
+	resolutionRename
+)
+
+func (s Strategy) keepReason(timestamped bool) error {
+	switch {
+	case s != StrategyKeepNewest:
+		return ErrExistingRecord
+	case !timestamped:
+		return ErrNoTimestamp
+	default:
+		return ErrStoredNewer
+	}
+}
+
+func (s Strategy) resolve(newer bool) resolution {
+	switch s {
+	case StrategyOverwrite:
+		return resolutionUpdate
+	case StrategyKeepNewest:
+		if newer {
+			return resolutionUpdate
+		}
+	case StrategyImportAsNew:
+		return resolutionRename
+	}
+	return resolutionKeep
+}
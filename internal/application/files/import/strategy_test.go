@@ -0,0 +1,269 @@
+package fileimport
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+func TestStrategyResolve(t *testing.T) {
+	tests := []struct {
+		strategy Strategy
+		newer    bool
+		want     resolution
+	}{
+		{strategy: StrategySkip, want: resolutionKeep},
+		{strategy: StrategySkip, newer: true, want: resolutionKeep},
+		{strategy: StrategyOverwrite, want: resolutionUpdate},
+		{strategy: StrategyKeepNewest, want: resolutionKeep},
+		{strategy: StrategyKeepNewest, newer: true, want: resolutionUpdate},
+		{strategy: StrategyImportAsNew, want: resolutionRename},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy.resolve(tt.newer); got != tt.want {
+			t.Errorf("%s.resolve(%v) = %v, want %v", tt.strategy, tt.newer, got, tt.want)
+		}
+	}
+}
+
+func TestStrategyKeepReason(t *testing.T) {
+	tests := []struct {
+		strategy    Strategy
+		timestamped bool
+		want        error
+	}{
+		{strategy: StrategySkip, want: ErrExistingRecord},
+		{strategy: StrategySkip, timestamped: true, want: ErrExistingRecord},
+		{strategy: StrategyKeepNewest, want: ErrNoTimestamp},
+		{strategy: StrategyKeepNewest, timestamped: true, want: ErrStoredNewer},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy.keepReason(tt.timestamped); !errors.Is(got, tt.want) {
+			t.Errorf("%s.keepReason(%v) = %v, want %v", tt.strategy, tt.timestamped, got, tt.want)
+		}
+	}
+}
+
+func TestKeepNewestComparesUpdateTime(t *testing.T) {
+	businessDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		updatedAt func(stored time.Time) time.Time
+		want      Outcome
+		wantErr   error
+	}{
+		{
+			name:      "edited later with older business date",
+			date:      businessDate.AddDate(0, -1, 0),
+			updatedAt: func(stored time.Time) time.Time { return stored.Add(time.Hour) },
+			want:      OutcomeUpdate,
+		},
+		{
+			name:      "later business date but stale edit",
+			date:      businessDate.AddDate(0, 1, 0),
+			updatedAt: func(stored time.Time) time.Time { return stored.Add(-time.Hour) },
+			want:      OutcomeDuplicate,
+			wantErr:   ErrStoredNewer,
+		},
+		{
+			name:      "no timestamp in file",
+			date:      businessDate.AddDate(1, 0, 0),
+			updatedAt: func(time.Time) time.Time { return time.Time{} },
+			want:      OutcomeDuplicate,
+			wantErr:   ErrNoTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			account, err := f.accounts.CreateAccountWithID(mustID(t), "Счёт", 1000)
+			if err != nil {
+				t.Fatalf("account: %v", err)
+			}
+			category, err := f.categories.CreateCategoryWithID(mustID(t), "Еда", domain.OperationTypeExpense)
+			if err != nil {
+				t.Fatalf("category: %v", err)
+			}
+			stored, err := f.operations.CreateOperationWithoutBalance(
+				mustID(t), domain.OperationTypeExpense, account.ID(), category.ID(), 100, businessDate, "", time.Now(),
+			)
+			if err != nil {
+				t.Fatalf("operation: %v", err)
+			}
+
+			payload := filesmodel.Payload{
+				Accounts: []filesmodel.Account{{ID: account.ID().String(), Name: "Другое имя", Balance: 5}},
+				Operations: []filesmodel.Operation{{
+					ID:            stored.ID().String(),
+					Type:          string(domain.OperationTypeExpense),
+					BankAccountID: account.ID().String(),
+					CategoryID:    category.ID().String(),
+					Amount:        200,
+					Date:          tt.date,
+					UpdatedAt:     tt.updatedAt(stored.UpdatedAt()),
+				}},
+			}
+
+			options := DefaultOptions()
+			options.Strategy = StrategyKeepNewest
+			records := f.service.planPayload(payload, options).outcomes()
+			if len(records) != 2 {
+				t.Fatalf("got %d records, want 2", len(records))
+			}
+
+			if records[0].Outcome != OutcomeDuplicate || !errors.Is(records[0].Err, ErrNoTimestamp) {
+				t.Errorf("account outcome = %s (%v), want duplicate without timestamp", records[0].Outcome, records[0].Err)
+			}
+			if records[1].Outcome != tt.want {
+				t.Errorf("operation outcome = %s, want %s", records[1].Outcome, tt.want)
+			}
+			if !errors.Is(records[1].Err, tt.wantErr) {
+				t.Errorf("operation error = %v, want %v", records[1].Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportAppliesStrategy(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		strategy Strategy
+		account  string
+		amounts  []int64
+	}{
+		{strategy: StrategySkip, account: "Счёт", amounts: []int64{100}},
+		{strategy: StrategyOverwrite, account: "Другое имя", amounts: []int64{200}},
+		{strategy: StrategyKeepNewest, account: "Счёт", amounts: []int64{200}},
+		{strategy: StrategyImportAsNew, account: "Счёт", amounts: []int64{100, 200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			accountID, categoryID, operationID := mustID(t), mustID(t), mustID(t)
+			payload := filesmodel.Payload{
+				Accounts: []filesmodel.Account{{ID: accountID.String(), Name: "Другое имя", Balance: 800}},
+				Operations: []filesmodel.Operation{{
+					ID:            operationID.String(),
+					Type:          string(domain.OperationTypeExpense),
+					BankAccountID: accountID.String(),
+					CategoryID:    categoryID.String(),
+					Amount:        200,
+					Date:          date,
+					UpdatedAt:     time.Now().Add(time.Hour),
+				}},
+			}
+			f := newFixture(t, payloadImporter{payload: payload})
+			if _, err := f.accounts.CreateAccountWithID(accountID, "Счёт", 900); err != nil {
+				t.Fatalf("account: %v", err)
+			}
+			if _, err := f.categories.CreateCategoryWithID(categoryID, "Еда", domain.OperationTypeExpense); err != nil {
+				t.Fatalf("category: %v", err)
+			}
+			if _, err := f.operations.CreateOperationWithoutBalance(operationID, domain.OperationTypeExpense, accountID, categoryID, 100, date, "", time.Now()); err != nil {
+				t.Fatalf("operation: %v", err)
+			}
+
+			options := DefaultOptions()
+			options.Strategy = tt.strategy
+			if _, err := f.service.Import("payload", strings.NewReader(""), options); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+
+			account, err := f.accounts.GetAccount(accountID)
+			if err != nil {
+				t.Fatalf("account: %v", err)
+			}
+			if account.Name() != tt.account {
+				t.Errorf("account name = %q, want %q", account.Name(), tt.account)
+			}
+
+			operations, err := f.operations.ListOperationsWithFilter(query.NewOperationFilter())
+			if err != nil {
+				t.Fatalf("list operations: %v", err)
+			}
+			var amounts []int64
+			for _, op := range operations {
+				amounts = append(amounts, op.Amount())
+			}
+			slices.Sort(amounts)
+			if !slices.Equal(amounts, tt.amounts) {
+				t.Errorf("operation amounts = %v, want %v", amounts, tt.amounts)
+			}
+		})
+	}
+}
+
+func TestKeepNewestUsesImportedUpdateTime(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	edited := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		updatedAt time.Time
+		amount    int64
+	}{
+		{name: "later edit replaces the re-imported one", updatedAt: edited.Add(time.Hour), amount: 200},
+		{name: "same edit is kept", updatedAt: edited, amount: 100},
+		{name: "older edit is kept", updatedAt: edited.Add(-time.Hour), amount: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountID, categoryID, operationID := mustID(t), mustID(t), mustID(t)
+			operation := filesmodel.Operation{
+				ID:            operationID.String(),
+				Type:          string(domain.OperationTypeExpense),
+				BankAccountID: accountID.String(),
+				CategoryID:    categoryID.String(),
+				Amount:        100,
+				Date:          date,
+				UpdatedAt:     edited,
+			}
+			importer := &payloadImporter{payload: filesmodel.Payload{
+				Accounts:   []filesmodel.Account{{ID: accountID.String(), Name: "Счёт", Balance: 900}},
+				Categories: []filesmodel.Category{{ID: categoryID.String(), Name: "Еда", Type: string(domain.OperationTypeExpense)}},
+				Operations: []filesmodel.Operation{operation},
+			}}
+			f := newFixture(t, importer)
+
+			options := DefaultOptions()
+			options.Strategy = StrategyKeepNewest
+			if _, err := f.service.Import("payload", strings.NewReader(""), options); err != nil {
+				t.Fatalf("first import: %v", err)
+			}
+			stored, err := f.operations.GetOperation(operationID)
+			if err != nil {
+				t.Fatalf("operation: %v", err)
+			}
+			if !stored.UpdatedAt().Equal(edited) {
+				t.Fatalf("stored updated at = %s, want %s from the file", stored.UpdatedAt(), edited)
+			}
+
+			operation.Amount = 200
+			operation.UpdatedAt = tt.updatedAt
+			importer.payload = filesmodel.Payload{Operations: []filesmodel.Operation{operation}}
+			if _, err := f.service.Import("payload", strings.NewReader(""), options); err != nil {
+				t.Fatalf("second import: %v", err)
+			}
+			stored, err = f.operations.GetOperation(operationID)
+			if err != nil {
+				t.Fatalf("operation: %v", err)
+			}
+			if stored.Amount() != tt.amount {
+				t.Errorf("amount = %d, want %d", stored.Amount(), tt.amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package files
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func WriteFile(path string, write func(io.Writer) error) (err error) {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
+			return mkErr
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return write(file)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "strings"
+
+type AccountKind string
+
+const (
+	AccountKindAsset  AccountKind = "asset"
+	AccountKindCredit AccountKind = "credit"
+)
+
+func (k AccountKind) AllowsNegativeBalance() bool {
+	return k == AccountKindCredit
+}
+
+type AccountProfile struct {
+	accountID ID
+	kind      AccountKind
+	group     string
+}
+
+func NewAccountProfile(accountID ID, kind AccountKind, group string) (*AccountProfile, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccountProfile
+	}
+
+	switch kind {
+	case AccountKindAsset, AccountKindCredit:
+	default:
+		return nil, ErrInvalidAccountProfile
+	}
+
+	return &AccountProfile{
+		accountID: accountID,
+		kind:      kind,
+		group:     strings.TrimSpace(group),
+	}, nil
+}
+
+func DefaultAccountProfile(accountID ID) *AccountProfile {
+	return &AccountProfile{
+		accountID: accountID,
+		kind:      AccountKindAsset,
+	}
+}
+
+func (p *AccountProfile) AccountID() ID { return p.accountID }
+
+func (p *AccountProfile) Kind() AccountKind { return p.kind }
+
+func (p *AccountProfile) Group() string { return p.group }
@@ -18,10 +18,6 @@ func NewBankAccount(id ID, name string, balance int64) (*BankAccount, error) {
 		return nil, ErrInvalidBankAccount
 	}
 
-	if balance < 0 {
-		return nil, ErrInvalidBankAccount
-	}
-
 	return &BankAccount{
 		id:      id,
 		name:    name,
@@ -35,7 +31,18 @@ func (b *BankAccount) Name() string { return b.name }
 
 func (b *BankAccount) Balance() int64 { return b.balance }
 
+func (b *BankAccount) CheckBalance(kind AccountKind) error {
+	if b.balance < 0 && !kind.AllowsNegativeBalance() {
+		return ErrInvalidBankAccount
+	}
+	return nil
+}
+
 func (b *BankAccount) ApplyOperation(operation *Operation) error {
+	return b.ApplyOperationAs(AccountKindAsset, operation)
+}
+
+func (b *BankAccount) ApplyOperationAs(kind AccountKind, operation *Operation) error {
 	if operation == nil {
 		return ErrInvalidOperation
 	}
@@ -46,7 +53,7 @@ func (b *BankAccount) ApplyOperation(operation *Operation) error {
 		return nil
 	case OperationTypeExpense:
 		amount := operation.Amount()
-		if amount > b.balance {
+		if amount > b.balance && !kind.AllowsNegativeBalance() {
 			return ErrInsufficientFunds
 		}
 		b.balance -= amount
@@ -57,6 +64,10 @@ func (b *BankAccount) ApplyOperation(operation *Operation) error {
 }
 
 func (b *BankAccount) RevertOperation(operation *Operation) error {
+	return b.RevertOperationAs(AccountKindAsset, operation)
+}
+
+func (b *BankAccount) RevertOperationAs(kind AccountKind, operation *Operation) error {
 	if operation == nil {
 		return ErrInvalidOperation
 	}
@@ -64,7 +75,7 @@ func (b *BankAccount) RevertOperation(operation *Operation) error {
 	switch operation.Type() {
 	case OperationTypeIncome:
 		amount := operation.Amount()
-		if amount > b.balance {
+		if amount > b.balance && !kind.AllowsNegativeBalance() {
 			return ErrInvalidOperation
 		}
 		b.balance -= amount
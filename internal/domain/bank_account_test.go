@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBankAccountApplyOperationAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    AccountKind
+		balance int64
+		typ     OperationType
+		amount  int64
+		revert  bool
+		want    int64
+		wantErr error
+	}{
+		{name: "asset income", kind: AccountKindAsset, balance: 100, typ: OperationTypeIncome, amount: 50, want: 150},
+		{name: "asset expense", kind: AccountKindAsset, balance: 100, typ: OperationTypeExpense, amount: 100, want: 0},
+		{name: "asset overdraft", kind: AccountKindAsset, balance: 100, typ: OperationTypeExpense, amount: 101, want: 100, wantErr: ErrInsufficientFunds},
+		{name: "credit spending goes negative", kind: AccountKindCredit, balance: 0, typ: OperationTypeExpense, amount: 500, want: -500},
+		{name: "credit repayment", kind: AccountKindCredit, balance: -500, typ: OperationTypeIncome, amount: 200, want: -300},
+		{name: "asset revert income below zero", kind: AccountKindAsset, balance: 10, typ: OperationTypeIncome, amount: 20, revert: true, want: 10, wantErr: ErrInvalidOperation},
+		{name: "credit revert income below zero", kind: AccountKindCredit, balance: 10, typ: OperationTypeIncome, amount: 20, revert: true, want: -10},
+		{name: "credit revert expense", kind: AccountKindCredit, balance: -500, typ: OperationTypeExpense, amount: 500, revert: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := NewBankAccount("A", "Карта", tt.balance)
+			if err != nil {
+				t.Fatalf("NewBankAccount: %v", err)
+			}
+			op, err := NewOperation("O", tt.typ, "A", "C", tt.amount, time.Now(), "")
+			if err != nil {
+				t.Fatalf("NewOperation: %v", err)
+			}
+
+			if tt.revert {
+				err = account.RevertOperationAs(tt.kind, op)
+			} else {
+				err = account.ApplyOperationAs(tt.kind, op)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if account.Balance() != tt.want {
+				t.Fatalf("balance = %d, want %d", account.Balance(), tt.want)
+			}
+		})
+	}
+}
+
+func TestBankAccountCheckBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    AccountKind
+		balance int64
+		wantErr error
+	}{
+		{name: "asset positive", kind: AccountKindAsset, balance: 1},
+		{name: "asset negative", kind: AccountKindAsset, balance: -1, wantErr: ErrInvalidBankAccount},
+		{name: "credit negative", kind: AccountKindCredit, balance: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := NewBankAccount("A", "Счёт", tt.balance)
+			if err != nil {
+				t.Fatalf("NewBankAccount: %v", err)
+			}
+			if err := account.CheckBalance(tt.kind); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckBalance = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,8 +7,11 @@ var (
 	ErrInvalidBankAccount    = errors.New("invalid bank account")
 	ErrInvalidCategory       = errors.New("invalid category")
 	ErrInvalidOperation      = errors.New("invalid operation")
+	ErrInvalidSavedView      = errors.New("invalid saved view")
+	ErrInvalidAccountProfile = errors.New("invalid account profile")
 	ErrInsufficientFunds     = errors.New("insufficient funds")
 	ErrOperationTypeMismatch = errors.New("operation type mismatch")
 	ErrNotFound              = errors.New("not found")
 	ErrAlreadyExists         = errors.New("already exists")
+	ErrUnsupportedFilter     = errors.New("unsupported filter")
 )
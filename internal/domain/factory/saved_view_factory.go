@@ -0,0 +1,32 @@
+package factory
+
+import (
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+type SavedViewFactory interface {
+	Create(name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error)
+	Rebuild(id domain.ID, name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error)
+}
+
+func NewSavedViewFactory(idGenerator domain.IDGenerator) SavedViewFactory {
+	return &savedViewFactory{idGenerator: idGenerator}
+}
+
+type savedViewFactory struct {
+	idGenerator domain.IDGenerator
+}
+
+func (f *savedViewFactory) Create(name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error) {
+	id, err := f.idGenerator.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	return query.NewSavedView(id, name, filter, period)
+}
+
+func (f *savedViewFactory) Rebuild(id domain.ID, name string, filter query.OperationFilter, period query.PeriodSpec) (*query.SavedView, error) {
+	return query.NewSavedView(id, name, filter, period)
+}
@@ -13,6 +13,7 @@ type Operation struct {
 	amount        int64
 	date          time.Time
 	description   string
+	updatedAt     time.Time
 }
 
 func NewOperation(
@@ -64,3 +65,9 @@ func (o *Operation) Amount() int64 { return o.amount }
 func (o *Operation) Date() time.Time { return o.date }
 
 func (o *Operation) Description() string { return o.description }
+
+func (o *Operation) UpdatedAt() time.Time { return o.updatedAt }
+
+func (o *Operation) Touch(at time.Time) {
+	o.updatedAt = at
+}
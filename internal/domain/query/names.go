@@ -0,0 +1,62 @@
+package query
+
+import (
+	"strings"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type NameResolver interface {
+	AccountIDs(name string) []domain.ID
+	CategoryIDs(name string) []domain.ID
+}
+
+type NameIndex struct {
+	accounts   map[string][]domain.ID
+	categories map[string][]domain.ID
+}
+
+func NewNameIndex(accounts []*domain.BankAccount, categories []*domain.Category) *NameIndex {
+	index := &NameIndex{
+		accounts:   make(map[string][]domain.ID, len(accounts)),
+		categories: make(map[string][]domain.ID, len(categories)),
+	}
+
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+		key := normalizeName(account.Name())
+		index.accounts[key] = append(index.accounts[key], account.ID())
+	}
+
+	for _, category := range categories {
+		if category == nil {
+			continue
+		}
+		key := normalizeName(category.Name())
+		index.categories[key] = append(index.categories[key], category.ID())
+	}
+
+	return index
+}
+
+func (n *NameIndex) AccountIDs(name string) []domain.ID {
+	if n == nil {
+		return nil
+	}
+	return n.accounts[normalizeName(name)]
+}
+
+func (n *NameIndex) CategoryIDs(name string) []domain.ID {
+	if n == nil {
+		return nil
+	}
+	return n.categories[normalizeName(name)]
+}
+
+var _ NameResolver = (*NameIndex)(nil)
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -1,17 +1,21 @@
 package query
 
 import (
+	"strings"
 	"time"
 
 	"kpo-hw-2/internal/domain"
 )
 
 type OperationFilter struct {
-	accountID  domain.ID
-	categoryID domain.ID
-	typ        domain.OperationType
+	accounts   Selector[domain.ID]
+	categories Selector[domain.ID]
+	types      Selector[domain.OperationType]
 	from       *time.Time
 	to         *time.Time
+	minAmount  *int64
+	maxAmount  *int64
+	texts      []string
 }
 
 func NewOperationFilter() OperationFilter {
@@ -19,17 +23,59 @@ func NewOperationFilter() OperationFilter {
 }
 
 func (f OperationFilter) ForAccount(id domain.ID) OperationFilter {
-	f.accountID = id
+	return f.ForAccounts(id)
+}
+
+func (f OperationFilter) ForAccounts(ids ...domain.ID) OperationFilter {
+	f.accounts = Include(ids...)
+	return f
+}
+
+func (f OperationFilter) ExcludeAccounts(ids ...domain.ID) OperationFilter {
+	f.accounts = Exclude(ids...)
+	return f
+}
+
+func (f OperationFilter) WithAccounts(selector Selector[domain.ID]) OperationFilter {
+	f.accounts = selector
 	return f
 }
 
 func (f OperationFilter) ForCategory(id domain.ID) OperationFilter {
-	f.categoryID = id
+	return f.ForCategories(id)
+}
+
+func (f OperationFilter) ForCategories(ids ...domain.ID) OperationFilter {
+	f.categories = Include(ids...)
+	return f
+}
+
+func (f OperationFilter) ExcludeCategories(ids ...domain.ID) OperationFilter {
+	f.categories = Exclude(ids...)
+	return f
+}
+
+func (f OperationFilter) WithCategories(selector Selector[domain.ID]) OperationFilter {
+	f.categories = selector
 	return f
 }
 
 func (f OperationFilter) OfType(typ domain.OperationType) OperationFilter {
-	f.typ = typ
+	return f.OfTypes(typ)
+}
+
+func (f OperationFilter) OfTypes(types ...domain.OperationType) OperationFilter {
+	f.types = Include(types...)
+	return f
+}
+
+func (f OperationFilter) ExcludeTypes(types ...domain.OperationType) OperationFilter {
+	f.types = Exclude(types...)
+	return f
+}
+
+func (f OperationFilter) WithTypes(selector Selector[domain.OperationType]) OperationFilter {
+	f.types = selector
 	return f
 }
 
@@ -49,10 +95,95 @@ func (f OperationFilter) Between(from, to time.Time) OperationFilter {
 	return f
 }
 
-func (f OperationFilter) AccountID() domain.ID { return f.accountID }
+func (f OperationFilter) MinAmount(amount int64) OperationFilter {
+	f.minAmount = &amount
+	return f
+}
+
+func (f OperationFilter) MaxAmount(amount int64) OperationFilter {
+	f.maxAmount = &amount
+	return f
+}
+
+func (f OperationFilter) Containing(texts ...string) OperationFilter {
+	f.texts = nil
+	for _, text := range texts {
+		text = strings.ToLower(strings.TrimSpace(text))
+		if text != "" {
+			f.texts = append(f.texts, text)
+		}
+	}
+	return f
+}
+
+func (f OperationFilter) Accounts() Selector[domain.ID] { return f.accounts }
 
-func (f OperationFilter) CategoryID() domain.ID { return f.categoryID }
+func (f OperationFilter) Categories() Selector[domain.ID] { return f.categories }
 
-func (f OperationFilter) Type() domain.OperationType { return f.typ }
+func (f OperationFilter) Types() Selector[domain.OperationType] { return f.types }
 
 func (f OperationFilter) Period() (*time.Time, *time.Time) { return f.from, f.to }
+
+func (f OperationFilter) AmountRange() (*int64, *int64) { return f.minAmount, f.maxAmount }
+
+func (f OperationFilter) Texts() []string {
+	if len(f.texts) == 0 {
+		return nil
+	}
+	out := make([]string, len(f.texts))
+	copy(out, f.texts)
+	return out
+}
+
+func (f OperationFilter) IsEmpty() bool {
+	return f.accounts.IsEmpty() &&
+		f.categories.IsEmpty() &&
+		f.types.IsEmpty() &&
+		f.from == nil &&
+		f.to == nil &&
+		f.minAmount == nil &&
+		f.maxAmount == nil &&
+		len(f.texts) == 0
+}
+
+func (f OperationFilter) Matches(op *domain.Operation) bool {
+	if op == nil {
+		return false
+	}
+	if !f.accounts.Matches(op.BankAccountID()) {
+		return false
+	}
+	if !f.categories.Matches(op.CategoryID()) {
+		return false
+	}
+	if !f.types.Matches(op.Type()) {
+		return false
+	}
+
+	date := op.Date()
+	if f.from != nil && date.Before(*f.from) {
+		return false
+	}
+	if f.to != nil && date.After(*f.to) {
+		return false
+	}
+
+	amount := op.Amount()
+	if f.minAmount != nil && amount < *f.minAmount {
+		return false
+	}
+	if f.maxAmount != nil && amount > *f.maxAmount {
+		return false
+	}
+
+	if len(f.texts) > 0 {
+		description := strings.ToLower(op.Description())
+		for _, text := range f.texts {
+			if !strings.Contains(description, text) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
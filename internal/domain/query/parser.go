@@ -0,0 +1,606 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type ParseError struct {
+	Position int
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("query: position %d: %s", e.Position, e.Message)
+}
+
+func ParseOperationFilter(input string, names NameResolver, now time.Time) (OperationFilter, error) {
+	filter, period, err := ParseOperationQuery(input, names, now.Location())
+	if err != nil {
+		return OperationFilter{}, err
+	}
+	return filter.WithinPeriod(period, now), nil
+}
+
+func ParseOperationQuery(input string, names NameResolver, loc *time.Location) (OperationFilter, PeriodSpec, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return OperationFilter{}, PeriodSpec{}, err
+	}
+
+	p := &parser{names: names, loc: loc}
+	for _, tok := range tokens {
+		if err := p.term(tok); err != nil {
+			return OperationFilter{}, PeriodSpec{}, err
+		}
+	}
+
+	return p.build(), p.period(), nil
+}
+
+type qrune struct {
+	r      rune
+	quoted bool
+	pos    int
+}
+
+type segment struct {
+	runes []qrune
+	start int
+}
+
+func (s segment) text() string {
+	var b strings.Builder
+	for _, q := range s.runes {
+		b.WriteRune(q.r)
+	}
+	return b.String()
+}
+
+func (s segment) pos() int {
+	if len(s.runes) > 0 {
+		return s.runes[0].pos
+	}
+	return s.start
+}
+
+func (s segment) allQuoted() bool {
+	for _, q := range s.runes {
+		if !q.quoted {
+			return false
+		}
+	}
+	return len(s.runes) > 0
+}
+
+func (s segment) split(sep string) []segment {
+	sepRunes := []rune(sep)
+	var parts []segment
+	current := segment{start: s.start}
+	for i := 0; i < len(s.runes); i++ {
+		if s.matchAt(i, sepRunes) {
+			parts = append(parts, current)
+			i += len(sepRunes) - 1
+			next := s.start
+			if i+1 < len(s.runes) {
+				next = s.runes[i+1].pos
+			} else if len(s.runes) > 0 {
+				next = s.runes[len(s.runes)-1].pos + 1
+			}
+			current = segment{start: next}
+			continue
+		}
+		current.runes = append(current.runes, s.runes[i])
+	}
+	return append(parts, current)
+}
+
+func (s segment) matchAt(i int, sep []rune) bool {
+	if i+len(sep) > len(s.runes) {
+		return false
+	}
+	for j, r := range sep {
+		q := s.runes[i+j]
+		if q.quoted || q.r != r {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenize(input string) ([]segment, error) {
+	runes := []rune(input)
+	var tokens []segment
+
+	i := 0
+	for i < len(runes) {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		tok := segment{start: i + 1}
+		quoteStart := 0
+		inQuote := false
+		for i < len(runes) && (inQuote || !unicode.IsSpace(runes[i])) {
+			r := runes[i]
+			switch {
+			case r == '"':
+				inQuote = !inQuote
+				quoteStart = i + 1
+			case inQuote && r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				tok.runes = append(tok.runes, qrune{r: runes[i], quoted: true, pos: i + 1})
+			default:
+				tok.runes = append(tok.runes, qrune{r: r, quoted: inQuote, pos: i + 1})
+			}
+			i++
+		}
+
+		if inQuote {
+			return nil, &ParseError{Position: quoteStart, Message: "unterminated quote"}
+		}
+		if len(tok.runes) > 0 {
+			tokens = append(tokens, tok)
+		}
+	}
+
+	return tokens, nil
+}
+
+type idTerm struct {
+	include []domain.ID
+	exclude []domain.ID
+}
+
+func (t *idTerm) add(ids []domain.ID, negate bool, pos int) error {
+	if negate {
+		if len(t.include) > 0 {
+			return &ParseError{Position: pos, Message: "cannot mix included and excluded values of the same key"}
+		}
+		t.exclude = append(t.exclude, ids...)
+		return nil
+	}
+	if len(t.exclude) > 0 {
+		return &ParseError{Position: pos, Message: "cannot mix included and excluded values of the same key"}
+	}
+	t.include = append(t.include, ids...)
+	return nil
+}
+
+func (t idTerm) selector() Selector[domain.ID] {
+	if len(t.exclude) > 0 {
+		return Exclude(t.exclude...)
+	}
+	return Include(t.include...)
+}
+
+type parser struct {
+	names NameResolver
+	loc   *time.Location
+
+	accounts   idTerm
+	categories idTerm
+
+	types        []domain.OperationType
+	typesExclude bool
+	typesSet     bool
+
+	from      *time.Time
+	to        *time.Time
+	relative  *PeriodSpec
+	minAmount *int64
+	maxAmount *int64
+	texts     []string
+}
+
+func (p *parser) build() OperationFilter {
+	filter := NewOperationFilter().
+		WithAccounts(p.accounts.selector()).
+		WithCategories(p.categories.selector()).
+		Containing(p.texts...)
+
+	if p.typesExclude {
+		filter = filter.ExcludeTypes(p.types...)
+	} else {
+		filter = filter.OfTypes(p.types...)
+	}
+
+	if p.minAmount != nil {
+		filter = filter.MinAmount(*p.minAmount)
+	}
+	if p.maxAmount != nil {
+		filter = filter.MaxAmount(*p.maxAmount)
+	}
+
+	return filter
+}
+
+func (p *parser) period() PeriodSpec {
+	if p.relative != nil {
+		return *p.relative
+	}
+	return CustomPeriod(p.from, p.to)
+}
+
+func (p *parser) term(tok segment) error {
+	if tok.allQuoted() {
+		p.texts = append(p.texts, tok.text())
+		return nil
+	}
+
+	runes := tok.runes
+	negate := false
+	offset := 0
+	if len(runes) > 1 && !runes[0].quoted && runes[0].r == '-' {
+		negate = true
+		offset = 1
+	}
+
+	keyEnd := offset
+	for keyEnd < len(runes) && !runes[keyEnd].quoted && (unicode.IsLetter(runes[keyEnd].r) || runes[keyEnd].r == '_') {
+		keyEnd++
+	}
+
+	op, opLen := operatorAt(runes, keyEnd)
+	if keyEnd == offset || op == "" {
+		p.texts = append(p.texts, tok.text())
+		return nil
+	}
+
+	keySeg := segment{runes: runes[offset:keyEnd], start: tok.start}
+	key := strings.ToLower(keySeg.text())
+	value := segment{runes: runes[keyEnd+opLen:], start: runes[keyEnd+opLen-1].pos + 1}
+
+	switch key {
+	case "type", "t":
+		if op != ":" {
+			return unexpectedOperator(runes[keyEnd].pos, op, key)
+		}
+		return p.typeTerm(value, negate, tok.pos())
+	case "cat", "category":
+		if op != ":" {
+			return unexpectedOperator(runes[keyEnd].pos, op, key)
+		}
+		ids, err := p.resolve(value, "category", p.categoryIDs)
+		if err != nil {
+			return err
+		}
+		return p.categories.add(ids, negate, tok.pos())
+	case "acc", "account":
+		if op != ":" {
+			return unexpectedOperator(runes[keyEnd].pos, op, key)
+		}
+		ids, err := p.resolve(value, "account", p.accountIDs)
+		if err != nil {
+			return err
+		}
+		return p.accounts.add(ids, negate, tok.pos())
+	case "date", "from", "to":
+		if negate {
+			return &ParseError{Position: tok.pos(), Message: fmt.Sprintf("key %q cannot be negated", key)}
+		}
+		if op != ":" {
+			return unexpectedOperator(runes[keyEnd].pos, op, key)
+		}
+		return p.dateTerm(key, value)
+	case "amount", "sum":
+		if negate {
+			return &ParseError{Position: tok.pos(), Message: fmt.Sprintf("key %q cannot be negated", key)}
+		}
+		return p.amountTerm(op, value)
+	default:
+		if op == ":" {
+			return &ParseError{Position: keySeg.pos(), Message: fmt.Sprintf("unknown key %q", key)}
+		}
+		p.texts = append(p.texts, tok.text())
+		return nil
+	}
+}
+
+func operatorAt(runes []qrune, i int) (string, int) {
+	if i >= len(runes) || runes[i].quoted {
+		return "", 0
+	}
+	next := rune(0)
+	if i+1 < len(runes) && !runes[i+1].quoted {
+		next = runes[i+1].r
+	}
+	switch runes[i].r {
+	case ':':
+		return ":", 1
+	case '=':
+		return "=", 1
+	case '>':
+		if next == '=' {
+			return ">=", 2
+		}
+		return ">", 1
+	case '<':
+		if next == '=' {
+			return "<=", 2
+		}
+		return "<", 1
+	default:
+		return "", 0
+	}
+}
+
+func unexpectedOperator(pos int, op, key string) error {
+	return &ParseError{Position: pos, Message: fmt.Sprintf("operator %q is not supported for key %q", op, key)}
+}
+
+func (p *parser) typeTerm(value segment, negate bool, pos int) error {
+	if p.typesSet && p.typesExclude != negate {
+		return &ParseError{Position: pos, Message: "cannot mix included and excluded values of the same key"}
+	}
+
+	for _, part := range value.split(",") {
+		text := strings.ToLower(strings.TrimSpace(part.text()))
+		var typ domain.OperationType
+		switch text {
+		case "income", "доход":
+			typ = domain.OperationTypeIncome
+		case "expense", "расход":
+			typ = domain.OperationTypeExpense
+		case "":
+			return &ParseError{Position: part.pos(), Message: "empty value"}
+		default:
+			return &ParseError{Position: part.pos(), Message: fmt.Sprintf("unknown operation type %q", text)}
+		}
+		p.types = append(p.types, typ)
+	}
+
+	p.typesSet = true
+	p.typesExclude = negate
+	return nil
+}
+
+func (p *parser) accountIDs(name string) []domain.ID {
+	if p.names == nil {
+		return nil
+	}
+	return p.names.AccountIDs(name)
+}
+
+func (p *parser) categoryIDs(name string) []domain.ID {
+	if p.names == nil {
+		return nil
+	}
+	return p.names.CategoryIDs(name)
+}
+
+func (p *parser) resolve(value segment, kind string, lookup func(string) []domain.ID) ([]domain.ID, error) {
+	var ids []domain.ID
+	for _, part := range value.split(",") {
+		name := strings.TrimSpace(part.text())
+		if name == "" {
+			return nil, &ParseError{Position: part.pos(), Message: "empty value"}
+		}
+		found := lookup(name)
+		if len(found) == 0 {
+			return nil, &ParseError{Position: part.pos(), Message: fmt.Sprintf("unknown %s %q", kind, name)}
+		}
+		ids = append(ids, found...)
+	}
+	return ids, nil
+}
+
+func (p *parser) dateTerm(key string, value segment) error {
+	if key == "date" {
+		if spec, ok := relativePeriod(value.text()); ok {
+			if p.relative != nil || p.from != nil || p.to != nil {
+				return &ParseError{Position: value.pos(), Message: "relative period cannot be combined with other dates"}
+			}
+			p.relative = &spec
+			return nil
+		}
+	}
+	if p.relative != nil {
+		return &ParseError{Position: value.pos(), Message: "relative period cannot be combined with other dates"}
+	}
+
+	var from, to *time.Time
+
+	switch key {
+	case "from":
+		start, _, err := p.parsePeriod(value)
+		if err != nil {
+			return err
+		}
+		from = &start
+	case "to":
+		_, end, err := p.parsePeriod(value)
+		if err != nil {
+			return err
+		}
+		to = &end
+	default:
+		bounds := value.split("..")
+		switch len(bounds) {
+		case 1:
+			start, end, err := p.parsePeriod(bounds[0])
+			if err != nil {
+				return err
+			}
+			from, to = &start, &end
+		case 2:
+			if len(bounds[0].runes) == 0 && len(bounds[1].runes) == 0 {
+				return &ParseError{Position: value.pos(), Message: "empty date range"}
+			}
+			if len(bounds[0].runes) > 0 {
+				start, _, err := p.parsePeriod(bounds[0])
+				if err != nil {
+					return err
+				}
+				from = &start
+			}
+			if len(bounds[1].runes) > 0 {
+				_, end, err := p.parsePeriod(bounds[1])
+				if err != nil {
+					return err
+				}
+				to = &end
+			}
+			if from != nil && to != nil && from.After(*to) {
+				return &ParseError{Position: value.pos(), Message: "range start is after its end"}
+			}
+		default:
+			return &ParseError{Position: value.pos(), Message: "invalid date range"}
+		}
+	}
+
+	if from != nil && (p.from == nil || from.After(*p.from)) {
+		p.from = from
+	}
+	if to != nil && (p.to == nil || to.Before(*p.to)) {
+		p.to = to
+	}
+	return nil
+}
+
+func relativePeriod(text string) (PeriodSpec, bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	switch text {
+	case "today":
+		return RelativePeriod(PeriodToday), true
+	case "this-week":
+		return RelativePeriod(PeriodThisWeek), true
+	case "this-month":
+		return RelativePeriod(PeriodThisMonth), true
+	case "last-month":
+		return RelativePeriod(PeriodLastMonth), true
+	case "this-year":
+		return RelativePeriod(PeriodThisYear), true
+	case "last-year":
+		return RelativePeriod(PeriodLastYear), true
+	}
+
+	if strings.HasPrefix(text, "last-") && strings.HasSuffix(text, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(text, "last-"), "d"))
+		if err == nil && days > 0 {
+			return LastDays(days), true
+		}
+	}
+
+	return PeriodSpec{}, false
+}
+
+var periodLayouts = []struct {
+	layout string
+	next   func(time.Time) time.Time
+}{
+	{"2006-01-02", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"02.01.2006", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"2006-01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"01.2006", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+}
+
+func (p *parser) parsePeriod(value segment) (time.Time, time.Time, error) {
+	text := strings.TrimSpace(value.text())
+	if text == "" {
+		return time.Time{}, time.Time{}, &ParseError{Position: value.pos(), Message: "empty date"}
+	}
+
+	for _, candidate := range periodLayouts {
+		start, err := time.ParseInLocation(candidate.layout, text, p.loc)
+		if err != nil {
+			continue
+		}
+		return start, candidate.next(start).Add(-time.Nanosecond), nil
+	}
+
+	return time.Time{}, time.Time{}, &ParseError{
+		Position: value.pos(),
+		Message:  fmt.Sprintf("invalid date %q, expected YYYY-MM-DD, YYYY-MM or YYYY", text),
+	}
+}
+
+func (p *parser) amountTerm(op string, value segment) error {
+	var min, max *int64
+
+	switch op {
+	case ":", "=":
+		bounds := value.split("..")
+		switch len(bounds) {
+		case 1:
+			amount, err := parseAmount(bounds[0])
+			if err != nil {
+				return err
+			}
+			min, max = &amount, &amount
+		case 2:
+			if len(bounds[0].runes) > 0 {
+				amount, err := parseAmount(bounds[0])
+				if err != nil {
+					return err
+				}
+				min = &amount
+			}
+			if len(bounds[1].runes) > 0 {
+				amount, err := parseAmount(bounds[1])
+				if err != nil {
+					return err
+				}
+				max = &amount
+			}
+			if min == nil && max == nil {
+				return &ParseError{Position: value.pos(), Message: "empty amount range"}
+			}
+			if min != nil && max != nil && *min > *max {
+				return &ParseError{Position: value.pos(), Message: "range start is greater than its end"}
+			}
+		default:
+			return &ParseError{Position: value.pos(), Message: "invalid amount range"}
+		}
+	case ">=", ">":
+		amount, err := parseAmount(value)
+		if err != nil {
+			return err
+		}
+		if op == ">" {
+			amount++
+		}
+		min = &amount
+	case "<=", "<":
+		amount, err := parseAmount(value)
+		if err != nil {
+			return err
+		}
+		if op == "<" {
+			amount--
+		}
+		max = &amount
+	}
+
+	if min != nil && (p.minAmount == nil || *min > *p.minAmount) {
+		p.minAmount = min
+	}
+	if max != nil && (p.maxAmount == nil || *max < *p.maxAmount) {
+		p.maxAmount = max
+	}
+	return nil
+}
+
+func parseAmount(value segment) (int64, error) {
+	text := strings.TrimSpace(value.text())
+	if text == "" {
+		return 0, &ParseError{Position: value.pos(), Message: "empty amount"}
+	}
+
+	amount, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || amount < 0 {
+		return 0, &ParseError{Position: value.pos(), Message: fmt.Sprintf("invalid amount %q", text)}
+	}
+	return amount, nil
+}
@@ -0,0 +1,184 @@
+package query
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+func testNames(t *testing.T) *NameIndex {
+	t.Helper()
+
+	card, err := domain.NewBankAccount("card", "Карта", 0)
+	if err != nil {
+		t.Fatalf("NewBankAccount: %v", err)
+	}
+	cash, err := domain.NewBankAccount("cash", "Наличные", 0)
+	if err != nil {
+		t.Fatalf("NewBankAccount: %v", err)
+	}
+	food, err := domain.NewCategory("food", domain.OperationTypeExpense, "Еда")
+	if err != nil {
+		t.Fatalf("NewCategory: %v", err)
+	}
+	cafe, err := domain.NewCategory("cafe", domain.OperationTypeExpense, "Кафе и рестораны")
+	if err != nil {
+		t.Fatalf("NewCategory: %v", err)
+	}
+	salary, err := domain.NewCategory("salary", domain.OperationTypeIncome, "Зарплата")
+	if err != nil {
+		t.Fatalf("NewCategory: %v", err)
+	}
+	return NewNameIndex([]*domain.BankAccount{card, cash}, []*domain.Category{food, cafe, salary})
+}
+
+func TestParseOperationFilterMatches(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+	operation := func(id domain.ID, typ domain.OperationType, account, category domain.ID, amount int64, date time.Time, description string) *domain.Operation {
+		op, err := domain.NewOperation(id, typ, account, category, amount, date, description)
+		if err != nil {
+			t.Fatalf("NewOperation: %v", err)
+		}
+		return op
+	}
+	operations := []*domain.Operation{
+		operation("salary", domain.OperationTypeIncome, "card", "salary", 50000, time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC), "Аванс"),
+		operation("lunch", domain.OperationTypeExpense, "card", "cafe", 700, time.Date(2026, time.March, 10, 13, 0, 0, 0, time.UTC), "Обед с коллегами"),
+		operation("market", domain.OperationTypeExpense, "cash", "food", 1500, time.Date(2026, time.February, 20, 9, 0, 0, 0, time.UTC), "Рынок"),
+		operation("shop", domain.OperationTypeExpense, "card", "food", 2300, time.Date(2025, time.December, 31, 18, 0, 0, 0, time.UTC), "Магазин у дома"),
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []domain.ID
+	}{
+		{name: "empty", input: "", want: []domain.ID{"salary", "lunch", "market", "shop"}},
+		{name: "type", input: "type:expense", want: []domain.ID{"lunch", "market", "shop"}},
+		{name: "russian type", input: "t:доход", want: []domain.ID{"salary"}},
+		{name: "negated type", input: "-type:expense", want: []domain.ID{"salary"}},
+		{name: "account by name", input: "acc:наличные", want: []domain.ID{"market"}},
+		{name: "quoted category", input: `cat:"Кафе и рестораны"`, want: []domain.ID{"lunch"}},
+		{name: "several categories", input: `cat:еда,"кафе и рестораны"`, want: []domain.ID{"lunch", "market", "shop"}},
+		{name: "excluded categories", input: "-cat:еда -cat:зарплата", want: []domain.ID{"lunch"}},
+		{name: "amount range", input: "amount:700..1500", want: []domain.ID{"lunch", "market"}},
+		{name: "amount comparison", input: "amount>700 sum<2300", want: []domain.ID{"market"}},
+		{name: "month", input: "date:2026-03", want: []domain.ID{"salary", "lunch"}},
+		{name: "open range", input: "date:..2026-02-20", want: []domain.ID{"market", "shop"}},
+		{name: "from and to", input: "from:2026-02 to:2026-02", want: []domain.ID{"market"}},
+		{name: "relative period", input: "date:last-7d", want: []domain.ID{"lunch"}},
+		{name: "free text", input: "обед", want: []domain.ID{"lunch"}},
+		{name: "quoted text", input: `"у дома"`, want: []domain.ID{"shop"}},
+		{name: "unknown operator keeps text", input: "amount", want: nil},
+		{name: "combined", input: "type:expense acc:карта amount>=1000", want: []domain.ID{"shop"}},
+	}
+
+	names := testNames(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseOperationFilter(tt.input, names, now)
+			if err != nil {
+				t.Fatalf("ParseOperationFilter(%q): %v", tt.input, err)
+			}
+			var got []domain.ID
+			for _, op := range operations {
+				if filter.Matches(op) {
+					got = append(got, op.ID())
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("matched %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOperationFilterUsesLocalDates(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2026, time.April, 15, 12, 0, 0, 0, msk)
+	operation := func(id domain.ID, date time.Time) *domain.Operation {
+		op, err := domain.NewOperation(id, domain.OperationTypeExpense, "card", "food", 100, date, "")
+		if err != nil {
+			t.Fatalf("NewOperation: %v", err)
+		}
+		return op
+	}
+	operations := []*domain.Operation{
+		operation("first", time.Date(2026, time.March, 1, 1, 0, 0, 0, msk)),
+		operation("last", time.Date(2026, time.March, 31, 23, 30, 0, 0, msk)),
+		operation("before", time.Date(2026, time.February, 28, 23, 0, 0, 0, msk)),
+	}
+
+	tests := []struct {
+		input string
+		want  []domain.ID
+	}{
+		{input: "date:2026-03", want: []domain.ID{"first", "last"}},
+		{input: "date:2026-02", want: []domain.ID{"before"}},
+		{input: "date:01.03.2026", want: []domain.ID{"first"}},
+		{input: "from:2026-03-31", want: []domain.ID{"last"}},
+		{input: "to:2026-02-28", want: []domain.ID{"before"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			filter, err := ParseOperationFilter(tt.input, testNames(t), now)
+			if err != nil {
+				t.Fatalf("ParseOperationFilter(%q): %v", tt.input, err)
+			}
+			var got []domain.ID
+			for _, op := range operations {
+				if filter.Matches(op) {
+					got = append(got, op.ID())
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOperationFilterErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+	}{
+		{name: "unterminated quote", input: `cat:"Еда`, position: 5},
+		{name: "unknown key", input: "foo:bar", position: 1},
+		{name: "unknown type", input: "type:transfer", position: 6},
+		{name: "unknown account", input: "acc:вклад", position: 5},
+		{name: "empty value", input: "cat:еда,", position: 9},
+		{name: "mixed include and exclude", input: "cat:еда -cat:зарплата", position: 9},
+		{name: "negated date", input: "-date:2026", position: 1},
+		{name: "bad operator", input: "type>expense", position: 5},
+		{name: "bad date", input: "date:31-12-2026", position: 6},
+		{name: "reversed dates", input: "date:2026-03..2026-01", position: 6},
+		{name: "relative with absolute", input: "from:2026 date:this-month", position: 16},
+		{name: "negative amount", input: "amount:-5", position: 8},
+		{name: "reversed amounts", input: "amount:10..5", position: 8},
+	}
+
+	names := testNames(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOperationFilter(tt.input, names, time.Now())
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseOperationFilter(%q) error = %v, want *ParseError", tt.input, err)
+			}
+			if parseErr.Position != tt.position {
+				t.Fatalf("position = %d, want %d (%v)", parseErr.Position, tt.position, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"time"
+)
+
+type PeriodKind string
+
+const (
+	PeriodAll       PeriodKind = ""
+	PeriodCustom    PeriodKind = "custom"
+	PeriodToday     PeriodKind = "today"
+	PeriodThisWeek  PeriodKind = "this_week"
+	PeriodThisMonth PeriodKind = "this_month"
+	PeriodLastMonth PeriodKind = "last_month"
+	PeriodThisYear  PeriodKind = "this_year"
+	PeriodLastYear  PeriodKind = "last_year"
+	PeriodLastDays  PeriodKind = "last_days"
+)
+
+type PeriodSpec struct {
+	kind PeriodKind
+	days int
+	from *time.Time
+	to   *time.Time
+}
+
+func AllTime() PeriodSpec {
+	return PeriodSpec{}
+}
+
+func CustomPeriod(from, to *time.Time) PeriodSpec {
+	if from == nil && to == nil {
+		return PeriodSpec{}
+	}
+	spec := PeriodSpec{kind: PeriodCustom}
+	if from != nil {
+		value := *from
+		spec.from = &value
+	}
+	if to != nil {
+		value := *to
+		spec.to = &value
+	}
+	return spec
+}
+
+func RelativePeriod(kind PeriodKind) PeriodSpec {
+	switch kind {
+	case PeriodToday, PeriodThisWeek, PeriodThisMonth, PeriodLastMonth, PeriodThisYear, PeriodLastYear:
+		return PeriodSpec{kind: kind}
+	default:
+		return PeriodSpec{}
+	}
+}
+
+func LastDays(days int) PeriodSpec {
+	if days <= 0 {
+		return PeriodSpec{}
+	}
+	return PeriodSpec{kind: PeriodLastDays, days: days}
+}
+
+func (p PeriodSpec) Kind() PeriodKind { return p.kind }
+
+func (p PeriodSpec) Days() int { return p.days }
+
+func (p PeriodSpec) Bounds() (*time.Time, *time.Time) { return p.from, p.to }
+
+func (p PeriodSpec) IsRelative() bool {
+	return p.kind != PeriodAll && p.kind != PeriodCustom
+}
+
+func (p PeriodSpec) Resolve(now time.Time) (*time.Time, *time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var from, next time.Time
+	switch p.kind {
+	case PeriodCustom:
+		return CustomPeriod(p.from, p.to).Bounds()
+	case PeriodToday:
+		from, next = today, today.AddDate(0, 0, 1)
+	case PeriodThisWeek:
+		offset := (int(today.Weekday()) + 6) % 7
+		from = today.AddDate(0, 0, -offset)
+		next = from.AddDate(0, 0, 7)
+	case PeriodThisMonth:
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		next = from.AddDate(0, 1, 0)
+	case PeriodLastMonth:
+		next = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		from = next.AddDate(0, -1, 0)
+	case PeriodThisYear:
+		from = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		next = from.AddDate(1, 0, 0)
+	case PeriodLastYear:
+		next = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		from = next.AddDate(-1, 0, 0)
+	case PeriodLastDays:
+		next = today.AddDate(0, 0, 1)
+		from = next.AddDate(0, 0, -p.days)
+	default:
+		return nil, nil
+	}
+
+	to := next.Add(-time.Nanosecond)
+	return &from, &to
+}
+
+func (f OperationFilter) WithinPeriod(period PeriodSpec, now time.Time) OperationFilter {
+	f.from, f.to = period.Resolve(now)
+	return f
+}
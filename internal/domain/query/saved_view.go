@@ -0,0 +1,55 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+type SavedView struct {
+	id     domain.ID
+	name   string
+	filter OperationFilter
+	period PeriodSpec
+}
+
+func NewSavedView(id domain.ID, name string, filter OperationFilter, period PeriodSpec) (*SavedView, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidSavedView
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrInvalidSavedView
+	}
+
+	if period.Kind() == PeriodLastDays && period.Days() <= 0 {
+		return nil, domain.ErrInvalidSavedView
+	}
+	if from, to := period.Bounds(); from != nil && to != nil && from.After(*to) {
+		return nil, domain.ErrInvalidSavedView
+	}
+
+	filter.from = nil
+	filter.to = nil
+
+	return &SavedView{
+		id:     id,
+		name:   name,
+		filter: filter,
+		period: period,
+	}, nil
+}
+
+func (v *SavedView) ID() domain.ID { return v.id }
+
+func (v *SavedView) Name() string { return v.name }
+
+func (v *SavedView) Criteria() OperationFilter { return v.filter }
+
+func (v *SavedView) Period() PeriodSpec { return v.period }
+
+func (v *SavedView) Filter(now time.Time) OperationFilter {
+	return v.filter.WithinPeriod(v.period, now)
+}
@@ -0,0 +1,63 @@
+package query
+
+type Selector[T comparable] struct {
+	values  []T
+	exclude bool
+}
+
+func Include[T comparable](values ...T) Selector[T] {
+	return Selector[T]{values: uniqueValues(values)}
+}
+
+func Exclude[T comparable](values ...T) Selector[T] {
+	return Selector[T]{values: uniqueValues(values), exclude: true}
+}
+
+func (s Selector[T]) Values() []T {
+	if len(s.values) == 0 {
+		return nil
+	}
+	out := make([]T, len(s.values))
+	copy(out, s.values)
+	return out
+}
+
+func (s Selector[T]) IsExclude() bool { return s.exclude }
+
+func (s Selector[T]) IsEmpty() bool { return len(s.values) == 0 }
+
+func (s Selector[T]) Contains(value T) bool {
+	for _, v := range s.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Selector[T]) Matches(value T) bool {
+	if len(s.values) == 0 {
+		return true
+	}
+	return s.Contains(value) != s.exclude
+}
+
+func uniqueValues[T comparable](values []T) []T {
+	var zero T
+	out := make([]T, 0, len(values))
+	seen := make(map[T]struct{}, len(values))
+	for _, v := range values {
+		if v == zero {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
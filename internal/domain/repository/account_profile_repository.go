@@ -0,0 +1,10 @@
+package repository
+
+import "kpo-hw-2/internal/domain"
+
+type AccountProfileRepository interface {
+	Save(profile *domain.AccountProfile) error
+	Delete(accountID domain.ID) error
+	Get(accountID domain.ID) (*domain.AccountProfile, error)
+	List() ([]*domain.AccountProfile, error)
+}
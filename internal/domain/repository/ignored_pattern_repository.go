@@ -0,0 +1,7 @@
+package repository
+
+type IgnoredPatternRepository interface {
+	Add(key string) error
+	Remove(key string) error
+	List() ([]string, error)
+}
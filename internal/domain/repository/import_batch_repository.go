@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+)
+
+type ImportState struct {
+	Account   *domain.BankAccount
+	Category  *domain.Category
+	Operation *domain.Operation
+	View      *query.SavedView
+}
+
+type ImportChange struct {
+	Action    ImportAction
+	Account   *domain.BankAccount
+	Profile   *domain.AccountProfile
+	Category  *domain.Category
+	Operation *domain.Operation
+	View      *query.SavedView
+	Balanced  bool
+	Applied   ImportState
+	Recorded  bool
+}
+
+type ImportBatch struct {
+	ID        domain.ID
+	Format    string
+	Source    string
+	Strategy  string
+	Mode      string
+	CreatedAt time.Time
+	Changes   []ImportChange
+}
+
+type ImportBatchRepository interface {
+	Save(batch ImportBatch) error
+	Delete(id domain.ID) error
+	Get(id domain.ID) (ImportBatch, error)
+	List() ([]ImportBatch, error)
+}
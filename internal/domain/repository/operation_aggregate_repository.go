@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+type AggregateCell struct {
+	AccountID  domain.ID
+	CategoryID domain.ID
+	Type       domain.OperationType
+	Day        time.Time
+	Amount     int64
+	Count      int
+}
+
+type OperationAggregateRepository interface {
+	Add(operation *domain.Operation) error
+	Remove(operation *domain.Operation) error
+	Rebuild(day time.Time, operations []*domain.Operation) error
+	Cells(filter query.OperationFilter) ([]AggregateCell, error)
+}
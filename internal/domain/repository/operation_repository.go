@@ -11,4 +11,5 @@ type OperationRepository interface {
 	Delete(id domain.ID) error
 	Get(id domain.ID) (*domain.Operation, error)
 	ListByFilter(filter query.OperationFilter) ([]*domain.Operation, error)
+	ListPage(filter query.OperationFilter, after *domain.Operation, limit int) ([]*domain.Operation, error)
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+type SavedViewRepository interface {
+	Create(view *query.SavedView) error
+	Update(view *query.SavedView) error
+	Delete(id domain.ID) error
+	Get(id domain.ID) (*query.SavedView, error)
+	List() ([]*query.SavedView, error)
+}
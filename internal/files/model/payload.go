@@ -6,12 +6,16 @@ type Payload struct {
 	Accounts   []Account
 	Categories []Category
 	Operations []Operation
+	Views      []SavedView
 }
 
 type Account struct {
 	ID      string
 	Name    string
 	Balance int64
+	Kind    string `json:",omitempty" yaml:",omitempty"`
+	Group   string `json:",omitempty" yaml:",omitempty"`
+	Rounded bool   `json:"-" yaml:"-"`
 }
 
 type Category struct {
@@ -25,7 +29,29 @@ type Operation struct {
 	Type          string
 	BankAccountID string
 	CategoryID    string
+	AccountName   string `json:",omitempty" yaml:",omitempty"`
+	CategoryName  string `json:",omitempty" yaml:",omitempty"`
 	Amount        int64
 	Date          time.Time
 	Description   string
+	UpdatedAt     time.Time
+	Rounded       bool `json:"-" yaml:"-"`
+}
+
+type SavedView struct {
+	ID                string
+	Name              string
+	Accounts          []string
+	ExcludeAccounts   bool
+	Categories        []string
+	ExcludeCategories bool
+	Types             []string
+	ExcludeTypes      bool
+	MinAmount         *int64
+	MaxAmount         *int64
+	Texts             []string
+	Period            string
+	PeriodDays        int
+	From              *time.Time
+	To                *time.Time
 }
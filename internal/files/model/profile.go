@@ -0,0 +1,28 @@
+package model
+
+type StatementProfile struct {
+	Name               string           `yaml:"name"`
+	Encoding           string           `yaml:"encoding"`
+	Delimiter          string           `yaml:"delimiter"`
+	SkipRows           int              `yaml:"skip_rows"`
+	Header             bool             `yaml:"header"`
+	Columns            StatementColumns `yaml:"columns"`
+	DateLayout         string           `yaml:"date_layout"`
+	DecimalSeparator   string           `yaml:"decimal_separator"`
+	ThousandsSeparator string           `yaml:"thousands_separator"`
+	Scale              int64            `yaml:"scale"`
+	Sign               string           `yaml:"sign"`
+	IncomeValues       []string         `yaml:"income_values"`
+	Account            string           `yaml:"account"`
+	IncomeCategory     string           `yaml:"income_category"`
+	ExpenseCategory    string           `yaml:"expense_category"`
+}
+
+type StatementColumns struct {
+	Date        string `yaml:"date"`
+	Amount      string `yaml:"amount"`
+	Description string `yaml:"description"`
+	Category    string `yaml:"category"`
+	Account     string `yaml:"account"`
+	Type        string `yaml:"type"`
+}
@@ -7,6 +7,7 @@ import (
 	appfacade "kpo-hw-2/internal/application/facade"
 	fileexport "kpo-hw-2/internal/application/files/export"
 	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
 	domainfactory "kpo-hw-2/internal/domain/factory"
 	"kpo-hw-2/internal/domain/repository"
 	"kpo-hw-2/internal/infrastructure/di"
@@ -22,7 +23,11 @@ func registerApplication(container di.Container) error {
 		if err != nil {
 			return nil, err
 		}
-		return appfacade.NewAccountFacade(factory, repo), nil
+		profileRepo, err := di.Resolve[repository.AccountProfileRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		return appfacade.NewAccountFacade(factory, repo, profileRepo), nil
 	}); err != nil {
 		return fmt.Errorf("bootstrap: register account facade: %w", err)
 	}
@@ -58,16 +63,60 @@ func registerApplication(container di.Container) error {
 		if err != nil {
 			return nil, err
 		}
-		return appfacade.NewOperationFacade(factory, opRepo, accountRepo, categoryRepo), nil
+		aggregateRepo, err := di.Resolve[repository.OperationAggregateRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		profileRepo, err := di.Resolve[repository.AccountProfileRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		return appfacade.NewOperationFacade(factory, opRepo, accountRepo, categoryRepo, profileRepo, aggregateRepo), nil
 	}); err != nil {
 		return fmt.Errorf("bootstrap: register operation facade: %w", err)
 	}
 
+	if err := di.Register(container, func(c di.Container) (appfacade.SavedViewFacade, error) {
+		factory, err := di.Resolve[domainfactory.SavedViewFactory](c)
+		if err != nil {
+			return nil, err
+		}
+		viewRepo, err := di.Resolve[repository.SavedViewRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		accountRepo, err := di.Resolve[repository.AccountRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		categoryRepo, err := di.Resolve[repository.CategoryRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		return appfacade.NewSavedViewFacade(factory, viewRepo, accountRepo, categoryRepo), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register saved view facade: %w", err)
+	}
+
+	if err := di.Register(container, func(c di.Container) (appfacade.SubscriptionFacade, error) {
+		ignoredRepo, err := di.Resolve[repository.IgnoredPatternRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		return appfacade.NewSubscriptionFacade(ignoredRepo), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register subscription facade: %w", err)
+	}
+
 	if err := di.Register(container, func(c di.Container) (*fileexport.Service, error) {
 		accountRepo, err := di.Resolve[repository.AccountRepository](c)
 		if err != nil {
 			return nil, err
 		}
+		profileRepo, err := di.Resolve[repository.AccountProfileRepository](c)
+		if err != nil {
+			return nil, err
+		}
 		categoryRepo, err := di.Resolve[repository.CategoryRepository](c)
 		if err != nil {
 			return nil, err
@@ -76,12 +125,16 @@ func registerApplication(container di.Container) error {
 		if err != nil {
 			return nil, err
 		}
+		viewRepo, err := di.Resolve[repository.SavedViewRepository](c)
+		if err != nil {
+			return nil, err
+		}
 		exporters, err := di.Resolve[[]fileexport.Exporter](c)
 		if err != nil {
 			return nil, err
 		}
 
-		return fileexport.NewService(accountRepo, categoryRepo, operationRepo, exporters), nil
+		return fileexport.NewService(accountRepo, profileRepo, categoryRepo, operationRepo, viewRepo, exporters), nil
 	}); err != nil {
 		return fmt.Errorf("bootstrap: register export service: %w", err)
 	}
@@ -99,12 +152,37 @@ func registerApplication(container di.Container) error {
 		if err != nil {
 			return nil, err
 		}
+		viewFacade, err := di.Resolve[appfacade.SavedViewFacade](c)
+		if err != nil {
+			return nil, err
+		}
+		idGenerator, err := di.Resolve[domain.IDGenerator](c)
+		if err != nil {
+			return nil, err
+		}
+		batchRepo, err := di.Resolve[repository.ImportBatchRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		profiles, err := di.Resolve[fileimport.ProfileStore](c)
+		if err != nil {
+			return nil, err
+		}
 		importers, err := di.Resolve[[]fileimport.Importer](c)
 		if err != nil {
 			return nil, err
 		}
 
-		return fileimport.NewService(accountFacade, categoryFacade, operationFacade, importers), nil
+		return fileimport.NewService(
+			accountFacade,
+			categoryFacade,
+			operationFacade,
+			viewFacade,
+			idGenerator,
+			batchRepo,
+			profiles,
+			importers,
+		), nil
 	}); err != nil {
 		return fmt.Errorf("bootstrap: register import service: %w", err)
 	}
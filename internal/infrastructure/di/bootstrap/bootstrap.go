@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	appanalytics "kpo-hw-2/internal/application/analytics"
 	accountcmd "kpo-hw-2/internal/application/command/account"
 	analyticscmd "kpo-hw-2/internal/application/command/analytics"
 	categorycmd "kpo-hw-2/internal/application/command/category"
 	exportcmd "kpo-hw-2/internal/application/command/export"
 	importcmd "kpo-hw-2/internal/application/command/import"
 	operationcmd "kpo-hw-2/internal/application/command/operation"
+	viewcmd "kpo-hw-2/internal/application/command/view"
 	fileexport "kpo-hw-2/internal/application/files/export"
 	fileimport "kpo-hw-2/internal/application/files/import"
 	"kpo-hw-2/internal/infrastructure/di"
@@ -19,6 +21,11 @@ import (
 
 type App struct {
 	Model *tui.Model
+
+	Accounts   *accountcmd.Service
+	Categories *categorycmd.Service
+	Operations *operationcmd.Service
+	Import     *importcmd.Service
 }
 
 func Build(
@@ -26,6 +33,8 @@ func Build(
 	logFn func(string, time.Duration, error),
 	exporters []fileexport.Exporter,
 	importers []fileimport.Importer,
+	profiles fileimport.ProfileStore,
+	reports appanalytics.ReportWriter,
 ) (*App, error) {
 	if ctx == nil {
 		ctx = context.Background()
@@ -42,6 +51,12 @@ func Build(
 	if err := di.Provide[[]fileimport.Importer](container, importers); err != nil {
 		return nil, fmt.Errorf("bootstrap: provide importers: %w", err)
 	}
+	if err := di.Provide[fileimport.ProfileStore](container, profiles); err != nil {
+		return nil, fmt.Errorf("bootstrap: provide statement profiles: %w", err)
+	}
+	if err := di.Provide[appanalytics.ReportWriter](container, reports); err != nil {
+		return nil, fmt.Errorf("bootstrap: provide report writer: %w", err)
+	}
 
 	if err := registerInfrastructure(container); err != nil {
 		return nil, err
@@ -71,6 +86,10 @@ func Build(
 	if err != nil {
 		return nil, fmt.Errorf("bootstrap: resolve operation commands: %w", err)
 	}
+	viewCommands, err := di.Resolve[*viewcmd.Service](container)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap: resolve saved view commands: %w", err)
+	}
 	fileExportCommands, err := di.Resolve[*exportcmd.Service](container)
 	if err != nil {
 		return nil, fmt.Errorf("bootstrap: resolve export commands: %w", err)
@@ -94,11 +113,18 @@ func Build(
 		accountCommands,
 		categoryCommands,
 		operationCommands,
+		viewCommands,
 		fileExportCommands,
 		fileImportCommands,
 		analyticsCommands,
 		rootScreen,
 	)
 
-	return &App{Model: model}, nil
+	return &App{
+		Model:      model,
+		Accounts:   accountCommands,
+		Categories: categoryCommands,
+		Operations: operationCommands,
+		Import:     fileImportCommands,
+	}, nil
 }
@@ -13,11 +13,15 @@ import (
 	exportcmd "kpo-hw-2/internal/application/command/export"
 	fileimportcmd "kpo-hw-2/internal/application/command/import"
 	operationcmd "kpo-hw-2/internal/application/command/operation"
+	viewcmd "kpo-hw-2/internal/application/command/view"
 	appfacade "kpo-hw-2/internal/application/facade"
 	appfiles "kpo-hw-2/internal/application/files"
 	fileexport "kpo-hw-2/internal/application/files/export"
 	fileimport "kpo-hw-2/internal/application/files/import"
 	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+	filesmodel "kpo-hw-2/internal/files/model"
 	"kpo-hw-2/internal/infrastructure/di"
 )
 
@@ -35,6 +39,8 @@ func registerCommands(container di.Container) error {
 		timedBankAccount := decorator.Timed[*domain.BankAccount]{Log: logFn}
 		timedNoResult := decorator.Timed[command.NoResult]{Log: logFn}
 		timedList := decorator.Timed[[]*domain.BankAccount]{Log: logFn}
+		timedProfile := decorator.Timed[*domain.AccountProfile]{Log: logFn}
+		timedProfiles := decorator.Timed[[]*domain.AccountProfile]{Log: logFn}
 
 		return accountcmd.NewService(
 			facade,
@@ -44,6 +50,10 @@ func registerCommands(container di.Container) error {
 				Delete: []command.Decorator[command.NoResult]{timedNoResult},
 				List:   []command.Decorator[[]*domain.BankAccount]{timedList},
 				Get:    []command.Decorator[*domain.BankAccount]{timedBankAccount},
+
+				SetProfile: []command.Decorator[*domain.AccountProfile]{timedProfile},
+				Profile:    []command.Decorator[*domain.AccountProfile]{timedProfile},
+				Profiles:   []command.Decorator[[]*domain.AccountProfile]{timedProfiles},
 			},
 		), nil
 	}); err != nil {
@@ -91,6 +101,7 @@ func registerCommands(container di.Container) error {
 		timedOperation := decorator.Timed[*domain.Operation]{Log: logFn}
 		timedNoResult := decorator.Timed[command.NoResult]{Log: logFn}
 		timedList := decorator.Timed[[]*domain.Operation]{Log: logFn}
+		timedFilter := decorator.Timed[query.OperationFilter]{Log: logFn}
 
 		return operationcmd.NewService(
 			facade,
@@ -100,12 +111,43 @@ func registerCommands(container di.Container) error {
 				Delete: []command.Decorator[command.NoResult]{timedNoResult},
 				List:   []command.Decorator[[]*domain.Operation]{timedList},
 				Get:    []command.Decorator[*domain.Operation]{timedOperation},
+				Parse:  []command.Decorator[query.OperationFilter]{timedFilter},
+				Query:  []command.Decorator[[]*domain.Operation]{timedList},
 			},
 		), nil
 	}); err != nil {
 		return fmt.Errorf("bootstrap: register operation commands: %w", err)
 	}
 
+	if err := di.Register(container, func(c di.Container) (*viewcmd.Service, error) {
+		facade, err := di.Resolve[appfacade.SavedViewFacade](c)
+		if err != nil {
+			return nil, err
+		}
+		logFn, err := di.Resolve[func(string, time.Duration, error)](c)
+		if err != nil {
+			return nil, err
+		}
+
+		timedView := decorator.Timed[*query.SavedView]{Log: logFn}
+		timedNoResult := decorator.Timed[command.NoResult]{Log: logFn}
+		timedList := decorator.Timed[[]*query.SavedView]{Log: logFn}
+		timedFilter := decorator.Timed[query.OperationFilter]{Log: logFn}
+
+		return viewcmd.NewService(
+			facade,
+			viewcmd.Decorators{
+				Create:  []command.Decorator[*query.SavedView]{timedView},
+				Delete:  []command.Decorator[command.NoResult]{timedNoResult},
+				List:    []command.Decorator[[]*query.SavedView]{timedList},
+				Get:     []command.Decorator[*query.SavedView]{timedView},
+				Resolve: []command.Decorator[query.OperationFilter]{timedFilter},
+			},
+		), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register saved view commands: %w", err)
+	}
+
 	if err := di.Register(container, func(c di.Container) (*exportcmd.Service, error) {
 		service, err := di.Resolve[*fileexport.Service](c)
 		if err != nil {
@@ -142,13 +184,21 @@ func registerCommands(container di.Container) error {
 		}
 
 		timedFormats := decorator.Timed[[]appfiles.Format]{Log: logFn}
+		timedProfiles := decorator.Timed[[]filesmodel.StatementProfile]{Log: logFn}
 		timedResult := decorator.Timed[fileimport.Result]{Log: logFn}
+		timedPreview := decorator.Timed[fileimport.Preview]{Log: logFn}
+		timedBatches := decorator.Timed[[]repository.ImportBatch]{Log: logFn}
+		timedRollback := decorator.Timed[repository.ImportBatch]{Log: logFn}
 
 		return fileimportcmd.NewService(
 			service,
 			fileimportcmd.Decorators{
-				ListFormats:    []command.Decorator[[]appfiles.Format]{timedFormats},
-				ImportFromPath: []command.Decorator[fileimport.Result]{timedResult},
+				ListFormats:     []command.Decorator[[]appfiles.Format]{timedFormats},
+				Profiles:        []command.Decorator[[]filesmodel.StatementProfile]{timedProfiles},
+				ImportFromPath:  []command.Decorator[fileimport.Result]{timedResult},
+				PreviewFromPath: []command.Decorator[fileimport.Preview]{timedPreview},
+				Batches:         []command.Decorator[[]repository.ImportBatch]{timedBatches},
+				Rollback:        []command.Decorator[repository.ImportBatch]{timedRollback},
 			},
 		), nil
 	}); err != nil {
@@ -160,17 +210,80 @@ func registerCommands(container di.Container) error {
 		if err != nil {
 			return nil, err
 		}
+		accountFacade, err := di.Resolve[appfacade.AccountFacade](c)
+		if err != nil {
+			return nil, err
+		}
+		operationFacade, err := di.Resolve[appfacade.OperationFacade](c)
+		if err != nil {
+			return nil, err
+		}
+		categoryFacade, err := di.Resolve[appfacade.CategoryFacade](c)
+		if err != nil {
+			return nil, err
+		}
+		subscriptionFacade, err := di.Resolve[appfacade.SubscriptionFacade](c)
+		if err != nil {
+			return nil, err
+		}
+		reports, err := di.Resolve[appanalytics.ReportWriter](c)
+		if err != nil {
+			return nil, err
+		}
 		logFn, err := di.Resolve[func(string, time.Duration, error)](c)
 		if err != nil {
 			return nil, err
 		}
 
 		timedTotals := decorator.Timed[appanalytics.Totals]{Log: logFn}
+		timedBreakdown := decorator.Timed[appanalytics.Breakdown]{Log: logFn}
+		timedSeries := decorator.Timed[[]appanalytics.Bucket]{Log: logFn}
+		timedStatement := decorator.Timed[appanalytics.Statement]{Log: logFn}
+		timedBalance := decorator.Timed[int64]{Log: logFn}
+		timedBalanceSeries := decorator.Timed[[]appanalytics.BalancePoint]{Log: logFn}
+		timedForecast := decorator.Timed[appanalytics.Forecast]{Log: logFn}
+		timedExportForecast := decorator.Timed[command.NoResult]{Log: logFn}
+		timedAnomalies := decorator.Timed[[]appanalytics.Anomaly]{Log: logFn}
+		timedCompare := decorator.Timed[appanalytics.Comparison]{Log: logFn}
+		timedSubscriptions := decorator.Timed[[]appanalytics.Subscription]{Log: logFn}
+		timedNetWorth := decorator.Timed[appanalytics.NetWorth]{Log: logFn}
+		timedIncomeStatement := decorator.Timed[appanalytics.IncomeStatement]{Log: logFn}
+		timedBalanceSheet := decorator.Timed[appanalytics.BalanceSheet]{Log: logFn}
+		timedNoResult := decorator.Timed[command.NoResult]{Log: logFn}
 
 		return analyticscmd.NewService(
 			service,
+			accountFacade,
+			categoryFacade,
+			operationFacade,
+			subscriptionFacade,
+			reports,
 			analyticscmd.Decorators{
 				NetTotals: []command.Decorator[appanalytics.Totals]{timedTotals},
+				Totals:    []command.Decorator[appanalytics.Totals]{timedTotals},
+				Breakdown: []command.Decorator[appanalytics.Breakdown]{timedBreakdown},
+				Series:    []command.Decorator[[]appanalytics.Bucket]{timedSeries},
+
+				Statement:     []command.Decorator[appanalytics.Statement]{timedStatement},
+				BalanceAsOf:   []command.Decorator[int64]{timedBalance},
+				BalanceSeries: []command.Decorator[[]appanalytics.BalancePoint]{timedBalanceSeries},
+
+				Forecast:       []command.Decorator[appanalytics.Forecast]{timedForecast},
+				ExportForecast: []command.Decorator[command.NoResult]{timedExportForecast},
+				Anomalies:      []command.Decorator[[]appanalytics.Anomaly]{timedAnomalies},
+				Compare:        []command.Decorator[appanalytics.Comparison]{timedCompare},
+
+				Subscriptions:       []command.Decorator[[]appanalytics.Subscription]{timedSubscriptions},
+				IgnoreSubscription:  []command.Decorator[command.NoResult]{timedNoResult},
+				RestoreSubscription: []command.Decorator[command.NoResult]{timedNoResult},
+
+				NetWorth:       []command.Decorator[appanalytics.NetWorth]{timedNetWorth},
+				ExportNetWorth: []command.Decorator[command.NoResult]{timedNoResult},
+
+				IncomeStatement:       []command.Decorator[appanalytics.IncomeStatement]{timedIncomeStatement},
+				ExportIncomeStatement: []command.Decorator[command.NoResult]{timedNoResult},
+				BalanceSheet:          []command.Decorator[appanalytics.BalanceSheet]{timedBalanceSheet},
+				ExportBalanceSheet:    []command.Decorator[command.NoResult]{timedNoResult},
 			},
 		), nil
 	}); err != nil {
@@ -39,5 +39,15 @@ func registerDomain(container di.Container) error {
 		return fmt.Errorf("bootstrap: register operation factory: %w", err)
 	}
 
+	if err := di.Register(container, func(c di.Container) (domainfactory.SavedViewFactory, error) {
+		idGenerator, err := di.Resolve[domain.IDGenerator](c)
+		if err != nil {
+			return nil, err
+		}
+		return domainfactory.NewSavedViewFactory(idGenerator), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register saved view factory: %w", err)
+	}
+
 	return nil
 }
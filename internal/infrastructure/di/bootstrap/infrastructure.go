@@ -21,6 +21,12 @@ func registerInfrastructure(container di.Container) error {
 		return fmt.Errorf("bootstrap: register account repository: %w", err)
 	}
 
+	if err := di.Register(container, func(di.Container) (repository.AccountProfileRepository, error) {
+		return memoryrepo.NewAccountProfileRepository(), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register account profile repository: %w", err)
+	}
+
 	if err := di.Register(container, func(di.Container) (repository.CategoryRepository, error) {
 		return memoryrepo.NewCategoryRepository(), nil
 	}); err != nil {
@@ -33,5 +39,29 @@ func registerInfrastructure(container di.Container) error {
 		return fmt.Errorf("bootstrap: register operation repository: %w", err)
 	}
 
+	if err := di.Register(container, func(di.Container) (repository.OperationAggregateRepository, error) {
+		return memoryrepo.NewOperationAggregateRepository(), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register operation aggregate repository: %w", err)
+	}
+
+	if err := di.Register(container, func(di.Container) (repository.SavedViewRepository, error) {
+		return memoryrepo.NewSavedViewRepository(), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register saved view repository: %w", err)
+	}
+
+	if err := di.Register(container, func(di.Container) (repository.IgnoredPatternRepository, error) {
+		return memoryrepo.NewIgnoredPatternRepository(), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register ignored pattern repository: %w", err)
+	}
+
+	if err := di.Register(container, func(di.Container) (repository.ImportBatchRepository, error) {
+		return memoryrepo.NewImportBatchRepository(), nil
+	}); err != nil {
+		return fmt.Errorf("bootstrap: register import batch repository: %w", err)
+	}
+
 	return nil
 }
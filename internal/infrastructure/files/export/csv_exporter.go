@@ -2,13 +2,16 @@ package fileexport
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"time"
 
 	appfiles "kpo-hw-2/internal/application/files"
 	fileexport "kpo-hw-2/internal/application/files/export"
 	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
 	filesmodel "kpo-hw-2/internal/files/model"
 )
 
@@ -37,55 +40,126 @@ var _ fileexport.Exporter = (*CSVExporter)(nil)
 
 type csvVisitor struct {
 	writer  *csv.Writer
-	payload filesmodel.Payload
+	started bool
 }
 
-func (v *csvVisitor) VisitBankAccount(account *domain.BankAccount) error {
+func (v *csvVisitor) VisitBankAccount(account *domain.BankAccount, profile *domain.AccountProfile) error {
 	if account == nil {
 		return nil
 	}
-	v.payload.Accounts = append(v.payload.Accounts, filesmodel.Account{
-		ID:      account.ID().String(),
-		Name:    account.Name(),
-		Balance: account.Balance(),
+	model := accountModel(account, profile)
+	return v.write([]string{
+		"account",
+		model.ID,
+		model.Name,
+		model.Kind,
+		strconv.FormatInt(model.Balance, 10),
+		"",
+		"",
+		"",
+		"",
+		model.Group,
+		"",
 	})
-	return nil
 }
 
 func (v *csvVisitor) VisitCategory(category *domain.Category) error {
 	if category == nil {
 		return nil
 	}
-	v.payload.Categories = append(v.payload.Categories, filesmodel.Category{
-		ID:   category.ID().String(),
-		Type: string(category.Type()),
-		Name: category.Name(),
+	model := categoryModel(category)
+	return v.write([]string{
+		"category",
+		model.ID,
+		model.Name,
+		model.Type,
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
 	})
-	return nil
 }
 
 func (v *csvVisitor) VisitOperation(operation *domain.Operation) error {
 	if operation == nil {
 		return nil
 	}
-	v.payload.Operations = append(v.payload.Operations, filesmodel.Operation{
-		ID:            operation.ID().String(),
-		Type:          string(operation.Type()),
-		BankAccountID: operation.BankAccountID().String(),
-		CategoryID:    operation.CategoryID().String(),
-		Amount:        operation.Amount(),
-		Date:          operation.Date(),
-		Description:   operation.Description(),
+	model := operationModel(operation)
+
+	dateValue := ""
+	if !model.Date.IsZero() {
+		dateValue = model.Date.Format(time.RFC3339)
+	}
+	updatedValue := ""
+	if !model.UpdatedAt.IsZero() {
+		updatedValue = model.UpdatedAt.Format(time.RFC3339Nano)
+	}
+
+	return v.write([]string{
+		"operation",
+		model.ID,
+		"",
+		model.Type,
+		"",
+		model.BankAccountID,
+		model.CategoryID,
+		strconv.FormatInt(model.Amount, 10),
+		dateValue,
+		model.Description,
+		updatedValue,
+	})
+}
+
+func (v *csvVisitor) VisitSavedView(view *query.SavedView) error {
+	if view == nil {
+		return nil
+	}
+	model := savedViewModel(view)
+	return v.write([]string{
+		"view",
+		model.ID,
+		model.Name,
+		joinCSVList(model.Types, model.ExcludeTypes),
+		"",
+		joinCSVList(model.Accounts, model.ExcludeAccounts),
+		joinCSVList(model.Categories, model.ExcludeCategories),
+		formatCSVRange(model.MinAmount, model.MaxAmount),
+		formatCSVPeriod(model),
+		strings.Join(model.Texts, "|"),
+		"",
 	})
-	return nil
 }
 
 func (v *csvVisitor) Finalize() error {
 	if v.writer == nil {
 		return nil
 	}
+	if err := v.writeHeader(); err != nil {
+		return err
+	}
+	v.writer.Flush()
+	return v.writer.Error()
+}
+
+func (v *csvVisitor) write(record []string) error {
+	if v.writer == nil {
+		return nil
+	}
+	if err := v.writeHeader(); err != nil {
+		return err
+	}
+	return v.writer.Write(record)
+}
 
-	if err := v.writer.Write([]string{
+func (v *csvVisitor) writeHeader() error {
+	if v.started {
+		return nil
+	}
+	v.started = true
+	return v.writer.Write([]string{
 		"entity",
 		"id",
 		"name",
@@ -96,68 +170,53 @@ func (v *csvVisitor) Finalize() error {
 		"amount",
 		"date",
 		"description",
-	}); err != nil {
-		return err
-	}
+		"updated_at",
+	})
+}
 
-	for _, account := range v.payload.Accounts {
-		if err := v.writer.Write([]string{
-			"account",
-			account.ID,
-			account.Name,
-			"",
-			strconv.FormatInt(account.Balance, 10),
-			"",
-			"",
-			"",
-			"",
-			"",
-		}); err != nil {
-			return err
-		}
+func joinCSVList(values []string, exclude bool) string {
+	if len(values) == 0 {
+		return ""
+	}
+	joined := strings.Join(values, "|")
+	if exclude {
+		return "!" + joined
 	}
+	return joined
+}
 
-	for _, category := range v.payload.Categories {
-		if err := v.writer.Write([]string{
-			"category",
-			category.ID,
-			category.Name,
-			category.Type,
-			"",
-			"",
-			"",
-			"",
-			"",
-			"",
-		}); err != nil {
-			return err
-		}
+func formatCSVRange(minValue, maxValue *int64) string {
+	if minValue == nil && maxValue == nil {
+		return ""
+	}
+	var from, to string
+	if minValue != nil {
+		from = strconv.FormatInt(*minValue, 10)
 	}
+	if maxValue != nil {
+		to = strconv.FormatInt(*maxValue, 10)
+	}
+	return from + ".." + to
+}
 
-	for _, operation := range v.payload.Operations {
-		dateValue := ""
-		if !operation.Date.IsZero() {
-			dateValue = operation.Date.Format(time.RFC3339)
+func formatCSVPeriod(view filesmodel.SavedView) string {
+	switch view.Period {
+	case "":
+		return ""
+	case string(query.PeriodLastDays):
+		return fmt.Sprintf("%s:%d", view.Period, view.PeriodDays)
+	case string(query.PeriodCustom):
+		var from, to string
+		if view.From != nil {
+			from = view.From.Format(time.RFC3339)
 		}
-
-		if err := v.writer.Write([]string{
-			"operation",
-			operation.ID,
-			"",
-			operation.Type,
-			"",
-			operation.BankAccountID,
-			operation.CategoryID,
-			strconv.FormatInt(operation.Amount, 10),
-			dateValue,
-			operation.Description,
-		}); err != nil {
-			return err
+		if view.To != nil {
+			to = view.To.Format(time.RFC3339)
 		}
+		return fmt.Sprintf("%s:%s..%s", view.Period, from, to)
+	default:
+		return view.Period
 	}
-
-	v.writer.Flush()
-	return v.writer.Error()
 }
 
 var _ fileexport.Visitor = (*csvVisitor)(nil)
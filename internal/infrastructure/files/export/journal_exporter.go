@@ -0,0 +1,361 @@
+package fileexport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	appfiles "kpo-hw-2/internal/application/files"
+	fileexport "kpo-hw-2/internal/application/files/export"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+const (
+	journalAssetsRoot     = "Assets:Bank"
+	journalIncomeRoot     = "Income"
+	journalExpensesRoot   = "Expenses"
+	journalOpeningAccount = "Equity:Opening Balances"
+	journalOpeningPayee   = "Opening Balance"
+	beancountCurrency     = "RUB"
+)
+
+var journalSeparators = strings.NewReplacer(
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+	"\t", " ",
+	";", ",",
+)
+
+type journalDialect int
+
+const (
+	dialectLedger journalDialect = iota
+	dialectHledger
+	dialectBeancount
+)
+
+type JournalExporter struct {
+	dialect journalDialect
+}
+
+func NewLedgerExporter() *JournalExporter {
+	return &JournalExporter{dialect: dialectLedger}
+}
+
+func NewHledgerExporter() *JournalExporter {
+	return &JournalExporter{dialect: dialectHledger}
+}
+
+func NewBeancountExporter() *JournalExporter {
+	return &JournalExporter{dialect: dialectBeancount}
+}
+
+func (e *JournalExporter) Format() appfiles.Format {
+	switch e.dialect {
+	case dialectHledger:
+		return appfiles.Format{
+			Key:         "hledger",
+			Title:       "hledger",
+			Description: "Экспорт в журнал hledger.",
+			Extension:   "journal",
+		}
+	case dialectBeancount:
+		return appfiles.Format{
+			Key:         "beancount",
+			Title:       "Beancount",
+			Description: "Экспорт в журнал Beancount.",
+			Extension:   "beancount",
+		}
+	default:
+		return appfiles.Format{
+			Key:         "ledger",
+			Title:       "Ledger",
+			Description: "Экспорт в журнал ledger-cli.",
+			Extension:   "ledger",
+		}
+	}
+}
+
+func (e *JournalExporter) NewVisitor(writer io.Writer) (fileexport.Visitor, error) {
+	return &journalVisitor{
+		dialect:  e.dialect,
+		writer:   bufio.NewWriter(writer),
+		accounts: make(map[domain.ID]journalAccount),
+		names:    make(map[string]struct{}),
+		fallback: make(map[domain.OperationType]journalAccount),
+		totals:   make(map[domain.ID]int64),
+	}, nil
+}
+
+var _ fileexport.Exporter = (*JournalExporter)(nil)
+
+type journalAccount struct {
+	id   domain.ID
+	name string
+	path string
+}
+
+type journalVisitor struct {
+	dialect  journalDialect
+	writer   *bufio.Writer
+	accounts map[domain.ID]journalAccount
+	order    []journalAccount
+	balances []*domain.BankAccount
+	names    map[string]struct{}
+	fallback map[domain.OperationType]journalAccount
+	totals   map[domain.ID]int64
+	started  bool
+	openDate time.Time
+}
+
+func (v *journalVisitor) VisitBankAccount(account *domain.BankAccount, _ *domain.AccountProfile) error {
+	if account == nil {
+		return nil
+	}
+	v.register(account.ID(), account.Name(), journalAssetsRoot)
+	v.balances = append(v.balances, account)
+	return nil
+}
+
+func (v *journalVisitor) VisitCategory(category *domain.Category) error {
+	if category == nil {
+		return nil
+	}
+	root := journalExpensesRoot
+	if category.Type() == domain.OperationTypeIncome {
+		root = journalIncomeRoot
+	}
+	v.register(category.ID(), category.Name(), root)
+	return nil
+}
+
+func (v *journalVisitor) VisitOperation(op *domain.Operation) error {
+	if op == nil {
+		return nil
+	}
+	v.start(op.Date())
+
+	asset, ok := v.accounts[op.BankAccountID()]
+	if !ok {
+		asset = v.register(op.BankAccountID(), op.BankAccountID().String(), journalAssetsRoot)
+		v.line("")
+		v.declare(asset, v.openDate)
+	}
+	counter, ok := v.accounts[op.CategoryID()]
+	if !ok {
+		counter = v.uncategorized(op.Type())
+	}
+	v.totals[op.BankAccountID()] += signedAmount(op)
+
+	v.line("")
+	v.header(op.Date(), op.Description())
+	v.meta("id", op.ID().String())
+	v.posting(counter.path, -signedAmount(op))
+	v.posting(asset.path, signedAmount(op))
+	return nil
+}
+
+func (v *journalVisitor) VisitSavedView(*query.SavedView) error {
+	return nil
+}
+
+func (v *journalVisitor) Finalize() error {
+	v.start(time.Now())
+
+	for _, account := range v.balances {
+		opening := account.Balance() - v.totals[account.ID()]
+		if opening == 0 {
+			continue
+		}
+		asset := v.accounts[account.ID()]
+		v.line("")
+		v.header(v.openDate, journalOpeningPayee)
+		v.posting(asset.path, opening)
+		v.elided(journalOpeningAccount)
+	}
+
+	return v.writer.Flush()
+}
+
+func (v *journalVisitor) start(openDate time.Time) {
+	if v.started {
+		return
+	}
+	v.started = true
+	v.openDate = openDate
+
+	if v.dialect == dialectBeancount {
+		v.line(fmt.Sprintf("option \"operating_currency\" \"%s\"", beancountCurrency))
+		v.line("")
+	}
+	for _, account := range v.order {
+		v.declare(account, openDate)
+	}
+	if v.dialect == dialectBeancount {
+		v.line(fmt.Sprintf("%s open %s", v.date(openDate), v.accountName(journalOpeningAccount)))
+	} else {
+		v.line("account " + journalOpeningAccount)
+	}
+}
+
+func (v *journalVisitor) uncategorized(typ domain.OperationType) journalAccount {
+	if account, ok := v.fallback[typ]; ok {
+		return account
+	}
+	root := journalExpensesRoot
+	if typ == domain.OperationTypeIncome {
+		root = journalIncomeRoot
+	}
+	account := v.register("", "Без категории", root)
+	v.fallback[typ] = account
+	v.line("")
+	v.declare(account, v.openDate)
+	return account
+}
+
+func (v *journalVisitor) register(id domain.ID, name, root string) journalAccount {
+	base := root + ":" + journalPath(name)
+	path := base
+	for suffix := 2; ; suffix++ {
+		if _, taken := v.names[v.accountName(path)]; !taken {
+			break
+		}
+		path = fmt.Sprintf("%s %d", base, suffix)
+	}
+	v.names[v.accountName(path)] = struct{}{}
+
+	account := journalAccount{id: id, name: name, path: path}
+	if id != "" {
+		v.accounts[id] = account
+	}
+	v.order = append(v.order, account)
+	return account
+}
+
+func (v *journalVisitor) declare(account journalAccount, openDate time.Time) {
+	if v.dialect == dialectBeancount {
+		line := fmt.Sprintf("%s open %s", v.date(openDate), v.accountName(account.path))
+		if strings.HasPrefix(account.path, journalAssetsRoot) {
+			line += " " + beancountCurrency
+		}
+		v.line(line)
+	} else {
+		v.line("account " + account.path)
+	}
+	if account.id != "" {
+		v.meta("id", account.id.String())
+	}
+	v.meta("name", account.name)
+}
+
+func (v *journalVisitor) header(date time.Time, description string) {
+	description = journalText(description)
+	if v.dialect == dialectBeancount {
+		v.line(fmt.Sprintf("%s * %s", v.date(date), beancountString(description)))
+		return
+	}
+	v.line(strings.TrimRight(fmt.Sprintf("%s * %s", v.date(date), description), " "))
+}
+
+func (v *journalVisitor) posting(account string, amount int64) {
+	name := v.accountName(account)
+	value := fmt.Sprintf("%d", amount)
+	if v.dialect == dialectBeancount {
+		value += " " + beancountCurrency
+	}
+	v.line(fmt.Sprintf("    %-40s  %12s", name, value))
+}
+
+func (v *journalVisitor) elided(account string) {
+	v.line("    " + v.accountName(account))
+}
+
+func (v *journalVisitor) meta(key, value string) {
+	if v.dialect == dialectBeancount {
+		v.line(fmt.Sprintf("    %s: %s", key, beancountString(value)))
+		return
+	}
+	v.line(fmt.Sprintf("    ; %s: %s", key, journalText(value)))
+}
+
+func (v *journalVisitor) date(value time.Time) string {
+	if v.dialect == dialectLedger {
+		return value.Format("2006/01/02")
+	}
+	return value.Format("2006-01-02")
+}
+
+func (v *journalVisitor) accountName(path string) string {
+	if v.dialect != dialectBeancount {
+		return path
+	}
+	parts := strings.Split(path, ":")
+	for idx, part := range parts {
+		parts[idx] = beancountComponent(part)
+	}
+	return strings.Join(parts, ":")
+}
+
+func (v *journalVisitor) line(value string) {
+	_, _ = v.writer.WriteString(value)
+	_ = v.writer.WriteByte('\n')
+}
+
+func journalPath(name string) string {
+	parts := strings.Split(name, ":")
+	for idx, part := range parts {
+		parts[idx] = journalComponent(part)
+	}
+	return strings.Join(parts, ":")
+}
+
+func journalComponent(value string) string {
+	value = strings.Join(strings.Fields(journalText(value)), " ")
+	value = strings.TrimLeft(value, "([")
+	value = strings.TrimRight(value, ")]")
+	if value == "" {
+		return "_"
+	}
+	return value
+}
+
+func journalText(value string) string {
+	return strings.TrimSpace(journalSeparators.Replace(value))
+}
+
+func beancountComponent(value string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	out := []rune(strings.TrimRight(b.String(), "-"))
+	if len(out) == 0 {
+		return "X"
+	}
+	if upper := unicode.ToUpper(out[0]); unicode.IsUpper(upper) || unicode.IsDigit(upper) {
+		out[0] = upper
+		return string(out)
+	}
+	return "X" + string(out)
+}
+
+func beancountString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+var _ fileexport.Visitor = (*journalVisitor)(nil)
@@ -1,13 +1,15 @@
 package fileexport
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
 	"io"
 
 	appfiles "kpo-hw-2/internal/application/files"
 	fileexport "kpo-hw-2/internal/application/files/export"
 	"kpo-hw-2/internal/domain"
-	filesmodel "kpo-hw-2/internal/files/model"
+	"kpo-hw-2/internal/domain/query"
 )
 
 type JSONExporter struct{}
@@ -27,71 +29,94 @@ func (e *JSONExporter) Format() appfiles.Format {
 
 func (e *JSONExporter) NewVisitor(writer io.Writer) (fileexport.Visitor, error) {
 	return &jsonVisitor{
-		writer: writer,
+		writer: bufio.NewWriter(writer),
 	}, nil
 }
 
 var _ fileexport.Exporter = (*JSONExporter)(nil)
 
 type jsonVisitor struct {
-	writer  io.Writer
-	payload filesmodel.Payload
+	writer *bufio.Writer
+	cursor sectionCursor
+	fields int
 }
 
-func (v *jsonVisitor) VisitBankAccount(account *domain.BankAccount) error {
+func (v *jsonVisitor) VisitBankAccount(account *domain.BankAccount, profile *domain.AccountProfile) error {
 	if account == nil {
 		return nil
 	}
-	v.payload.Accounts = append(v.payload.Accounts, filesmodel.Account{
-		ID:      account.ID().String(),
-		Name:    account.Name(),
-		Balance: account.Balance(),
-	})
-	return nil
+	return v.write(sectionAccounts, accountModel(account, profile))
 }
 
 func (v *jsonVisitor) VisitCategory(category *domain.Category) error {
 	if category == nil {
 		return nil
 	}
-	v.payload.Categories = append(v.payload.Categories, filesmodel.Category{
-		ID:   category.ID().String(),
-		Type: string(category.Type()),
-		Name: category.Name(),
-	})
-	return nil
+	return v.write(sectionCategories, categoryModel(category))
 }
 
 func (v *jsonVisitor) VisitOperation(operation *domain.Operation) error {
 	if operation == nil {
 		return nil
 	}
-	v.payload.Operations = append(v.payload.Operations, filesmodel.Operation{
-		ID:            operation.ID().String(),
-		Type:          string(operation.Type()),
-		BankAccountID: operation.BankAccountID().String(),
-		CategoryID:    operation.CategoryID().String(),
-		Amount:        operation.Amount(),
-		Date:          operation.Date(),
-		Description:   operation.Description(),
-	})
-	return nil
+	return v.write(sectionOperations, operationModel(operation))
+}
+
+func (v *jsonVisitor) VisitSavedView(view *query.SavedView) error {
+	if view == nil {
+		return nil
+	}
+	return v.write(sectionViews, savedViewModel(view))
 }
 
 func (v *jsonVisitor) Finalize() error {
-	payload := struct {
-		Accounts   []filesmodel.Account   `json:"accounts"`
-		Categories []filesmodel.Category  `json:"categories"`
-		Operations []filesmodel.Operation `json:"operations"`
-	}{
-		Accounts:   v.payload.Accounts,
-		Categories: v.payload.Categories,
-		Operations: v.payload.Operations,
+	if err := v.cursor.move(sectionEnd, v.closeSection); err != nil {
+		return err
 	}
+	_, _ = v.writer.WriteString("\n}\n")
+	return v.writer.Flush()
+}
 
-	encoder := json.NewEncoder(v.writer)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(payload)
+func (v *jsonVisitor) write(section exportSection, item any) error {
+	if err := v.cursor.move(section, v.closeSection); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(item, "    ", "  ")
+	if err != nil {
+		return err
+	}
+
+	if v.cursor.items == 0 {
+		v.field(section)
+		_, _ = v.writer.WriteString("[\n    ")
+	} else {
+		_, _ = v.writer.WriteString(",\n    ")
+	}
+	_, _ = v.writer.Write(data)
+	v.cursor.items++
+
+	return nil
+}
+
+func (v *jsonVisitor) field(section exportSection) {
+	if v.fields == 0 {
+		_, _ = v.writer.WriteString("{\n")
+	} else {
+		_, _ = v.writer.WriteString(",\n")
+	}
+	_, _ = fmt.Fprintf(v.writer, "  %q: ", section.key())
+	v.fields++
+}
+
+func (v *jsonVisitor) closeSection(section exportSection, items int) {
+	switch {
+	case items > 0:
+		_, _ = v.writer.WriteString("\n  ]")
+	case section != sectionViews:
+		v.field(section)
+		_, _ = v.writer.WriteString("null")
+	}
 }
 
 var _ fileexport.Visitor = (*jsonVisitor)(nil)
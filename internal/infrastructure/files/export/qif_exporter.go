@@ -0,0 +1,205 @@
+package fileexport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	appfiles "kpo-hw-2/internal/application/files"
+	fileexport "kpo-hw-2/internal/application/files/export"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+)
+
+const (
+	qifDateLayout     = "01/02/2006"
+	qifOpeningBalance = "Opening Balance"
+)
+
+var qifLineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+type QIFExporter struct{}
+
+func NewQIFExporter() *QIFExporter {
+	return &QIFExporter{}
+}
+
+func (e *QIFExporter) Format() appfiles.Format {
+	return appfiles.Format{
+		Key:         "qif",
+		Title:       "QIF",
+		Description: "Экспорт счетов и операций в формате Quicken Interchange Format.",
+		Extension:   "qif",
+	}
+}
+
+func (e *QIFExporter) NewVisitor(writer io.Writer) (fileexport.Visitor, error) {
+	return &qifVisitor{
+		writer:     bufio.NewWriter(writer),
+		accounts:   make(map[domain.ID]*qifAccount),
+		categories: make(map[domain.ID]*domain.Category),
+	}, nil
+}
+
+var _ fileexport.Exporter = (*QIFExporter)(nil)
+
+type qifAccount struct {
+	id      domain.ID
+	name    string
+	typ     string
+	balance int64
+	total   int64
+	first   time.Time
+	seen    bool
+}
+
+type qifVisitor struct {
+	writer     *bufio.Writer
+	accounts   map[domain.ID]*qifAccount
+	order      []*qifAccount
+	listed     bool
+	categories map[domain.ID]*domain.Category
+	current    domain.ID
+}
+
+func (v *qifVisitor) VisitBankAccount(account *domain.BankAccount, profile *domain.AccountProfile) error {
+	if account == nil {
+		return nil
+	}
+	typ := "Bank"
+	if profile != nil && profile.Kind() == domain.AccountKindCredit {
+		typ = "CCard"
+	}
+	entry := &qifAccount{id: account.ID(), name: account.Name(), typ: typ, balance: account.Balance()}
+	v.accounts[account.ID()] = entry
+	v.order = append(v.order, entry)
+	return nil
+}
+
+func (v *qifVisitor) VisitCategory(category *domain.Category) error {
+	if category == nil {
+		return nil
+	}
+	v.listAccounts()
+	if len(v.categories) == 0 {
+		v.line("!Type:Cat")
+	}
+	v.categories[category.ID()] = category
+	v.field('N', category.Name())
+	if category.Type() == domain.OperationTypeIncome {
+		v.line("I")
+	} else {
+		v.line("E")
+	}
+	v.line("^")
+	return nil
+}
+
+func (v *qifVisitor) VisitOperation(op *domain.Operation) error {
+	if op == nil {
+		return nil
+	}
+	v.listAccounts()
+	entry, ok := v.accounts[op.BankAccountID()]
+	if !ok {
+		entry = &qifAccount{id: op.BankAccountID(), name: op.BankAccountID().String(), typ: "Bank"}
+		v.accounts[op.BankAccountID()] = entry
+	}
+	if !entry.seen {
+		entry.first, entry.seen = op.Date(), true
+	}
+	entry.total += signedAmount(op)
+	v.switchTo(entry)
+
+	v.field('D', op.Date().Format(qifDateLayout))
+	v.field('T', formatQIFAmount(signedAmount(op)))
+	if description := op.Description(); description != "" {
+		v.field('P', description)
+	}
+	if category, ok := v.categories[op.CategoryID()]; ok {
+		v.field('L', category.Name())
+	}
+	v.line("^")
+	return nil
+}
+
+func (v *qifVisitor) VisitSavedView(*query.SavedView) error {
+	return nil
+}
+
+func (v *qifVisitor) Finalize() error {
+	v.listAccounts()
+	for _, entry := range v.order {
+		opening := entry.balance - entry.total
+		if opening == 0 {
+			continue
+		}
+		date := time.Now()
+		if entry.seen {
+			date = entry.first
+		}
+		v.switchTo(entry)
+		v.field('D', date.Format(qifDateLayout))
+		v.field('T', formatQIFAmount(opening))
+		v.field('P', qifOpeningBalance)
+		v.field('L', "["+entry.name+"]")
+		v.line("^")
+	}
+	return v.writer.Flush()
+}
+
+func (v *qifVisitor) listAccounts() {
+	if v.listed {
+		return
+	}
+	v.listed = true
+	if len(v.order) == 0 {
+		return
+	}
+	v.line("!Option:AutoSwitch")
+	v.line("!Account")
+	for _, entry := range v.order {
+		v.field('N', entry.name)
+		v.field('T', entry.typ)
+		v.line("^")
+	}
+	v.line("!Clear:AutoSwitch")
+}
+
+func (v *qifVisitor) switchTo(account *qifAccount) {
+	if v.current == account.id {
+		return
+	}
+	v.current = account.id
+	v.line("!Account")
+	v.field('N', account.name)
+	v.field('T', account.typ)
+	v.line("^")
+	v.line("!Type:" + account.typ)
+}
+
+func (v *qifVisitor) line(value string) {
+	_, _ = v.writer.WriteString(value)
+	_ = v.writer.WriteByte('\n')
+}
+
+func (v *qifVisitor) field(code byte, value string) {
+	value = qifLineBreaks.Replace(value)
+	_ = v.writer.WriteByte(code)
+	v.line(value)
+}
+
+func signedAmount(op *domain.Operation) int64 {
+	if op.Type() == domain.OperationTypeExpense {
+		return -op.Amount()
+	}
+	return op.Amount()
+}
+
+func formatQIFAmount(amount int64) string {
+	return fmt.Sprintf("%d.00", amount)
+}
+
+var _ fileexport.Visitor = (*qifVisitor)(nil)
@@ -0,0 +1,92 @@
+package fileexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	appfiles "kpo-hw-2/internal/application/files"
+)
+
+type ReportWriter struct{}
+
+func NewReportWriter() *ReportWriter {
+	return &ReportWriter{}
+}
+
+func (ReportWriter) SaveReport(path string, format appanalytics.ReportFormat, table appanalytics.ReportTable) error {
+	var write func(io.Writer, appanalytics.ReportTable) error
+	switch format {
+	case appanalytics.ReportFormatCSV:
+		write = WriteReportCSV
+	case appanalytics.ReportFormatMarkdown:
+		write = WriteReportMarkdown
+	default:
+		return fmt.Errorf("%w: %q", appanalytics.ErrUnsupportedReportFormat, format)
+	}
+
+	return appfiles.WriteFile(path, func(writer io.Writer) error {
+		return write(writer, table)
+	})
+}
+
+func WriteReportCSV(writer io.Writer, table appanalytics.ReportTable) error {
+	out := csv.NewWriter(writer)
+	if err := out.Write(table.Keys); err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		if err := out.Write(row.Cells); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func WriteReportMarkdown(writer io.Writer, table appanalytics.ReportTable) error {
+	var b strings.Builder
+
+	if table.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", table.Title)
+	}
+
+	b.WriteString("|")
+	for _, column := range table.Columns {
+		b.WriteString(" " + escapeMarkdownCell(column) + " |")
+	}
+	b.WriteString("\n|")
+	for idx := range table.Columns {
+		if idx < len(table.Numeric) && table.Numeric[idx] {
+			b.WriteString(" ---: |")
+		} else {
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+
+	for _, row := range table.Rows {
+		b.WriteString("|")
+		for _, cell := range row.Cells {
+			cell = escapeMarkdownCell(cell)
+			if row.Total && cell != "" {
+				cell = "**" + cell + "**"
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+var _ appanalytics.ReportWriter = (*ReportWriter)(nil)
@@ -0,0 +1,77 @@
+package fileexport
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+)
+
+func TestReportWriterSaveReport(t *testing.T) {
+	table := appanalytics.ReportTable{
+		Title:   "Отчёт",
+		Keys:    []string{"name", "amount"},
+		Columns: []string{"Имя", "Сумма"},
+		Numeric: []bool{false, true},
+		Rows: []appanalytics.ReportRow{
+			{Cells: []string{"a|b", "10"}},
+			{Cells: []string{"Итого", "10"}, Total: true},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		format  appanalytics.ReportFormat
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "csv",
+			format: appanalytics.ReportFormatCSV,
+			want:   "name,amount\na|b,10\nИтого,10\n",
+		},
+		{
+			name:   "markdown",
+			format: appanalytics.ReportFormatMarkdown,
+			want: "# Отчёт\n\n" +
+				"| Имя | Сумма |\n" +
+				"| --- | ---: |\n" +
+				"| a\\|b | 10 |\n" +
+				"| **Итого** | **10** |\n",
+		},
+		{
+			name:    "unsupported",
+			format:  appanalytics.ReportFormat("pdf"),
+			wantErr: appanalytics.ErrUnsupportedReportFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "nested", "report")
+			err := NewReportWriter().SaveReport(path, tt.format, table)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SaveReport error = %v, want %v", err, tt.wantErr)
+				}
+				if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+					t.Fatalf("report file created for unsupported format")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SaveReport: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read report: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("report = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
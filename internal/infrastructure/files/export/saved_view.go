@@ -0,0 +1,43 @@
+package fileexport
+
+import (
+	"kpo-hw-2/internal/domain/query"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+func savedViewModel(view *query.SavedView) filesmodel.SavedView {
+	criteria := view.Criteria()
+	period := view.Period()
+	minAmount, maxAmount := criteria.AmountRange()
+	from, to := period.Bounds()
+
+	return filesmodel.SavedView{
+		ID:                view.ID().String(),
+		Name:              view.Name(),
+		Accounts:          selectorStrings(criteria.Accounts()),
+		ExcludeAccounts:   criteria.Accounts().IsExclude(),
+		Categories:        selectorStrings(criteria.Categories()),
+		ExcludeCategories: criteria.Categories().IsExclude(),
+		Types:             selectorStrings(criteria.Types()),
+		ExcludeTypes:      criteria.Types().IsExclude(),
+		MinAmount:         minAmount,
+		MaxAmount:         maxAmount,
+		Texts:             criteria.Texts(),
+		Period:            string(period.Kind()),
+		PeriodDays:        period.Days(),
+		From:              from,
+		To:                to,
+	}
+}
+
+func selectorStrings[T ~string](selector query.Selector[T]) []string {
+	values := selector.Values()
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, string(value))
+	}
+	return out
+}
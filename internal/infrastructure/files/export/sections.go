@@ -0,0 +1,81 @@
+package fileexport
+
+import (
+	fileexport "kpo-hw-2/internal/application/files/export"
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+type exportSection int
+
+const (
+	sectionAccounts exportSection = iota
+	sectionCategories
+	sectionOperations
+	sectionViews
+	sectionEnd
+)
+
+func (s exportSection) key() string {
+	switch s {
+	case sectionAccounts:
+		return "accounts"
+	case sectionCategories:
+		return "categories"
+	case sectionOperations:
+		return "operations"
+	default:
+		return "views"
+	}
+}
+
+type sectionCursor struct {
+	current exportSection
+	items   int
+}
+
+func (c *sectionCursor) move(next exportSection, closeSection func(exportSection, int)) error {
+	if next < c.current {
+		return fileexport.ErrVisitOrder
+	}
+	for c.current < next {
+		closeSection(c.current, c.items)
+		c.current++
+		c.items = 0
+	}
+	return nil
+}
+
+func accountModel(account *domain.BankAccount, profile *domain.AccountProfile) filesmodel.Account {
+	model := filesmodel.Account{
+		ID:      account.ID().String(),
+		Name:    account.Name(),
+		Balance: account.Balance(),
+	}
+	if profile != nil {
+		model.Kind = string(profile.Kind())
+		model.Group = profile.Group()
+	}
+	return model
+}
+
+func categoryModel(category *domain.Category) filesmodel.Category {
+	return filesmodel.Category{
+		ID:   category.ID().String(),
+		Type: string(category.Type()),
+		Name: category.Name(),
+	}
+}
+
+func operationModel(operation *domain.Operation) filesmodel.Operation {
+	return filesmodel.Operation{
+		ID:            operation.ID().String(),
+		Type:          string(operation.Type()),
+		BankAccountID: operation.BankAccountID().String(),
+		CategoryID:    operation.CategoryID().String(),
+		Amount:        operation.Amount(),
+		Date:          operation.Date(),
+		Description:   operation.Description(),
+		UpdatedAt:     operation.UpdatedAt(),
+	}
+}
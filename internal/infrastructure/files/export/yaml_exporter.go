@@ -1,6 +1,8 @@
 package fileexport
 
 import (
+	"bufio"
+	"bytes"
 	"io"
 
 	"gopkg.in/yaml.v3"
@@ -8,7 +10,7 @@ import (
 	appfiles "kpo-hw-2/internal/application/files"
 	fileexport "kpo-hw-2/internal/application/files/export"
 	"kpo-hw-2/internal/domain"
-	filesmodel "kpo-hw-2/internal/files/model"
+	"kpo-hw-2/internal/domain/query"
 )
 
 type YAMLExporter struct{}
@@ -28,75 +30,88 @@ func (e *YAMLExporter) Format() appfiles.Format {
 
 func (e *YAMLExporter) NewVisitor(writer io.Writer) (fileexport.Visitor, error) {
 	return &yamlVisitor{
-		writer: writer,
+		writer: bufio.NewWriter(writer),
 	}, nil
 }
 
 var _ fileexport.Exporter = (*YAMLExporter)(nil)
 
 type yamlVisitor struct {
-	writer  io.Writer
-	payload filesmodel.Payload
+	writer *bufio.Writer
+	cursor sectionCursor
+	buffer bytes.Buffer
 }
 
-func (v *yamlVisitor) VisitBankAccount(account *domain.BankAccount) error {
+func (v *yamlVisitor) VisitBankAccount(account *domain.BankAccount, profile *domain.AccountProfile) error {
 	if account == nil {
 		return nil
 	}
-	v.payload.Accounts = append(v.payload.Accounts, filesmodel.Account{
-		ID:      account.ID().String(),
-		Name:    account.Name(),
-		Balance: account.Balance(),
-	})
-	return nil
+	return v.write(sectionAccounts, accountModel(account, profile))
 }
 
 func (v *yamlVisitor) VisitCategory(category *domain.Category) error {
 	if category == nil {
 		return nil
 	}
-	v.payload.Categories = append(v.payload.Categories, filesmodel.Category{
-		ID:   category.ID().String(),
-		Type: string(category.Type()),
-		Name: category.Name(),
-	})
-	return nil
+	return v.write(sectionCategories, categoryModel(category))
 }
 
 func (v *yamlVisitor) VisitOperation(operation *domain.Operation) error {
 	if operation == nil {
 		return nil
 	}
-	v.payload.Operations = append(v.payload.Operations, filesmodel.Operation{
-		ID:            operation.ID().String(),
-		Type:          string(operation.Type()),
-		BankAccountID: operation.BankAccountID().String(),
-		CategoryID:    operation.CategoryID().String(),
-		Amount:        operation.Amount(),
-		Date:          operation.Date(),
-		Description:   operation.Description(),
-	})
-	return nil
+	return v.write(sectionOperations, operationModel(operation))
+}
+
+func (v *yamlVisitor) VisitSavedView(view *query.SavedView) error {
+	if view == nil {
+		return nil
+	}
+	return v.write(sectionViews, savedViewModel(view))
 }
 
 func (v *yamlVisitor) Finalize() error {
-	payload := struct {
-		Accounts   []filesmodel.Account   `yaml:"accounts"`
-		Categories []filesmodel.Category  `yaml:"categories"`
-		Operations []filesmodel.Operation `yaml:"operations"`
-	}{
-		Accounts:   v.payload.Accounts,
-		Categories: v.payload.Categories,
-		Operations: v.payload.Operations,
+	if err := v.cursor.move(sectionEnd, v.closeSection); err != nil {
+		return err
+	}
+	return v.writer.Flush()
+}
+
+func (v *yamlVisitor) write(section exportSection, item any) error {
+	if err := v.cursor.move(section, v.closeSection); err != nil {
+		return err
 	}
 
-	encoder := yaml.NewEncoder(v.writer)
+	v.buffer.Reset()
+	encoder := yaml.NewEncoder(&v.buffer)
 	encoder.SetIndent(2)
-	if err := encoder.Encode(payload); err != nil {
+	if err := encoder.Encode([]any{item}); err != nil {
 		_ = encoder.Close()
 		return err
 	}
-	return encoder.Close()
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	if v.cursor.items == 0 {
+		_, _ = v.writer.WriteString(section.key() + ":\n")
+	}
+	for _, line := range bytes.SplitAfter(v.buffer.Bytes(), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		_, _ = v.writer.WriteString("  ")
+		_, _ = v.writer.Write(line)
+	}
+	v.cursor.items++
+
+	return nil
+}
+
+func (v *yamlVisitor) closeSection(section exportSection, items int) {
+	if items == 0 && section != sectionViews {
+		_, _ = v.writer.WriteString(section.key() + ": []\n")
+	}
 }
 
 var _ fileexport.Visitor = (*yamlVisitor)(nil)
@@ -1,7 +1,6 @@
 package fileimport
 
 import (
-	"bytes"
 	"encoding/csv"
 	"errors"
 	"fmt"
@@ -15,12 +14,18 @@ import (
 	filesmodel "kpo-hw-2/internal/files/model"
 )
 
-type CSVImporter struct{}
+type CSVImporter struct {
+	options CSVOptions
+}
 
 func NewCSVImporter() *CSVImporter {
 	return &CSVImporter{}
 }
 
+func NewCSVImporterWithOptions(options CSVOptions) *CSVImporter {
+	return &CSVImporter{options: options}
+}
+
 func (i *CSVImporter) Format() appfiles.Format {
 	return appfiles.Format{
 		Key:         "csv",
@@ -30,15 +35,38 @@ func (i *CSVImporter) Format() appfiles.Format {
 	}
 }
 
-func (i *CSVImporter) Parse(data []byte) (filesmodel.Payload, error) {
-	if len(data) == 0 {
-		return filesmodel.Payload{}, nil
+func (i *CSVImporter) ParseWithDialect(source io.Reader, dialect fileimport.Dialect) (filesmodel.Payload, error) {
+	options := i.options
+	if encoding := strings.TrimSpace(dialect.Encoding); encoding != "" {
+		options.Encoding = encoding
+	}
+	if dialect.Delimiter != "" {
+		options.Delimiter = dialect.Delimiter
 	}
+	return NewCSVImporterWithOptions(options).Parse(source)
+}
+
+func (i *CSVImporter) Parse(source io.Reader) (filesmodel.Payload, error) {
+	return fileimport.Collect(func(visitor fileimport.RecordVisitor) error {
+		return i.Stream(source, visitor)
+	})
+}
 
-	reader := csv.NewReader(bytes.NewReader(data))
+func (i *CSVImporter) Stream(source io.Reader, visitor fileimport.RecordVisitor) error {
+	decoded, err := decodeReader(source, i.options.Encoding)
+	if err != nil {
+		return fmt.Errorf("csv: %w", err)
+	}
+	delimiter, err := resolveDelimiter(i.options.Delimiter, decoded)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.ReuseRecord = true
+	reader.Comma = delimiter
 	reader.FieldsPerRecord = -1
 
-	var payload filesmodel.Payload
 	var line int
 	headerSkipped := false
 
@@ -48,7 +76,7 @@ func (i *CSVImporter) Parse(data []byte) (filesmodel.Payload, error) {
 			if errors.Is(err, io.EOF) {
 				break
 			}
-			return filesmodel.Payload{}, err
+			return err
 		}
 
 		line++
@@ -58,7 +86,7 @@ func (i *CSVImporter) Parse(data []byte) (filesmodel.Payload, error) {
 		}
 
 		for i := range record {
-			record[i] = strings.TrimSpace(strings.TrimPrefix(record[i], "\ufeff"))
+			record[i] = strings.TrimSpace(record[i])
 		}
 
 		if !headerSkipped && len(record) > 0 && strings.EqualFold(record[0], "entity") {
@@ -71,33 +99,47 @@ func (i *CSVImporter) Parse(data []byte) (filesmodel.Payload, error) {
 		case "account":
 			account, err := parseAccountRecord(record)
 			if err != nil {
-				return filesmodel.Payload{}, fmt.Errorf("csv: parse account line %d: %w", line, err)
+				return fmt.Errorf("csv: parse account line %d: %w", line, err)
+			}
+			if err := visitor.VisitAccount(account); err != nil {
+				return err
 			}
-			payload.Accounts = append(payload.Accounts, account)
 		case "category":
 			category, err := parseCategoryRecord(record)
 			if err != nil {
-				return filesmodel.Payload{}, fmt.Errorf("csv: parse category line %d: %w", line, err)
+				return fmt.Errorf("csv: parse category line %d: %w", line, err)
+			}
+			if err := visitor.VisitCategory(category); err != nil {
+				return err
 			}
-			payload.Categories = append(payload.Categories, category)
 		case "operation":
 			operation, err := parseOperationRecord(record)
 			if err != nil {
-				return filesmodel.Payload{}, fmt.Errorf("csv: parse operation line %d: %w", line, err)
+				return fmt.Errorf("csv: parse operation line %d: %w", line, err)
+			}
+			if err := visitor.VisitOperation(operation); err != nil {
+				return err
+			}
+		case "view":
+			view, err := parseViewRecord(record)
+			if err != nil {
+				return fmt.Errorf("csv: parse view line %d: %w", line, err)
+			}
+			if err := visitor.VisitView(view); err != nil {
+				return err
 			}
-			payload.Operations = append(payload.Operations, operation)
 		case "":
 			continue
 		default:
-			return filesmodel.Payload{}, fmt.Errorf("csv: unknown entity %q on line %d", record[0], line)
+			return fmt.Errorf("csv: unknown entity %q on line %d", record[0], line)
 		}
 	}
 
-	return payload, nil
+	return nil
 }
 
 func recordValue(record []string, idx int) string {
-	if idx >= len(record) {
+	if idx < 0 || idx >= len(record) {
 		return ""
 	}
 	return record[idx]
@@ -109,18 +151,22 @@ func parseAccountRecord(record []string) (filesmodel.Account, error) {
 	balanceStr := recordValue(record, 4)
 
 	var balance int64
+	var rounded bool
 	if balanceStr != "" {
-		parsed, err := strconv.ParseInt(balanceStr, 10, 64)
+		parsed, fractional, err := parseLocaleAmount(balanceStr, "", "", 1)
 		if err != nil {
 			return filesmodel.Account{}, fmt.Errorf("balance: %w", err)
 		}
-		balance = parsed
+		balance, rounded = parsed, fractional
 	}
 
 	return filesmodel.Account{
 		ID:      id,
 		Name:    name,
 		Balance: balance,
+		Kind:    recordValue(record, 3),
+		Group:   recordValue(record, 9),
+		Rounded: rounded,
 	}, nil
 }
 
@@ -135,24 +181,34 @@ func parseCategoryRecord(record []string) (filesmodel.Category, error) {
 func parseOperationRecord(record []string) (filesmodel.Operation, error) {
 	amountStr := recordValue(record, 7)
 	var amount int64
+	var rounded bool
 	if amountStr != "" {
-		parsed, err := strconv.ParseInt(amountStr, 10, 64)
+		parsed, fractional, err := parseLocaleAmount(amountStr, "", "", 1)
 		if err != nil {
 			return filesmodel.Operation{}, fmt.Errorf("amount: %w", err)
 		}
-		amount = parsed
+		amount, rounded = parsed, fractional
 	}
 
 	dateStr := recordValue(record, 8)
 	var date time.Time
 	if dateStr != "" {
-		parsed, err := time.Parse(time.RFC3339, dateStr)
+		parsed, err := parseLocaleDate(dateStr, "")
 		if err != nil {
 			return filesmodel.Operation{}, fmt.Errorf("date: %w", err)
 		}
 		date = parsed
 	}
 
+	var updatedAt time.Time
+	if updatedStr := recordValue(record, 10); updatedStr != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, updatedStr)
+		if err != nil {
+			return filesmodel.Operation{}, fmt.Errorf("updated_at: %w", err)
+		}
+		updatedAt = parsed
+	}
+
 	return filesmodel.Operation{
 		ID:            recordValue(record, 1),
 		Type:          recordValue(record, 3),
@@ -161,7 +217,104 @@ func parseOperationRecord(record []string) (filesmodel.Operation, error) {
 		Amount:        amount,
 		Date:          date,
 		Description:   recordValue(record, 9),
+		UpdatedAt:     updatedAt,
+		Rounded:       rounded,
 	}, nil
 }
 
-var _ fileimport.Importer = (*CSVImporter)(nil)
+func parseViewRecord(record []string) (filesmodel.SavedView, error) {
+	view := filesmodel.SavedView{
+		ID:   recordValue(record, 1),
+		Name: recordValue(record, 2),
+	}
+	view.Types, view.ExcludeTypes = splitCSVList(recordValue(record, 3))
+	view.Accounts, view.ExcludeAccounts = splitCSVList(recordValue(record, 5))
+	view.Categories, view.ExcludeCategories = splitCSVList(recordValue(record, 6))
+
+	if amountStr := recordValue(record, 7); amountStr != "" {
+		minStr, maxStr, ok := strings.Cut(amountStr, "..")
+		if !ok {
+			return filesmodel.SavedView{}, fmt.Errorf("amount: invalid range %q", amountStr)
+		}
+		var err error
+		if view.MinAmount, err = parseOptionalInt(minStr); err != nil {
+			return filesmodel.SavedView{}, fmt.Errorf("amount: %w", err)
+		}
+		if view.MaxAmount, err = parseOptionalInt(maxStr); err != nil {
+			return filesmodel.SavedView{}, fmt.Errorf("amount: %w", err)
+		}
+	}
+
+	if err := parseViewPeriod(recordValue(record, 8), &view); err != nil {
+		return filesmodel.SavedView{}, fmt.Errorf("date: %w", err)
+	}
+
+	if texts := recordValue(record, 9); texts != "" {
+		view.Texts = strings.Split(texts, "|")
+	}
+
+	return view, nil
+}
+
+func splitCSVList(value string) ([]string, bool) {
+	if value == "" {
+		return nil, false
+	}
+	exclude := strings.HasPrefix(value, "!")
+	return strings.Split(strings.TrimPrefix(value, "!"), "|"), exclude
+}
+
+func parseOptionalInt(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, _, err := parseLocaleAmount(value, "", "", 1)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := parseLocaleDate(value, "")
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseViewPeriod(value string, view *filesmodel.SavedView) error {
+	kind, arg, _ := strings.Cut(value, ":")
+	view.Period = kind
+
+	switch kind {
+	case "last_days":
+		days, err := strconv.Atoi(arg)
+		if err != nil {
+			return err
+		}
+		view.PeriodDays = days
+	case "custom":
+		fromStr, toStr, ok := strings.Cut(arg, "..")
+		if !ok {
+			return fmt.Errorf("invalid period %q", value)
+		}
+		var err error
+		if view.From, err = parseOptionalTime(fromStr); err != nil {
+			return err
+		}
+		if view.To, err = parseOptionalTime(toStr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	_ fileimport.DialectImporter = (*CSVImporter)(nil)
+	_ fileimport.StreamImporter  = (*CSVImporter)(nil)
+)
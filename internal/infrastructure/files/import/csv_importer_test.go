@@ -0,0 +1,81 @@
+package fileimport
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+
+	fileimport "kpo-hw-2/internal/application/files/import"
+)
+
+func TestCSVImporterParseWithDialect(t *testing.T) {
+	windows1251 := func(value string) []byte {
+		encoded, err := charmap.Windows1251.NewEncoder().Bytes([]byte(value))
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		return encoded
+	}
+
+	cases := []struct {
+		name    string
+		data    []byte
+		dialect fileimport.Dialect
+		want    string
+		balance int64
+		wantErr bool
+	}{
+		{
+			name: "auto detects semicolons",
+			data: []byte("account;a1;Сбер;;1 234\n"),
+			want: "Сбер", balance: 1234,
+		},
+		{
+			name:    "explicit windows-1251 and pipe",
+			data:    windows1251("account|a1|Сбер||551\n"),
+			dialect: fileimport.Dialect{Encoding: "windows-1251", Delimiter: "|"},
+			want:    "Сбер", balance: 551,
+		},
+		{
+			name:    "tab delimiter",
+			data:    []byte("account\ta1\tВТБ\t\t352\n"),
+			dialect: fileimport.Dialect{Delimiter: "tab"},
+			want:    "ВТБ", balance: 352,
+		},
+		{
+			name:    "utf-8 rejects windows-1251 bytes",
+			data:    windows1251("account;a1;Сбер;;1\n"),
+			dialect: fileimport.Dialect{Encoding: "utf-8"},
+			wantErr: true,
+		},
+		{
+			name:    "multi-character delimiter",
+			data:    []byte("account;a1;Сбер;;1\n"),
+			dialect: fileimport.Dialect{Delimiter: ";;"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := NewCSVImporter().ParseWithDialect(bytes.NewReader(tc.data), tc.dialect)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", payload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(payload.Accounts) != 1 {
+				t.Fatalf("accounts = %d, want 1", len(payload.Accounts))
+			}
+			account := payload.Accounts[0]
+			if account.Name != tc.want || account.Balance != tc.balance {
+				t.Fatalf("account = %q/%d, want %q/%d", account.Name, account.Balance, tc.want, tc.balance)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package fileimport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	EncodingAuto        = "auto"
+	EncodingUTF8        = "utf-8"
+	EncodingWindows1251 = "windows-1251"
+
+	DelimiterAuto = "auto"
+
+	sniffSize = 64 * 1024
+)
+
+var csvDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"02.01.2006",
+	"02.01.2006 15:04",
+	"02.01.2006 15:04:05",
+	"02.01.06",
+	"02/01/2006",
+}
+
+type CSVOptions struct {
+	Delimiter string
+	Encoding  string
+}
+
+func decodeReader(reader io.Reader, encoding string) (*bufio.Reader, error) {
+	buffered := bufio.NewReaderSize(reader, sniffSize)
+	if bom, err := buffered.Peek(3); err == nil && bytes.Equal(bom, []byte("\ufeff")) {
+		_, _ = buffered.Discard(3)
+	}
+
+	head, complete, err := peekHead(buffered)
+	if err != nil {
+		return nil, err
+	}
+
+	switch normalizeEncoding(encoding) {
+	case EncodingAuto:
+		if validUTF8Prefix(head, complete) {
+			return buffered, nil
+		}
+		return bufio.NewReaderSize(charmap.Windows1251.NewDecoder().Reader(buffered), sniffSize), nil
+	case EncodingUTF8:
+		if !validUTF8Prefix(head, complete) {
+			return nil, fmt.Errorf("data is not valid UTF-8")
+		}
+		return buffered, nil
+	case EncodingWindows1251:
+		return bufio.NewReaderSize(charmap.Windows1251.NewDecoder().Reader(buffered), sniffSize), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
+
+func peekHead(reader *bufio.Reader) ([]byte, bool, error) {
+	head, err := reader.Peek(sniffSize)
+	switch {
+	case err == nil:
+		return head, false, nil
+	case errors.Is(err, io.EOF):
+		return head, true, nil
+	default:
+		return nil, false, err
+	}
+}
+
+func validUTF8Prefix(data []byte, complete bool) bool {
+	if complete {
+		return utf8.Valid(data)
+	}
+	for cut := 0; cut < utf8.UTFMax && cut <= len(data); cut++ {
+		if utf8.Valid(data[:len(data)-cut]) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeEncoding(encoding string) string {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", EncodingAuto:
+		return EncodingAuto
+	case EncodingUTF8, "utf8":
+		return EncodingUTF8
+	case EncodingWindows1251, "cp1251", "windows1251":
+		return EncodingWindows1251
+	default:
+		return encoding
+	}
+}
+
+func resolveDelimiter(value string, reader *bufio.Reader) (rune, error) {
+	switch strings.TrimSpace(value) {
+	case "", DelimiterAuto:
+		head, _, err := peekHead(reader)
+		if err != nil {
+			return 0, err
+		}
+		return detectDelimiter(head), nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("csv: delimiter must be a single character, got %q", value)
+	}
+	return r, nil
+}
+
+func detectDelimiter(data []byte) rune {
+	candidates := []rune{';', ',', '\t', '|'}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		best, bestCount := ',', 0
+		for _, candidate := range candidates {
+			if count := countOutsideQuotes(line, candidate); count > bestCount {
+				best, bestCount = candidate, count
+			}
+		}
+		if bestCount > 0 {
+			return best
+		}
+	}
+	return ','
+}
+
+func countOutsideQuotes(line string, target rune) int {
+	count := 0
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == target && !quoted:
+			count++
+		}
+	}
+	return count
+}
+
+func parseLocaleAmount(value, decimalSeparator, thousandsSeparator string, scale int64) (int64, bool, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		case '\u2212':
+			return '-'
+		}
+		return r
+	}, value)
+	if cleaned == "" {
+		return 0, false, fmt.Errorf("empty value")
+	}
+
+	if decimalSeparator == "" {
+		decimalSeparator = guessDecimalSeparator(cleaned, thousandsSeparator)
+	}
+	if thousandsSeparator = strings.TrimSpace(thousandsSeparator); thousandsSeparator != "" && thousandsSeparator != decimalSeparator {
+		cleaned = strings.ReplaceAll(cleaned, thousandsSeparator, "")
+	}
+	if decimalSeparator != "." {
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+		cleaned = strings.ReplaceAll(cleaned, decimalSeparator, ".")
+	} else {
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	}
+
+	rat, ok := new(big.Rat).SetString(cleaned)
+	if !ok {
+		return 0, false, fmt.Errorf("invalid number %q", value)
+	}
+	if scale <= 0 {
+		scale = 1
+	}
+	rat.Mul(rat, new(big.Rat).SetInt64(scale))
+
+	negative := rat.Sign() < 0
+	rat.Abs(rat)
+	rounded := new(big.Int).Quo(
+		new(big.Int).Add(new(big.Int).Mul(rat.Num(), big.NewInt(2)), rat.Denom()),
+		new(big.Int).Mul(rat.Denom(), big.NewInt(2)),
+	)
+	if !rounded.IsInt64() {
+		return 0, false, fmt.Errorf("number %q is out of range", value)
+	}
+	fractional := !rat.IsInt()
+	if negative {
+		return -rounded.Int64(), fractional, nil
+	}
+	return rounded.Int64(), fractional, nil
+}
+
+func guessDecimalSeparator(value, thousandsSeparator string) string {
+	comma := strings.LastIndex(value, ",")
+	dot := strings.LastIndex(value, ".")
+	switch {
+	case thousandsSeparator == ",":
+		return "."
+	case thousandsSeparator == ".":
+		return ","
+	case comma >= 0 && dot >= 0:
+		if comma > dot {
+			return ","
+		}
+		return "."
+	case comma >= 0:
+		if strings.Count(value, ",") == 1 && len(value)-comma-1 != 3 {
+			return ","
+		}
+		return "."
+	default:
+		return "."
+	}
+}
+
+func parseLocaleDate(value, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if layout != "" {
+		return time.ParseInLocation(layout, value, time.UTC)
+	}
+
+	var firstErr error
+	for _, candidate := range csvDateLayouts {
+		parsed, err := time.ParseInLocation(candidate, value, time.UTC)
+		if err == nil {
+			return parsed, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, firstErr
+}
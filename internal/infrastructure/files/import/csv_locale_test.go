@@ -0,0 +1,44 @@
+package fileimport
+
+import "testing"
+
+func TestParseLocaleAmount(t *testing.T) {
+	cases := []struct {
+		value     string
+		decimal   string
+		thousands string
+		scale     int64
+		want      int64
+		rounded   bool
+		wantErr   bool
+	}{
+		{value: "1 234", want: 1234},
+		{value: "1 234,56", want: 1235, rounded: true},
+		{value: "-1249.50", decimal: ".", want: -1250, rounded: true},
+		{value: "83430.49", decimal: ".", want: 83430, rounded: true},
+		{value: "1,234.00", want: 1234},
+		{value: "1.234,5", decimal: ",", thousands: ".", want: 1235, rounded: true},
+		{value: "12,34", scale: 100, want: 1234},
+		{value: "−5", want: -5},
+		{value: "", wantErr: true},
+		{value: "abc", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, rounded, err := parseLocaleAmount(tc.value, tc.decimal, tc.thousands, tc.scale)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got != tc.want || rounded != tc.rounded {
+				t.Fatalf("got %d (rounded %v), want %d (rounded %v)", got, rounded, tc.want, tc.rounded)
+			}
+		})
+	}
+}
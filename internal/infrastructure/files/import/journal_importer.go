@@ -0,0 +1,397 @@
+package fileimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	appfiles "kpo-hw-2/internal/application/files"
+	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+const (
+	journalAssetsPrefix   = "assets:"
+	journalIncomePrefix   = "income:"
+	journalExpensesPrefix = "expenses:"
+	journalEquityPrefix   = "equity:"
+	journalBankPrefix     = "assets:bank:"
+)
+
+type JournalImporter struct{}
+
+func NewJournalImporter() *JournalImporter {
+	return &JournalImporter{}
+}
+
+func (i *JournalImporter) Format() appfiles.Format {
+	return appfiles.Format{
+		Key:         "ledger",
+		Title:       "Ledger / hledger",
+		Description: "Импорт журнала ledger-cli или hledger.",
+		Extension:   "ledger",
+	}
+}
+
+func (i *JournalImporter) Parse(reader io.Reader) (filesmodel.Payload, error) {
+	decoded, err := decodeReader(reader, EncodingAuto)
+	if err != nil {
+		return filesmodel.Payload{}, fmt.Errorf("journal: %w", err)
+	}
+
+	builder := newJournalBuilder()
+	scanner := bufio.NewScanner(decoded)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var current *journalEntry
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		entry := current
+		current = nil
+		return builder.add(entry)
+	}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if strings.TrimSpace(text) == "" {
+			if err := flush(); err != nil {
+				return filesmodel.Payload{}, err
+			}
+			continue
+		}
+
+		if text[0] == ' ' || text[0] == '\t' {
+			if current == nil {
+				continue
+			}
+			if err := current.addLine(strings.TrimSpace(text)); err != nil {
+				return filesmodel.Payload{}, fmt.Errorf("journal: line %d: %w", line, err)
+			}
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return filesmodel.Payload{}, err
+		}
+
+		switch {
+		case strings.ContainsRune(";#*%|", rune(text[0])):
+			continue
+		case strings.HasPrefix(text, "account "):
+			current = &journalEntry{line: line, directive: true, account: stripJournalComment(text[len("account "):])}
+		case text[0] >= '0' && text[0] <= '9':
+			entry, err := parseJournalHeader(text)
+			if err != nil {
+				return filesmodel.Payload{}, fmt.Errorf("journal: line %d: %w", line, err)
+			}
+			entry.line = line
+			current = entry
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return filesmodel.Payload{}, fmt.Errorf("journal: %w", err)
+	}
+	if err := flush(); err != nil {
+		return filesmodel.Payload{}, err
+	}
+
+	return builder.payload(), nil
+}
+
+var _ fileimport.Importer = (*JournalImporter)(nil)
+
+type journalPosting struct {
+	account string
+	amount  int64
+	elided  bool
+	rounded bool
+}
+
+type journalEntry struct {
+	line        int
+	directive   bool
+	account     string
+	date        time.Time
+	description string
+	meta        map[string]string
+	postings    []journalPosting
+}
+
+func parseJournalHeader(text string) (*journalEntry, error) {
+	text = stripJournalComment(text)
+	dateText, rest, _ := strings.Cut(text, " ")
+	if primary, _, ok := strings.Cut(dateText, "="); ok {
+		dateText = primary
+	}
+	dateText = strings.NewReplacer("/", "-", ".", "-").Replace(dateText)
+	date, err := time.ParseInLocation("2006-01-02", dateText, time.UTC)
+	if err != nil {
+		return nil, fmt.Errorf("date: %w", err)
+	}
+
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "*") || strings.HasPrefix(rest, "!") {
+		rest = strings.TrimSpace(rest[1:])
+	}
+	if strings.HasPrefix(rest, "(") {
+		if end := strings.IndexByte(rest, ')'); end >= 0 {
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+	}
+
+	return &journalEntry{date: date, description: rest}, nil
+}
+
+func (e *journalEntry) addLine(text string) error {
+	if strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+		key, value, ok := strings.Cut(strings.TrimSpace(strings.TrimLeft(text, ";#")), ":")
+		if ok {
+			if e.meta == nil {
+				e.meta = make(map[string]string)
+			}
+			e.meta[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+		return nil
+	}
+	if e.directive {
+		return nil
+	}
+
+	text = stripJournalComment(text)
+	account, amountText := splitJournalPosting(text)
+	account = strings.Trim(account, "()[]")
+	if amountText == "" {
+		e.postings = append(e.postings, journalPosting{account: account, elided: true})
+		return nil
+	}
+
+	amount, rounded, err := parseJournalAmount(amountText)
+	if err != nil {
+		return fmt.Errorf("amount: %w", err)
+	}
+	e.postings = append(e.postings, journalPosting{account: account, amount: amount, rounded: rounded})
+	return nil
+}
+
+func stripJournalComment(text string) string {
+	if idx := strings.IndexByte(text, ';'); idx >= 0 {
+		text = text[:idx]
+	}
+	return strings.TrimSpace(text)
+}
+
+func splitJournalPosting(text string) (string, string) {
+	if idx := strings.Index(text, "\t"); idx >= 0 {
+		return strings.TrimSpace(text[:idx]), strings.TrimSpace(text[idx+1:])
+	}
+	if idx := strings.Index(text, "  "); idx >= 0 {
+		return strings.TrimSpace(text[:idx]), strings.TrimSpace(text[idx+2:])
+	}
+	return strings.TrimSpace(text), ""
+}
+
+func parseJournalAmount(value string) (int64, bool, error) {
+	if at := strings.IndexAny(value, "@="); at >= 0 {
+		value = value[:at]
+	}
+	number := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '-' || r == '+' || r == '.' || r == ',' {
+			return r
+		}
+		return -1
+	}, value)
+	return parseLocaleAmount(number, "", "", 1)
+}
+
+type journalMeta struct {
+	id   string
+	name string
+}
+
+type journalBuilder struct {
+	declared    map[string]journalMeta
+	order       []string
+	accounts    []string
+	balances    map[string]int64
+	rounded     map[string]bool
+	operations  []filesmodel.Operation
+	occurrences map[string]int
+}
+
+func newJournalBuilder() *journalBuilder {
+	return &journalBuilder{
+		declared:    make(map[string]journalMeta),
+		balances:    make(map[string]int64),
+		rounded:     make(map[string]bool),
+		occurrences: make(map[string]int),
+	}
+}
+
+func (b *journalBuilder) add(entry *journalEntry) error {
+	if entry.directive {
+		key := strings.ToLower(entry.account)
+		if _, repeated := b.declared[key]; !repeated {
+			b.order = append(b.order, entry.account)
+		}
+		b.declared[key] = journalMeta{id: entry.meta["id"], name: entry.meta["name"]}
+		if hasJournalPrefix(entry.account, journalAssetsPrefix) {
+			b.account(entry.account)
+		}
+		return nil
+	}
+
+	if len(entry.postings) != 2 {
+		return fmt.Errorf("journal: line %d: expected exactly two postings, got %d", entry.line, len(entry.postings))
+	}
+
+	asset, counter := entry.postings[0], entry.postings[1]
+	if !hasJournalPrefix(asset.account, journalAssetsPrefix) {
+		asset, counter = counter, asset
+	}
+	if !hasJournalPrefix(asset.account, journalAssetsPrefix) {
+		return fmt.Errorf("journal: line %d: transaction has no assets posting", entry.line)
+	}
+
+	amount := asset.amount
+	switch {
+	case asset.elided && counter.elided:
+		return fmt.Errorf("journal: line %d: both postings have no amount", entry.line)
+	case asset.elided:
+		amount = -counter.amount
+	case !counter.elided && asset.amount+counter.amount != 0:
+		return fmt.Errorf("journal: line %d: transaction does not balance", entry.line)
+	}
+
+	account := b.account(asset.account)
+	b.balances[account] += amount
+	rounded := asset.rounded || counter.rounded
+
+	if hasJournalPrefix(counter.account, journalEquityPrefix) {
+		b.rounded[account] = b.rounded[account] || rounded
+		return nil
+	}
+
+	var typ domain.OperationType
+	switch {
+	case hasJournalPrefix(counter.account, journalIncomePrefix):
+		typ = domain.OperationTypeIncome
+	case hasJournalPrefix(counter.account, journalExpensesPrefix):
+		typ = domain.OperationTypeExpense
+	default:
+		return fmt.Errorf("journal: line %d: unsupported account %q", entry.line, counter.account)
+	}
+	if (typ == domain.OperationTypeIncome && amount < 0) || (typ == domain.OperationTypeExpense && amount > 0) {
+		return fmt.Errorf("journal: line %d: %s posting to %q reverses its direction, refunds are not supported", entry.line, typ, counter.account)
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+
+	op := filesmodel.Operation{
+		Type:          string(typ),
+		BankAccountID: b.accountID(account),
+		Amount:        amount,
+		Date:          entry.date,
+		Description:   entry.description,
+		Rounded:       rounded,
+	}
+	if meta, ok := b.declared[strings.ToLower(counter.account)]; ok && validJournalID(meta.id) {
+		op.CategoryID = meta.id
+	} else {
+		op.CategoryName = b.name(counter.account)
+	}
+
+	if id := entry.meta["id"]; validJournalID(id) {
+		op.ID = id
+	} else {
+		key := strings.Join([]string{
+			"journal",
+			account,
+			counter.account,
+			op.Date.Format(time.RFC3339),
+			strconv.FormatInt(op.Amount, 10),
+			op.Description,
+		}, "\x1f")
+		b.occurrences[key]++
+		op.ID = statementID(key, b.occurrences[key])
+	}
+
+	b.operations = append(b.operations, op)
+	return nil
+}
+
+func (b *journalBuilder) account(path string) string {
+	key := strings.ToLower(path)
+	if _, ok := b.balances[key]; !ok {
+		b.balances[key] = 0
+		b.accounts = append(b.accounts, path)
+	}
+	return key
+}
+
+func (b *journalBuilder) accountID(key string) string {
+	if meta, ok := b.declared[key]; ok && validJournalID(meta.id) {
+		return meta.id
+	}
+	return statementID("journal\x1f"+key, 1)
+}
+
+func (b *journalBuilder) name(path string) string {
+	if meta, ok := b.declared[strings.ToLower(path)]; ok && meta.name != "" {
+		return meta.name
+	}
+	for _, prefix := range []string{journalBankPrefix, journalAssetsPrefix, journalIncomePrefix, journalExpensesPrefix} {
+		if hasJournalPrefix(path, prefix) {
+			return path[len(prefix):]
+		}
+	}
+	return path
+}
+
+func (b *journalBuilder) payload() filesmodel.Payload {
+	var payload filesmodel.Payload
+	for _, path := range b.accounts {
+		key := strings.ToLower(path)
+		payload.Accounts = append(payload.Accounts, filesmodel.Account{
+			ID:      b.accountID(key),
+			Name:    b.name(path),
+			Balance: b.balances[key],
+			Rounded: b.rounded[key],
+		})
+	}
+	for _, path := range b.order {
+		var typ domain.OperationType
+		switch {
+		case hasJournalPrefix(path, journalIncomePrefix):
+			typ = domain.OperationTypeIncome
+		case hasJournalPrefix(path, journalExpensesPrefix):
+			typ = domain.OperationTypeExpense
+		default:
+			continue
+		}
+		category := filesmodel.Category{Type: string(typ), Name: b.name(path)}
+		if id := b.declared[strings.ToLower(path)].id; validJournalID(id) {
+			category.ID = id
+		}
+		payload.Categories = append(payload.Categories, category)
+	}
+	payload.Operations = b.operations
+	return payload
+}
+
+func hasJournalPrefix(account, prefix string) bool {
+	return len(account) >= len(prefix) && strings.EqualFold(account[:len(prefix)], prefix)
+}
+
+func validJournalID(value string) bool {
+	_, err := domain.ParseID(value)
+	return err == nil
+}
@@ -0,0 +1,138 @@
+package fileimport
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJournalImporterParse(t *testing.T) {
+	const (
+		cardID   = "01J0000000000000000000CARD"
+		sideID   = "01J0000000000000000000SXDE"
+		salaryID = "01J000000000000000000SAXRY"
+	)
+
+	cases := []struct {
+		name       string
+		journal    string
+		accounts   map[string]int64
+		categories map[string]string
+		operations int
+		wantErr    string
+	}{
+		{
+			name: "declared accounts and categories without postings",
+			journal: `account Assets:Bank:сбер
+    ; id: ` + cardID + `
+    ; name: сбер
+
+account Income:подработка
+    ; id: ` + sideID + `
+    ; name: подработка
+`,
+			accounts:   map[string]int64{cardID: 0},
+			categories: map[string]string{"подработка": sideID},
+		},
+		{
+			name: "negative posting sum keeps the account",
+			journal: `account Assets:Bank:карта
+    ; id: ` + cardID + `
+
+2024-03-02 * Магазин
+    Expenses:Еда  300
+    Assets:Bank:карта
+`,
+			accounts:   map[string]int64{cardID: -300},
+			operations: 1,
+		},
+		{
+			name: "opening posting and income",
+			journal: `account Income:Зарплата
+    ; id: ` + salaryID + `
+
+2024-03-01 * Opening Balance
+    Assets:Bank:тбанк  1000
+    Equity:Opening Balances
+
+2024-03-02 * Аванс
+    Income:Зарплата  -500
+    Assets:Bank:тбанк  500
+`,
+			accounts:   map[string]int64{statementID("journal\x1fassets:bank:тбанк", 1): 1500},
+			categories: map[string]string{"Зарплата": salaryID},
+			operations: 1,
+		},
+		{
+			name: "unbalanced transaction",
+			journal: `2024-03-02 * Ошибка
+    Expenses:Еда  300
+    Assets:Bank:карта  -200
+`,
+			wantErr: "line 1: transaction does not balance",
+		},
+		{
+			name: "three postings",
+			journal: `2024-03-02 * Ошибка
+    Expenses:Еда  100
+    Expenses:Кафе  100
+    Assets:Bank:карта
+`,
+			wantErr: "line 1: expected exactly two postings",
+		},
+		{
+			name: "expense refund",
+			journal: `2024-03-01 * Магазин
+    Expenses:Еда  300
+    Assets:Bank:карта
+
+2024-03-05 * Возврат
+    Expenses:Еда  -40
+    Assets:Bank:карта  40
+`,
+			wantErr: "line 5: expense posting",
+		},
+		{
+			name: "income reversal",
+			journal: `2024-03-02 * Сторно
+    Assets:Bank:карта  -500
+    Income:Зарплата
+`,
+			wantErr: "line 1: income posting",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := NewJournalImporter().Parse(strings.NewReader(tc.journal))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			if len(payload.Accounts) != len(tc.accounts) {
+				t.Fatalf("accounts = %+v, want %v", payload.Accounts, tc.accounts)
+			}
+			for _, account := range payload.Accounts {
+				if balance, ok := tc.accounts[account.ID]; !ok || balance != account.Balance {
+					t.Errorf("account %s = %d, want %d (known %v)", account.ID, account.Balance, balance, ok)
+				}
+			}
+			if len(payload.Categories) != len(tc.categories) {
+				t.Fatalf("categories = %+v, want %v", payload.Categories, tc.categories)
+			}
+			for _, category := range payload.Categories {
+				if id, ok := tc.categories[category.Name]; !ok || id != category.ID {
+					t.Errorf("category %q id = %q, want %q", category.Name, category.ID, id)
+				}
+			}
+			if len(payload.Operations) != tc.operations {
+				t.Errorf("operations = %d, want %d", len(payload.Operations), tc.operations)
+			}
+		})
+	}
+}
@@ -2,6 +2,10 @@ package fileimport
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 
 	appfiles "kpo-hw-2/internal/application/files"
 	fileimport "kpo-hw-2/internal/application/files/import"
@@ -23,17 +27,82 @@ func (i *JSONImporter) Format() appfiles.Format {
 	}
 }
 
-func (i *JSONImporter) Parse(data []byte) (filesmodel.Payload, error) {
-	if len(data) == 0 {
-		return filesmodel.Payload{}, nil
+func (i *JSONImporter) Parse(reader io.Reader) (filesmodel.Payload, error) {
+	return fileimport.Collect(func(visitor fileimport.RecordVisitor) error {
+		return i.Stream(reader, visitor)
+	})
+}
+
+func (i *JSONImporter) Stream(reader io.Reader, visitor fileimport.RecordVisitor) error {
+	decoder := json.NewDecoder(reader)
+
+	token, err := decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("json: expected object, got %v", token)
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+
+		switch strings.ToLower(key) {
+		case "accounts":
+			err = decodeJSONArray(decoder, visitor.VisitAccount)
+		case "categories":
+			err = decodeJSONArray(decoder, visitor.VisitCategory)
+		case "operations":
+			err = decodeJSONArray(decoder, visitor.VisitOperation)
+		case "views":
+			err = decodeJSONArray(decoder, visitor.VisitView)
+		default:
+			var skipped json.RawMessage
+			err = decoder.Decode(&skipped)
+		}
+		if err != nil {
+			return fmt.Errorf("json: %s: %w", key, err)
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+func decodeJSONArray[T any](decoder *json.Decoder, visit func(T) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array, got %v", token)
 	}
 
-	var payload filesmodel.Payload
-	if err := json.Unmarshal(data, &payload); err != nil {
-		return filesmodel.Payload{}, err
+	for decoder.More() {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return err
+		}
+		if err := visit(item); err != nil {
+			return err
+		}
 	}
 
-	return payload, nil
+	_, err = decoder.Token()
+	return err
 }
 
-var _ fileimport.Importer = (*JSONImporter)(nil)
+var _ fileimport.StreamImporter = (*JSONImporter)(nil)
@@ -0,0 +1,394 @@
+package fileimport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	appfiles "kpo-hw-2/internal/application/files"
+	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+type OFXImporter struct{}
+
+func NewOFXImporter() *OFXImporter {
+	return &OFXImporter{}
+}
+
+func (i *OFXImporter) Format() appfiles.Format {
+	return appfiles.Format{
+		Key:         "ofx",
+		Title:       "OFX/QFX",
+		Description: "Импорт операций из выписки в формате OFX 1.x/2.x.",
+		Extension:   "ofx",
+	}
+}
+
+func (i *OFXImporter) Parse(reader io.Reader) (filesmodel.Payload, error) {
+	return fileimport.Collect(func(visitor fileimport.RecordVisitor) error {
+		return i.Stream(reader, visitor)
+	})
+}
+
+func (i *OFXImporter) Stream(reader io.Reader, visitor fileimport.RecordVisitor) error {
+	buffered := bufio.NewReaderSize(reader, sniffSize)
+	head, complete, err := peekHead(buffered)
+	if err != nil {
+		return err
+	}
+	if complete && len(bytes.TrimSpace(head)) == 0 {
+		return nil
+	}
+
+	decoded, err := decodeReader(buffered, ofxEncoding(head))
+	if err != nil {
+		return fmt.Errorf("ofx: %w", err)
+	}
+
+	statements := 0
+	err = streamOFX(decoded, func(node *ofxNode, parents []*ofxNode) (bool, error) {
+		switch node.name {
+		case "STMTTRN":
+			statement := ofxStatement(parents)
+			if statement == nil {
+				return false, nil
+			}
+			account, err := ofxAccount(statement)
+			if err != nil {
+				return false, err
+			}
+			op, err := ofxOperation(account, node)
+			if err != nil {
+				return false, err
+			}
+			return true, visitor.VisitOperation(op)
+		case "STMTRS", "CCSTMTRS":
+			account, err := ofxAccount(node)
+			if err != nil {
+				return false, err
+			}
+			statements++
+			return true, visitor.VisitAccount(account)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if statements == 0 {
+		return fmt.Errorf("ofx: no bank or credit card statements found")
+	}
+	return nil
+}
+
+var _ fileimport.StreamImporter = (*OFXImporter)(nil)
+
+func ofxStatement(parents []*ofxNode) *ofxNode {
+	for i := len(parents) - 1; i >= 0; i-- {
+		if parents[i].name == "STMTRS" || parents[i].name == "CCSTMTRS" {
+			return parents[i]
+		}
+	}
+	return nil
+}
+
+func ofxAccount(statement *ofxNode) (filesmodel.Account, error) {
+	from := statement.find("BANKACCTFROM")
+	if from == nil {
+		from = statement.find("CCACCTFROM")
+	}
+	if from == nil {
+		return filesmodel.Account{}, fmt.Errorf("ofx: statement has no account")
+	}
+
+	accountID := from.value("ACCTID")
+	if accountID == "" {
+		return filesmodel.Account{}, fmt.Errorf("ofx: account has no ACCTID")
+	}
+	bankID := from.value("BANKID")
+
+	var balance int64
+	var rounded bool
+	if ledger := statement.find("LEDGERBAL"); ledger != nil {
+		if amount := ledger.value("BALAMT"); amount != "" {
+			parsed, fractional, err := parseLocaleAmount(amount, ".", "", 1)
+			if err != nil {
+				return filesmodel.Account{}, fmt.Errorf("ofx: account %s balance: %w", accountID, err)
+			}
+			balance, rounded = parsed, fractional
+		}
+	}
+
+	name := "Счёт " + accountID
+	if kind := ofxAccountKind(from.value("ACCTTYPE"), from.name); kind != "" {
+		name = kind + " " + accountID
+	}
+
+	return filesmodel.Account{
+		ID:      statementID(strings.Join([]string{"ofx", bankID, accountID}, "\x1f"), 1),
+		Name:    name,
+		Balance: balance,
+		Rounded: rounded,
+		Kind:    string(ofxBalanceKind(from.value("ACCTTYPE"), from.name)),
+	}, nil
+}
+
+func ofxAccountKind(acctType, aggregate string) string {
+	if aggregate == "CCACCTFROM" {
+		return "Кредитная карта"
+	}
+	switch strings.ToUpper(acctType) {
+	case "CHECKING":
+		return "Текущий счёт"
+	case "SAVINGS":
+		return "Сберегательный счёт"
+	case "CREDITLINE":
+		return "Кредитная линия"
+	case "MONEYMRKT":
+		return "Счёт денежного рынка"
+	default:
+		return ""
+	}
+}
+
+func ofxBalanceKind(acctType, aggregate string) domain.AccountKind {
+	if aggregate == "CCACCTFROM" || strings.EqualFold(acctType, "CREDITLINE") {
+		return domain.AccountKindCredit
+	}
+	return domain.AccountKindAsset
+}
+
+func ofxOperation(account filesmodel.Account, trn *ofxNode) (filesmodel.Operation, error) {
+	fitID := trn.value("FITID")
+	if fitID == "" {
+		return filesmodel.Operation{}, fmt.Errorf("ofx: transaction without FITID")
+	}
+
+	date, err := parseOFXDate(trn.value("DTPOSTED"))
+	if err != nil {
+		return filesmodel.Operation{}, fmt.Errorf("ofx: transaction %s date: %w", fitID, err)
+	}
+
+	amount, rounded, err := parseLocaleAmount(trn.value("TRNAMT"), ".", "", 1)
+	if err != nil {
+		return filesmodel.Operation{}, fmt.Errorf("ofx: transaction %s amount: %w", fitID, err)
+	}
+
+	typ := domain.OperationTypeIncome
+	switch {
+	case amount < 0:
+		typ = domain.OperationTypeExpense
+		amount = -amount
+	case amount == 0 && strings.EqualFold(trn.value("TRNTYPE"), "DEBIT"):
+		typ = domain.OperationTypeExpense
+	}
+
+	category := defaultIncomeCategory
+	if typ == domain.OperationTypeExpense {
+		category = defaultExpenseCategory
+	}
+
+	return filesmodel.Operation{
+		ID:            statementID(strings.Join([]string{"ofx", account.ID, fitID}, "\x1f"), 1),
+		Type:          string(typ),
+		BankAccountID: account.ID,
+		CategoryName:  category,
+		Amount:        amount,
+		Date:          date,
+		Description:   ofxDescription(trn.value("NAME"), trn.value("MEMO")),
+		Rounded:       rounded,
+	}, nil
+}
+
+func ofxDescription(name, memo string) string {
+	switch {
+	case name == "":
+		return memo
+	case memo == "" || strings.EqualFold(name, memo):
+		return name
+	default:
+		return name + " — " + memo
+	}
+}
+
+func parseOFXDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	location := time.UTC
+	if start := strings.IndexByte(value, '['); start >= 0 {
+		zone := strings.TrimSuffix(value[start+1:], "]")
+		value = value[:start]
+		offset, name, _ := strings.Cut(zone, ":")
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone %q", zone)
+		}
+		if name == "" {
+			name = "UTC" + offset
+		}
+		location = time.FixedZone(name, int(hours*3600))
+	}
+	if dot := strings.IndexByte(value, '.'); dot >= 0 {
+		value = value[:dot]
+	}
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.ParseInLocation(layout, value, location)
+}
+
+func ofxEncoding(head []byte) string {
+	header := strings.ToUpper(string(head[:min(len(head), 512)]))
+	if strings.Contains(header, "CHARSET:1251") ||
+		strings.Contains(header, `ENCODING="WINDOWS-1251"`) ||
+		strings.Contains(header, `ENCODING='WINDOWS-1251'`) {
+		return EncodingWindows1251
+	}
+	return EncodingAuto
+}
+
+type ofxNode struct {
+	name     string
+	text     string
+	children []*ofxNode
+}
+
+func streamOFX(reader *bufio.Reader, closed func(node *ofxNode, parents []*ofxNode) (bool, error)) error {
+	root := &ofxNode{}
+	stack := []*ofxNode{root}
+	started := false
+	var pending *ofxNode
+
+	pop := func(depth int) error {
+		for len(stack) > depth {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			drop, err := closed(node, stack)
+			if err != nil {
+				return err
+			}
+			if drop {
+				stack[len(stack)-1].detach(node)
+			}
+		}
+		return nil
+	}
+
+	for {
+		text, err := reader.ReadString('<')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("ofx: %w", err)
+		}
+		if pending != nil {
+			if value := strings.TrimSpace(strings.TrimSuffix(text, "<")); value != "" {
+				pending.text = html.UnescapeString(value)
+			} else {
+				stack = append(stack, pending)
+			}
+			pending = nil
+		}
+		if err != nil {
+			break
+		}
+
+		tag, err := reader.ReadString('>')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("ofx: unterminated tag")
+			}
+			return fmt.Errorf("ofx: %w", err)
+		}
+		tag = strings.TrimSpace(strings.TrimSuffix(tag, ">"))
+
+		if tag == "" || tag[0] == '?' || tag[0] == '!' {
+			continue
+		}
+
+		if tag[0] == '/' {
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					if err := pop(i); err != nil {
+						return err
+					}
+					break
+				}
+			}
+			continue
+		}
+
+		selfClosing := strings.HasSuffix(tag, "/")
+		fields := strings.Fields(strings.TrimSuffix(tag, "/"))
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToUpper(fields[0])
+		if !started {
+			if name != "OFX" {
+				continue
+			}
+			started = true
+		}
+
+		node := &ofxNode{name: name}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+		if !selfClosing {
+			pending = node
+		}
+	}
+
+	if !started {
+		return fmt.Errorf("ofx: missing <OFX> element")
+	}
+	return pop(1)
+}
+
+func (n *ofxNode) detach(child *ofxNode) {
+	for i := len(n.children) - 1; i >= 0; i-- {
+		if n.children[i] == child {
+			copy(n.children[i:], n.children[i+1:])
+			n.children[len(n.children)-1] = nil
+			n.children = n.children[:len(n.children)-1]
+			return
+		}
+	}
+}
+
+func (n *ofxNode) find(name string) *ofxNode {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+		if found := child.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (n *ofxNode) value(name string) string {
+	for _, child := range n.children {
+		if child.name == name {
+			return child.text
+		}
+	}
+	return ""
+}
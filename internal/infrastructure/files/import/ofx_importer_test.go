@@ -0,0 +1,153 @@
+package fileimport
+
+import (
+	"strings"
+	"testing"
+
+	appimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+)
+
+const ofxFixture = `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<BANKACCTFROM>
+<BANKID>044525225
+<ACCTID>40817810000000001234
+<ACCTTYPE>CHECKING
+</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240301100000[+3:MSK]
+<TRNAMT>85000.00
+<FITID>1
+<NAME>Salary
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240302
+<TRNAMT>-1249.50
+<FITID>2
+<NAME>Grocery store
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL>
+<BALAMT>83430.50
+<DTASOF>20240305
+</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+func TestOFXImporterParse(t *testing.T) {
+	payload, err := NewOFXImporter().Parse(strings.NewReader(ofxFixture))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if len(payload.Accounts) != 1 {
+		t.Fatalf("accounts = %d, want 1", len(payload.Accounts))
+	}
+	account := payload.Accounts[0]
+	if account.Balance != 83431 || !account.Rounded {
+		t.Fatalf("account balance = %d (rounded %v), want 83431 rounded", account.Balance, account.Rounded)
+	}
+
+	cases := []struct {
+		typ     string
+		amount  int64
+		rounded bool
+		name    string
+	}{
+		{typ: "income", amount: 85000, rounded: false, name: "Salary"},
+		{typ: "expense", amount: 1250, rounded: true, name: "Grocery store"},
+	}
+	if len(payload.Operations) != len(cases) {
+		t.Fatalf("operations = %d, want %d", len(payload.Operations), len(cases))
+	}
+	for i, tc := range cases {
+		op := payload.Operations[i]
+		if op.Type != tc.typ || op.Amount != tc.amount || op.Rounded != tc.rounded || op.Description != tc.name {
+			t.Errorf("operation %d = %s %d rounded=%v %q, want %s %d rounded=%v %q",
+				i, op.Type, op.Amount, op.Rounded, op.Description, tc.typ, tc.amount, tc.rounded, tc.name)
+		}
+		if op.BankAccountID != account.ID {
+			t.Errorf("operation %d account = %s, want %s", i, op.BankAccountID, account.ID)
+		}
+	}
+}
+
+func TestOFXImporterRejectsMalformed(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{name: "no ofx element", data: "<FOO>bar</FOO>"},
+		{name: "no statements", data: "<OFX><SIGNONMSGSRSV1></SIGNONMSGSRSV1></OFX>"},
+		{name: "transaction without fitid", data: "<OFX><STMTRS><BANKACCTFROM><ACCTID>1</BANKACCTFROM><BANKTRANLIST><STMTTRN><DTPOSTED>20240301<TRNAMT>1</STMTTRN></BANKTRANLIST></STMTRS></OFX>"},
+		{name: "unterminated tag", data: "<OFX><BANKMSGSRSV1"},
+		{name: "bad amount", data: "<OFX><STMTRS><BANKACCTFROM><ACCTID>1</BANKACCTFROM><BANKTRANLIST><STMTTRN><FITID>1<DTPOSTED>20240301<TRNAMT>abc</STMTTRN></BANKTRANLIST></STMTRS></OFX>"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewOFXImporter().Parse(strings.NewReader(tc.data)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestOFXNegativeLedgerBalanceDependsOnAccountKind(t *testing.T) {
+	statement := func(aggregate, from string) string {
+		return "<OFX><CREDITCARDMSGSRSV1><" + aggregate + "><" + from + "><ACCTID>5555</" + from + ">" +
+			"<BANKTRANLIST><STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240302<TRNAMT>-1500.00<FITID>1<NAME>Shop</STMTTRN></BANKTRANLIST>" +
+			"<LEDGERBAL><BALAMT>-1500.00<DTASOF>20240305</LEDGERBAL></" + aggregate + "></CREDITCARDMSGSRSV1></OFX>"
+	}
+
+	cases := []struct {
+		name      string
+		data      string
+		kind      domain.AccountKind
+		account   appimport.Outcome
+		operation appimport.Outcome
+	}{
+		{name: "card statement", data: statement("CCSTMTRS", "CCACCTFROM"), kind: domain.AccountKindCredit,
+			account: appimport.OutcomeCreate, operation: appimport.OutcomeCreate},
+		{name: "bank statement", data: statement("STMTRS", "BANKACCTFROM"), kind: domain.AccountKindAsset,
+			account: appimport.OutcomeInvalid, operation: appimport.OutcomeMissingReference},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := NewOFXImporter().Parse(strings.NewReader(tc.data))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(payload.Accounts) != 1 || payload.Accounts[0].Kind != string(tc.kind) {
+				t.Fatalf("accounts = %+v, want one %s account", payload.Accounts, tc.kind)
+			}
+
+			store := newRoundTripStore(nil, []appimport.Importer{NewOFXImporter()})
+			options := appimport.DefaultOptions()
+			result, err := store.imports.Import("ofx", strings.NewReader(tc.data), options)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			outcomes := make(map[appimport.Entity]appimport.Outcome)
+			for _, record := range result.Records {
+				outcomes[record.Entity] = record.Outcome
+			}
+			if outcomes[appimport.EntityAccount] != tc.account || outcomes[appimport.EntityOperation] != tc.operation {
+				t.Fatalf("outcomes = %v, want account %s, operation %s", outcomes, tc.account, tc.operation)
+			}
+		})
+	}
+}
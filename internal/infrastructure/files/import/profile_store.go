@@ -0,0 +1,90 @@
+package fileimport
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	fileimport "kpo-hw-2/internal/application/files/import"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+type ProfileStore struct {
+	dir string
+}
+
+func NewProfileStore(dir string) *ProfileStore {
+	return &ProfileStore{dir: dir}
+}
+
+func (s *ProfileStore) List() ([]filesmodel.StatementProfile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	profiles := make([]filesmodel.StatementProfile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		profile, err := s.load(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return strings.ToLower(profiles[i].Name) < strings.ToLower(profiles[j].Name)
+	})
+	return profiles, nil
+}
+
+func (s *ProfileStore) Get(name string) (filesmodel.StatementProfile, error) {
+	profiles, err := s.List()
+	if err != nil {
+		return filesmodel.StatementProfile{}, err
+	}
+
+	name = strings.TrimSpace(name)
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, nil
+		}
+	}
+	return filesmodel.StatementProfile{}, fileimport.ErrUnknownProfile
+}
+
+func (s *ProfileStore) load(path string) (filesmodel.StatementProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return filesmodel.StatementProfile{}, err
+	}
+
+	var profile filesmodel.StatementProfile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return filesmodel.StatementProfile{}, fmt.Errorf("profile %s: %w", filepath.Base(path), err)
+	}
+	if strings.TrimSpace(profile.Name) == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return profile, nil
+}
+
+var _ fileimport.ProfileStore = (*ProfileStore)(nil)
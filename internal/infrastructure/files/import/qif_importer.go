@@ -0,0 +1,437 @@
+package fileimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	appfiles "kpo-hw-2/internal/application/files"
+	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+const (
+	qifDefaultAccount   = "Счёт QIF"
+	qifTransferCategory = "Переводы"
+	qifOpeningBalance   = "opening balance"
+)
+
+type QIFImporter struct{}
+
+func NewQIFImporter() *QIFImporter {
+	return &QIFImporter{}
+}
+
+func (i *QIFImporter) Format() appfiles.Format {
+	return appfiles.Format{
+		Key:         "qif",
+		Title:       "QIF",
+		Description: "Импорт счетов и операций из файла Quicken Interchange Format.",
+		Extension:   "qif",
+	}
+}
+
+func (i *QIFImporter) Parse(reader io.Reader) (filesmodel.Payload, error) {
+	decoded, err := decodeReader(reader, EncodingAuto)
+	if err != nil {
+		return filesmodel.Payload{}, fmt.Errorf("qif: %w", err)
+	}
+
+	records, err := readQIFRecords(decoded)
+	if err != nil {
+		return filesmodel.Payload{}, err
+	}
+	dayFirst := qifDayFirst(records)
+
+	builder := newQIFBuilder()
+	for _, record := range records {
+		switch {
+		case record.section == "account":
+			if name := record.first('N'); name != "" {
+				builder.current = name
+				builder.kind(builder.account(), record.first('T'))
+			}
+		case record.section == "cat":
+			builder.category(record)
+		case isQIFTransactionSection(record.section):
+			if err := builder.transaction(record, dayFirst); err != nil {
+				return filesmodel.Payload{}, fmt.Errorf("qif: line %d: %w", record.line, err)
+			}
+		}
+	}
+
+	return builder.payload(), nil
+}
+
+var _ fileimport.Importer = (*QIFImporter)(nil)
+
+type qifField struct {
+	code  byte
+	value string
+}
+
+type qifRecord struct {
+	section string
+	line    int
+	fields  []qifField
+}
+
+func (r qifRecord) first(code byte) string {
+	for _, field := range r.fields {
+		if field.code == code {
+			return field.value
+		}
+	}
+	return ""
+}
+
+func readQIFRecords(reader io.Reader) ([]qifRecord, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var records []qifRecord
+	section := ""
+	current := qifRecord{}
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if text[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(text[1:]))
+			switch {
+			case header == "account":
+				section = "account"
+			case strings.HasPrefix(header, "type:"):
+				section = strings.TrimSpace(strings.TrimPrefix(header, "type:"))
+			case strings.HasPrefix(header, "option:"), strings.HasPrefix(header, "clear:"):
+				continue
+			default:
+				section = header
+			}
+			current = qifRecord{}
+			continue
+		}
+
+		if text[0] == '^' {
+			if len(current.fields) > 0 {
+				current.section = section
+				records = append(records, current)
+			}
+			current = qifRecord{}
+			continue
+		}
+
+		if len(current.fields) == 0 {
+			current.line = line
+		}
+		current.fields = append(current.fields, qifField{code: text[0], value: strings.TrimSpace(text[1:])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("qif: %w", err)
+	}
+	if len(current.fields) > 0 {
+		current.section = section
+		records = append(records, current)
+	}
+
+	return records, nil
+}
+
+func isQIFTransactionSection(section string) bool {
+	switch section {
+	case "bank", "cash", "ccard", "oth a", "oth l":
+		return true
+	default:
+		return false
+	}
+}
+
+type qifSplit struct {
+	category string
+	memo     string
+	amount   string
+}
+
+type qifBuilder struct {
+	current     string
+	accounts    []string
+	balances    map[string]int64
+	rounded     map[string]bool
+	credit      map[string]bool
+	categories  []filesmodel.Category
+	operations  []filesmodel.Operation
+	occurrences map[string]int
+}
+
+func newQIFBuilder() *qifBuilder {
+	return &qifBuilder{
+		balances:    make(map[string]int64),
+		rounded:     make(map[string]bool),
+		credit:      make(map[string]bool),
+		occurrences: make(map[string]int),
+	}
+}
+
+func (b *qifBuilder) kind(account, section string) {
+	switch strings.ToLower(strings.TrimSpace(section)) {
+	case "ccard", "oth l":
+		b.credit[account] = true
+	}
+}
+
+func (b *qifBuilder) account() string {
+	name := b.current
+	if name == "" {
+		name = qifDefaultAccount
+	}
+	if _, ok := b.balances[name]; !ok {
+		b.balances[name] = 0
+		b.accounts = append(b.accounts, name)
+	}
+	return name
+}
+
+func (b *qifBuilder) category(record qifRecord) {
+	name := record.first('N')
+	if qifTransfer(name) != "" {
+		return
+	}
+	if slash := strings.IndexByte(name, '/'); slash >= 0 {
+		name = name[:slash]
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return
+	}
+
+	typ := domain.OperationTypeExpense
+	for _, field := range record.fields {
+		if field.code == 'I' {
+			typ = domain.OperationTypeIncome
+		}
+	}
+	b.categories = append(b.categories, filesmodel.Category{Type: string(typ), Name: name})
+}
+
+func (b *qifBuilder) transaction(record qifRecord, dayFirst bool) error {
+	payee := record.first('P')
+	category := record.first('L')
+	opening := strings.EqualFold(payee, qifOpeningBalance) && qifTransfer(category) != ""
+	if opening && b.current == "" {
+		b.current = qifTransfer(category)
+	}
+	account := b.account()
+	b.kind(account, record.section)
+
+	date, err := parseQIFDate(record.first('D'), dayFirst)
+	if err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+
+	total := record.first('T')
+	if total == "" {
+		total = record.first('U')
+	}
+	memo := record.first('M')
+
+	var splits []qifSplit
+	for _, field := range record.fields {
+		switch field.code {
+		case 'S':
+			splits = append(splits, qifSplit{category: field.value})
+		case 'E':
+			if len(splits) > 0 {
+				splits[len(splits)-1].memo = field.value
+			}
+		case '$':
+			if len(splits) > 0 {
+				splits[len(splits)-1].amount = field.value
+			}
+		}
+	}
+	if len(splits) == 0 {
+		splits = []qifSplit{{category: category, memo: memo, amount: total}}
+	}
+
+	if opening && len(splits) == 1 {
+		amount, rounded, err := parseLocaleAmount(total, "", "", 1)
+		if err != nil {
+			return fmt.Errorf("amount: %w", err)
+		}
+		b.balances[account] += amount
+		b.rounded[account] = b.rounded[account] || rounded
+		return nil
+	}
+
+	for _, split := range splits {
+		amount, rounded, err := parseLocaleAmount(split.amount, "", "", 1)
+		if err != nil {
+			return fmt.Errorf("amount: %w", err)
+		}
+		b.balances[account] += amount
+
+		typ := domain.OperationTypeIncome
+		if amount < 0 {
+			typ = domain.OperationTypeExpense
+			amount = -amount
+		}
+
+		op := filesmodel.Operation{
+			Type:          string(typ),
+			BankAccountID: qifAccountID(account),
+			CategoryName:  qifCategory(split.category, typ),
+			Amount:        amount,
+			Date:          date,
+			Description:   joinQIFText(payee, split.memo),
+			Rounded:       rounded,
+		}
+
+		key := strings.Join([]string{
+			"qif",
+			account,
+			op.Type,
+			op.Date.Format(time.RFC3339),
+			strconv.FormatInt(op.Amount, 10),
+			op.CategoryName,
+			op.Description,
+		}, "\x1f")
+		b.occurrences[key]++
+		op.ID = statementID(key, b.occurrences[key])
+		b.operations = append(b.operations, op)
+	}
+
+	return nil
+}
+
+func (b *qifBuilder) payload() filesmodel.Payload {
+	var payload filesmodel.Payload
+	for _, name := range b.accounts {
+		account := filesmodel.Account{
+			ID:      qifAccountID(name),
+			Name:    name,
+			Balance: b.balances[name],
+			Rounded: b.rounded[name],
+		}
+		if b.credit[name] {
+			account.Kind = string(domain.AccountKindCredit)
+		}
+		payload.Accounts = append(payload.Accounts, account)
+	}
+	payload.Categories = b.categories
+	payload.Operations = b.operations
+	return payload
+}
+
+func qifAccountID(name string) string {
+	return statementID("qif\x1f"+strings.ToLower(name), 1)
+}
+
+func qifTransfer(category string) string {
+	if strings.HasPrefix(category, "[") {
+		if end := strings.IndexByte(category, ']'); end > 0 {
+			return category[1:end]
+		}
+	}
+	return ""
+}
+
+func qifCategory(category string, typ domain.OperationType) string {
+	if qifTransfer(category) != "" {
+		return qifTransferCategory
+	}
+	if slash := strings.IndexByte(category, '/'); slash >= 0 {
+		category = category[:slash]
+	}
+	if category = strings.TrimSpace(category); category != "" {
+		return category
+	}
+	if typ == domain.OperationTypeIncome {
+		return defaultIncomeCategory
+	}
+	return defaultExpenseCategory
+}
+
+func joinQIFText(payee, memo string) string {
+	switch {
+	case payee == "":
+		return memo
+	case memo == "" || strings.EqualFold(payee, memo):
+		return payee
+	default:
+		return payee + " — " + memo
+	}
+}
+
+func qifDayFirst(records []qifRecord) bool {
+	for _, record := range records {
+		if !isQIFTransactionSection(record.section) {
+			continue
+		}
+		parts := qifDateParts(record.first('D'))
+		if len(parts) != 3 || len(parts[0]) == 4 {
+			continue
+		}
+		if first, err := strconv.Atoi(parts[0]); err == nil && first > 12 {
+			return true
+		}
+	}
+	return false
+}
+
+func qifDateParts(value string) []string {
+	return strings.FieldsFunc(strings.ReplaceAll(value, " ", ""), func(r rune) bool {
+		return r == '/' || r == '\'' || r == '-' || r == '.'
+	})
+}
+
+func parseQIFDate(value string, dayFirst bool) (time.Time, error) {
+	parts := qifDateParts(value)
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+
+	numbers := make([]int, 3)
+	for idx, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		numbers[idx] = number
+	}
+
+	var year, month, day int
+	switch {
+	case len(parts[0]) == 4:
+		year, month, day = numbers[0], numbers[1], numbers[2]
+	case dayFirst || strings.Contains(value, "."):
+		day, month, year = numbers[0], numbers[1], numbers[2]
+	default:
+		month, day, year = numbers[0], numbers[1], numbers[2]
+	}
+
+	if len(parts[2]) <= 2 && len(parts[0]) != 4 {
+		switch {
+		case strings.Contains(value, "'"), year < 70:
+			year += 2000
+		default:
+			year += 1900
+		}
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,149 @@
+package fileimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const qifFixture = `!Type:Cat
+NЗарплата
+I
+^
+NЕда
+E
+^
+!Account
+NКарта
+TBank
+^
+!Type:Bank
+D03/01'26
+T1000.00
+POpening Balance
+L[Карта]
+^
+D03/02'26
+T-350.40
+PМагазин
+MПродукты
+LЕда/Дом
+^
+D03/03'26
+T-500
+PГипермаркет
+SЕда
+EОвощи
+$-300
+SБыт
+$-200
+^
+D03/04'26
+T-100
+PПеревод
+L[Наличные]
+^
+`
+
+func TestQIFImporterParse(t *testing.T) {
+	payload, err := NewQIFImporter().Parse(strings.NewReader(qifFixture))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if len(payload.Accounts) != 1 {
+		t.Fatalf("accounts = %d, want 1", len(payload.Accounts))
+	}
+	account := payload.Accounts[0]
+	if account.Name != "Карта" || account.Balance != 1000-350-500-100 || account.Rounded {
+		t.Fatalf("account = %q %d (rounded %v), want Карта %d", account.Name, account.Balance, account.Rounded, 1000-350-500-100)
+	}
+
+	if len(payload.Categories) != 2 || payload.Categories[0].Type != "income" || payload.Categories[1].Type != "expense" {
+		t.Fatalf("categories = %+v, want income Зарплата and expense Еда", payload.Categories)
+	}
+
+	tests := []struct {
+		typ         string
+		amount      int64
+		category    string
+		description string
+		day         int
+		rounded     bool
+	}{
+		{typ: "expense", amount: 350, category: "Еда", description: "Магазин — Продукты", day: 2, rounded: true},
+		{typ: "expense", amount: 300, category: "Еда", description: "Гипермаркет — Овощи", day: 3},
+		{typ: "expense", amount: 200, category: "Быт", description: "Гипермаркет", day: 3},
+		{typ: "expense", amount: 100, category: qifTransferCategory, description: "Перевод", day: 4},
+	}
+	if len(payload.Operations) != len(tests) {
+		t.Fatalf("operations = %d, want %d", len(payload.Operations), len(tests))
+	}
+	for i, want := range tests {
+		got := payload.Operations[i]
+		if got.Type != want.typ || got.Amount != want.amount || got.CategoryName != want.category ||
+			got.Description != want.description || got.BankAccountID != account.ID || got.Rounded != want.rounded {
+			t.Errorf("operation %d = %s %d %q %q, want %s %d %q %q", i,
+				got.Type, got.Amount, got.CategoryName, got.Description, want.typ, want.amount, want.category, want.description)
+		}
+		if want := time.Date(2026, time.March, want.day, 0, 0, 0, 0, time.UTC); !got.Date.Equal(want) {
+			t.Errorf("operation %d date = %s, want %s", i, got.Date, want)
+		}
+	}
+}
+
+func TestParseQIFDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		dayFirst bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{value: "03/14/2026", want: time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{value: "14/03/2026", dayFirst: true, want: time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{value: "3/4'26", want: time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)},
+		{value: "3/4/98", want: time.Date(1998, time.March, 4, 0, 0, 0, 0, time.UTC)},
+		{value: "14.03.2026", want: time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-03-14", want: time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{value: "02/30/2026", wantErr: true},
+		{value: "13/01/2026", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseQIFDate(tt.value, tt.dayFirst)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseQIFDate(%q) = %s, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQIFDate(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("parseQIFDate(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQIFImporterRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "bad date", data: "!Type:Bank\nDсегодня\nT-1\n^\n"},
+		{name: "bad amount", data: "!Type:Bank\nD03/01/2026\nTмного\n^\n"},
+		{name: "bad split amount", data: "!Type:Bank\nD03/01/2026\nT-1\nSЕда\n$x\n^\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewQIFImporter().Parse(strings.NewReader(tt.data)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
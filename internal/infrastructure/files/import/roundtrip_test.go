@@ -0,0 +1,428 @@
+package fileimport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/application/facade"
+	appexport "kpo-hw-2/internal/application/files/export"
+	appimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	domainfactory "kpo-hw-2/internal/domain/factory"
+	fileexport "kpo-hw-2/internal/infrastructure/files/export"
+	"kpo-hw-2/internal/infrastructure/id"
+	"kpo-hw-2/internal/infrastructure/repository/memory"
+)
+
+type roundTripStore struct {
+	accounts   facade.AccountFacade
+	categories facade.CategoryFacade
+	operations facade.OperationFacade
+	exports    *appexport.Service
+	imports    *appimport.Service
+}
+
+func newRoundTripStore(exporters []appexport.Exporter, importers []appimport.Importer) *roundTripStore {
+	ids := id.NewULIDGenerator()
+	accountRepo := memory.NewAccountRepository()
+	categoryRepo := memory.NewCategoryRepository()
+	operationRepo := memory.NewOperationRepository()
+	profileRepo := memory.NewAccountProfileRepository()
+	viewRepo := memory.NewSavedViewRepository()
+
+	accounts := facade.NewAccountFacade(domainfactory.NewBankAccountFactory(ids), accountRepo, profileRepo)
+	categories := facade.NewCategoryFacade(domainfactory.NewCategoryFactory(ids), categoryRepo)
+	operations := facade.NewOperationFacade(
+		domainfactory.NewOperationFactory(ids),
+		operationRepo,
+		accountRepo,
+		categoryRepo,
+		profileRepo,
+		memory.NewOperationAggregateRepository(),
+	)
+	views := facade.NewSavedViewFacade(domainfactory.NewSavedViewFactory(ids), viewRepo, accountRepo, categoryRepo)
+
+	return &roundTripStore{
+		accounts:   accounts,
+		categories: categories,
+		operations: operations,
+		exports:    appexport.NewService(accountRepo, profileRepo, categoryRepo, operationRepo, viewRepo, exporters),
+		imports:    appimport.NewService(accounts, categories, operations, views, ids, memory.NewImportBatchRepository(), nil, importers),
+	}
+}
+
+func seedRoundTripStore(t *testing.T, store *roundTripStore) {
+	t.Helper()
+
+	tbank, err := store.accounts.CreateAccount("тбанк")
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	vtb, err := store.accounts.CreateAccount("втб")
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if _, err := store.accounts.UpdateAccount(vtb.ID(), vtb.Name(), 551); err != nil {
+		t.Fatalf("update account: %v", err)
+	}
+	if _, err := store.accounts.CreateAccount("сбер"); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	salary, err := store.categories.CreateCategory("зарплата", domain.OperationTypeIncome)
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	food, err := store.categories.CreateCategory("еда", domain.OperationTypeExpense)
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	if _, err := store.categories.CreateCategory("подарки", domain.OperationTypeIncome); err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	day := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	operations := []struct {
+		typ      domain.OperationType
+		account  domain.ID
+		category domain.ID
+		amount   int64
+		offset   int
+	}{
+		{domain.OperationTypeIncome, tbank.ID(), salary.ID(), 50000, 0},
+		{domain.OperationTypeExpense, tbank.ID(), food.ID(), 8000, 1},
+		{domain.OperationTypeExpense, vtb.ID(), food.ID(), 199, 2},
+	}
+	for _, op := range operations {
+		if _, err := store.operations.CreateOperation(op.typ, op.account, op.category, op.amount, day.AddDate(0, 0, op.offset), ""); err != nil {
+			t.Fatalf("create operation: %v", err)
+		}
+	}
+}
+
+func TestOpeningBalanceRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		exporter appexport.Exporter
+		importer appimport.Importer
+	}{
+		{name: "json", exporter: fileexport.NewJSONExporter(), importer: NewJSONImporter()},
+		{name: "yaml", exporter: fileexport.NewYAMLExporter(), importer: NewYAMLImporter()},
+		{name: "csv", exporter: fileexport.NewCSVExporter(), importer: NewCSVImporter()},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := newRoundTripStore([]appexport.Exporter{tc.exporter}, nil)
+			seedRoundTripStore(t, source)
+
+			var buf bytes.Buffer
+			format := tc.exporter.Format().Key
+			if err := source.exports.Export(format, &buf, appexport.DefaultOptions()); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+
+			target := newRoundTripStore(nil, []appimport.Importer{tc.importer})
+			options := appimport.DefaultOptions()
+			options.Mode = appimport.ModeOpeningBalance
+			result, err := target.imports.Import(format, bytes.NewReader(buf.Bytes()), options)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+
+			for _, check := range result.Balances {
+				if check.Mismatch() {
+					t.Errorf("%s: balance %d, stated %d", check.Name, check.Balance, check.Stated)
+				}
+			}
+			assertSameAccounts(t, source.accounts, target.accounts)
+		})
+	}
+}
+
+func assertSameAccounts(t *testing.T, want, got facade.AccountFacade) {
+	t.Helper()
+
+	expected, err := want.ListAccounts()
+	if err != nil {
+		t.Fatalf("list accounts: %v", err)
+	}
+	for _, account := range expected {
+		imported, err := got.GetAccount(account.ID())
+		if err != nil {
+			t.Errorf("%s: %v", account.Name(), err)
+			continue
+		}
+		if imported.Name() != account.Name() || imported.Balance() != account.Balance() {
+			t.Errorf("account %s = %q/%d, want %q/%d",
+				account.ID(), imported.Name(), imported.Balance(), account.Name(), account.Balance())
+		}
+	}
+}
+
+func TestQIFRoundTripKeepsAccountsAndCategories(t *testing.T) {
+	for _, mode := range appimport.Modes() {
+		t.Run(string(mode), func(t *testing.T) {
+			source := newRoundTripStore([]appexport.Exporter{fileexport.NewQIFExporter()}, nil)
+			seedRoundTripStore(t, source)
+
+			var buf bytes.Buffer
+			if err := source.exports.Export("qif", &buf, appexport.DefaultOptions()); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+
+			target := newRoundTripStore(nil, []appimport.Importer{NewQIFImporter()})
+			options := appimport.DefaultOptions()
+			options.Mode = mode
+			result, err := target.imports.Import("qif", bytes.NewReader(buf.Bytes()), options)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			for _, check := range result.Balances {
+				if check.Mismatch() {
+					t.Errorf("%s: balance %d, stated %d", check.Name, check.Balance, check.Stated)
+				}
+			}
+
+			assertSameByName(t, source, target)
+		})
+	}
+}
+
+func assertSameByName(t *testing.T, want, got *roundTripStore) {
+	t.Helper()
+
+	balances := func(store *roundTripStore) map[string]int64 {
+		accounts, err := store.accounts.ListAccounts()
+		if err != nil {
+			t.Fatalf("list accounts: %v", err)
+		}
+		out := make(map[string]int64, len(accounts))
+		for _, account := range accounts {
+			out[account.Name()] = account.Balance()
+		}
+		return out
+	}
+	categories := func(store *roundTripStore) map[string]domain.OperationType {
+		list, err := store.categories.ListCategories("")
+		if err != nil {
+			t.Fatalf("list categories: %v", err)
+		}
+		out := make(map[string]domain.OperationType, len(list))
+		for _, category := range list {
+			out[category.Name()] = category.Type()
+		}
+		return out
+	}
+
+	gotBalances := balances(got)
+	for name, balance := range balances(want) {
+		if imported, ok := gotBalances[name]; !ok || imported != balance {
+			t.Errorf("account %q = %d (present %v), want %d", name, imported, ok, balance)
+		}
+	}
+	gotCategories := categories(got)
+	for name, typ := range categories(want) {
+		if imported, ok := gotCategories[name]; !ok || imported != typ {
+			t.Errorf("category %q = %q (present %v), want %q", name, imported, ok, typ)
+		}
+	}
+}
+
+func TestJournalRoundTripKeepsDeclarations(t *testing.T) {
+	cases := []struct {
+		name     string
+		exporter appexport.Exporter
+		mode     appimport.Mode
+	}{
+		{name: "ledger snapshot", exporter: fileexport.NewLedgerExporter(), mode: appimport.ModeSnapshot},
+		{name: "ledger opening balance", exporter: fileexport.NewLedgerExporter(), mode: appimport.ModeOpeningBalance},
+		{name: "hledger opening balance", exporter: fileexport.NewHledgerExporter(), mode: appimport.ModeOpeningBalance},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := newRoundTripStore([]appexport.Exporter{tc.exporter}, nil)
+			seedRoundTripStore(t, source)
+
+			var buf bytes.Buffer
+			if err := source.exports.Export(tc.exporter.Format().Key, &buf, appexport.DefaultOptions()); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+
+			target := newRoundTripStore(nil, []appimport.Importer{NewJournalImporter()})
+			options := appimport.DefaultOptions()
+			options.Mode = tc.mode
+			result, err := target.imports.Import("ledger", bytes.NewReader(buf.Bytes()), options)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			for _, check := range result.Balances {
+				if check.Mismatch() {
+					t.Errorf("%s: balance %d, stated %d", check.Name, check.Balance, check.Stated)
+				}
+			}
+
+			assertSameAccounts(t, source.accounts, target.accounts)
+			assertSameByName(t, source, target)
+		})
+	}
+}
+
+func TestCreditAccountRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		exporter appexport.Exporter
+		importer appimport.Importer
+	}{
+		{name: "json", exporter: fileexport.NewJSONExporter(), importer: NewJSONImporter()},
+		{name: "yaml", exporter: fileexport.NewYAMLExporter(), importer: NewYAMLImporter()},
+		{name: "csv", exporter: fileexport.NewCSVExporter(), importer: NewCSVImporter()},
+	}
+
+	for _, tc := range cases {
+		for _, mode := range appimport.Modes() {
+			t.Run(tc.name+"/"+string(mode), func(t *testing.T) {
+				source := newRoundTripStore([]appexport.Exporter{tc.exporter}, nil)
+				card, err := source.accounts.CreateAccount("кредитка")
+				if err != nil {
+					t.Fatalf("create account: %v", err)
+				}
+				if _, err := source.accounts.SetAccountProfile(card.ID(), domain.AccountKindCredit, "Карты"); err != nil {
+					t.Fatalf("set profile: %v", err)
+				}
+				food, err := source.categories.CreateCategory("еда", domain.OperationTypeExpense)
+				if err != nil {
+					t.Fatalf("create category: %v", err)
+				}
+				day := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+				if _, err := source.operations.CreateOperation(domain.OperationTypeExpense, card.ID(), food.ID(), 500, day, ""); err != nil {
+					t.Fatalf("create operation: %v", err)
+				}
+
+				var buf bytes.Buffer
+				format := tc.exporter.Format().Key
+				if err := source.exports.Export(format, &buf, appexport.DefaultOptions()); err != nil {
+					t.Fatalf("export: %v", err)
+				}
+
+				target := newRoundTripStore(nil, []appimport.Importer{tc.importer})
+				options := appimport.DefaultOptions()
+				options.Mode = mode
+				result, err := target.imports.Import(format, bytes.NewReader(buf.Bytes()), options)
+				if err != nil {
+					t.Fatalf("import: %v", err)
+				}
+				if result.CreatedAccounts != 1 || result.CreatedOperations != 1 {
+					t.Fatalf("created %d accounts, %d operations; want 1, 1", result.CreatedAccounts, result.CreatedOperations)
+				}
+
+				assertSameAccounts(t, source.accounts, target.accounts)
+				profile, err := target.accounts.GetAccountProfile(card.ID())
+				if err != nil {
+					t.Fatalf("profile: %v", err)
+				}
+				if profile.Kind() != domain.AccountKindCredit || profile.Group() != "Карты" {
+					t.Errorf("profile = %s/%q, want credit/Карты", profile.Kind(), profile.Group())
+				}
+			})
+		}
+	}
+}
+
+func TestQIFRoundTripKeepsCreditAccounts(t *testing.T) {
+	for _, mode := range appimport.Modes() {
+		t.Run(string(mode), func(t *testing.T) {
+			source := newRoundTripStore([]appexport.Exporter{fileexport.NewQIFExporter()}, nil)
+			card, err := source.accounts.CreateAccount("кредитка")
+			if err != nil {
+				t.Fatalf("create account: %v", err)
+			}
+			if _, err := source.accounts.SetAccountProfile(card.ID(), domain.AccountKindCredit, ""); err != nil {
+				t.Fatalf("set profile: %v", err)
+			}
+			food, err := source.categories.CreateCategory("еда", domain.OperationTypeExpense)
+			if err != nil {
+				t.Fatalf("create category: %v", err)
+			}
+			day := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+			if _, err := source.operations.CreateOperation(domain.OperationTypeExpense, card.ID(), food.ID(), 500, day, ""); err != nil {
+				t.Fatalf("create operation: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := source.exports.Export("qif", &buf, appexport.DefaultOptions()); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			if !bytes.Contains(buf.Bytes(), []byte("!Type:CCard")) {
+				t.Fatalf("export has no CCard section:\n%s", buf.String())
+			}
+
+			target := newRoundTripStore(nil, []appimport.Importer{NewQIFImporter()})
+			options := appimport.DefaultOptions()
+			options.Mode = mode
+			result, err := target.imports.Import("qif", bytes.NewReader(buf.Bytes()), options)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if result.CreatedAccounts != 1 || result.CreatedOperations != 1 {
+				t.Fatalf("created %d accounts, %d operations; want 1, 1", result.CreatedAccounts, result.CreatedOperations)
+			}
+			assertSameByName(t, source, target)
+
+			profiles, err := target.accounts.ListAccountProfiles()
+			if err != nil {
+				t.Fatalf("profiles: %v", err)
+			}
+			if len(profiles) != 1 || profiles[0].Kind() != domain.AccountKindCredit {
+				t.Errorf("profiles = %v, want one credit profile", profiles)
+			}
+		})
+	}
+}
+
+func TestQIFExportsOperationsWithoutAccounts(t *testing.T) {
+	source := newRoundTripStore([]appexport.Exporter{fileexport.NewQIFExporter()}, nil)
+	seedRoundTripStore(t, source)
+
+	var buf bytes.Buffer
+	options := appexport.Options{Entities: appexport.Entities{Operations: true}}
+	if err := source.exports.Export("qif", &buf, options); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	payload, err := NewQIFImporter().Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(payload.Operations) != 3 {
+		t.Fatalf("operations = %d, want 3:\n%s", len(payload.Operations), buf.String())
+	}
+	if len(payload.Accounts) != 2 {
+		t.Errorf("accounts = %+v, want the two accounts with operations", payload.Accounts)
+	}
+}
+
+func TestJournalExportsOperationsWithoutAccounts(t *testing.T) {
+	source := newRoundTripStore([]appexport.Exporter{fileexport.NewLedgerExporter()}, nil)
+	seedRoundTripStore(t, source)
+
+	var buf bytes.Buffer
+	options := appexport.Options{Entities: appexport.Entities{Operations: true}}
+	if err := source.exports.Export("ledger", &buf, options); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	payload, err := NewJournalImporter().Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(payload.Operations) != 3 {
+		t.Fatalf("operations = %d, want 3:\n%s", len(payload.Operations), buf.String())
+	}
+	for _, op := range payload.Operations {
+		if _, err := source.accounts.GetAccount(domain.ID(op.BankAccountID)); err != nil {
+			t.Errorf("operation %s account %s: %v", op.ID, op.BankAccountID, err)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package fileimport
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	appfiles "kpo-hw-2/internal/application/files"
+	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+const (
+	SignNegativeExpense = "negative_expense"
+	SignPositiveExpense = "positive_expense"
+	SignTypeColumn      = "type_column"
+
+	defaultIncomeCategory  = "Прочие доходы"
+	defaultExpenseCategory = "Прочие расходы"
+)
+
+type StatementImporter struct{}
+
+func NewStatementImporter() *StatementImporter {
+	return &StatementImporter{}
+}
+
+func (i *StatementImporter) Format() appfiles.Format {
+	return appfiles.Format{
+		Key:         "statement",
+		Title:       "Банковская выписка (CSV)",
+		Description: "Импорт операций из выписки банка по выбранному профилю.",
+		Extension:   "csv",
+	}
+}
+
+func (i *StatementImporter) Parse(io.Reader) (filesmodel.Payload, error) {
+	return filesmodel.Payload{}, fileimport.ErrProfileRequired
+}
+
+func (i *StatementImporter) ParseWithProfile(source io.Reader, profile filesmodel.StatementProfile) (filesmodel.Payload, error) {
+	decoded, err := decodeReader(source, profile.Encoding)
+	if err != nil {
+		return filesmodel.Payload{}, fmt.Errorf("statement: %w", err)
+	}
+
+	mapping, err := newStatementMapping(profile, decoded)
+	if err != nil {
+		return filesmodel.Payload{}, err
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.ReuseRecord = true
+	reader.Comma = mapping.delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var payload filesmodel.Payload
+	occurrences := make(map[string]int)
+	line := 0
+	headerRead := !profile.Header
+
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return filesmodel.Payload{}, err
+		}
+
+		line++
+		if line <= profile.SkipRows {
+			continue
+		}
+
+		for idx := range record {
+			record[idx] = strings.TrimSpace(record[idx])
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		if !headerRead {
+			if err := mapping.bindHeader(record); err != nil {
+				return filesmodel.Payload{}, err
+			}
+			headerRead = true
+			continue
+		}
+		if !mapping.bound {
+			if err := mapping.bindHeader(nil); err != nil {
+				return filesmodel.Payload{}, err
+			}
+		}
+
+		op, err := mapping.operation(record)
+		if err != nil {
+			return filesmodel.Payload{}, fmt.Errorf("statement: line %d: %w", line, err)
+		}
+
+		key := statementKey(profile.Name, op)
+		occurrences[key]++
+		op.ID = statementID(key, occurrences[key])
+		payload.Operations = append(payload.Operations, op)
+	}
+
+	return payload, nil
+}
+
+var _ fileimport.ProfileImporter = (*StatementImporter)(nil)
+
+type statementMapping struct {
+	profile   filesmodel.StatementProfile
+	delimiter rune
+	layout    string
+	sign      string
+	scale     int64
+	income    map[string]struct{}
+
+	bound       bool
+	date        int
+	amount      int
+	description int
+	category    int
+	account     int
+	typ         int
+}
+
+func newStatementMapping(profile filesmodel.StatementProfile, reader *bufio.Reader) (*statementMapping, error) {
+	delimiter, err := resolveDelimiter(profile.Delimiter, reader)
+	if err != nil {
+		return nil, fmt.Errorf("statement: profile %q: %w", profile.Name, err)
+	}
+
+	mapping := &statementMapping{
+		profile:   profile,
+		delimiter: delimiter,
+		layout:    profile.DateLayout,
+		sign:      strings.ToLower(strings.TrimSpace(profile.Sign)),
+		scale:     profile.Scale,
+		income:    make(map[string]struct{}),
+	}
+
+	if mapping.sign == "" {
+		mapping.sign = SignNegativeExpense
+	}
+	switch mapping.sign {
+	case SignNegativeExpense, SignPositiveExpense, SignTypeColumn:
+	default:
+		return nil, fmt.Errorf("statement: profile %q: unknown sign convention %q", profile.Name, profile.Sign)
+	}
+	if mapping.scale <= 0 {
+		mapping.scale = 1
+	}
+	for _, value := range profile.IncomeValues {
+		mapping.income[strings.ToLower(strings.TrimSpace(value))] = struct{}{}
+	}
+
+	return mapping, nil
+}
+
+func (m *statementMapping) bindHeader(header []string) error {
+	columns := m.profile.Columns
+	var err error
+	if m.date, err = columnIndex(columns.Date, header); err != nil {
+		return fmt.Errorf("statement: date column: %w", err)
+	}
+	if m.amount, err = columnIndex(columns.Amount, header); err != nil {
+		return fmt.Errorf("statement: amount column: %w", err)
+	}
+	if m.date < 0 || m.amount < 0 {
+		return fmt.Errorf("statement: profile %q must map date and amount columns", m.profile.Name)
+	}
+	if m.description, err = columnIndex(columns.Description, header); err != nil {
+		return fmt.Errorf("statement: description column: %w", err)
+	}
+	if m.category, err = columnIndex(columns.Category, header); err != nil {
+		return fmt.Errorf("statement: category column: %w", err)
+	}
+	if m.account, err = columnIndex(columns.Account, header); err != nil {
+		return fmt.Errorf("statement: account column: %w", err)
+	}
+	if m.typ, err = columnIndex(columns.Type, header); err != nil {
+		return fmt.Errorf("statement: type column: %w", err)
+	}
+	if m.sign == SignTypeColumn && m.typ < 0 {
+		return fmt.Errorf("statement: profile %q uses type_column without a type column", m.profile.Name)
+	}
+	m.bound = true
+	return nil
+}
+
+func (m *statementMapping) operation(record []string) (filesmodel.Operation, error) {
+	date, err := parseLocaleDate(recordValue(record, m.date), m.layout)
+	if err != nil {
+		return filesmodel.Operation{}, fmt.Errorf("date: %w", err)
+	}
+
+	amount, rounded, err := parseLocaleAmount(
+		recordValue(record, m.amount),
+		m.profile.DecimalSeparator,
+		m.profile.ThousandsSeparator,
+		m.scale,
+	)
+	if err != nil {
+		return filesmodel.Operation{}, fmt.Errorf("amount: %w", err)
+	}
+
+	typ := domain.OperationTypeIncome
+	switch m.sign {
+	case SignNegativeExpense:
+		if amount < 0 {
+			typ = domain.OperationTypeExpense
+		}
+	case SignPositiveExpense:
+		if amount > 0 {
+			typ = domain.OperationTypeExpense
+		}
+	case SignTypeColumn:
+		if _, ok := m.income[strings.ToLower(recordValue(record, m.typ))]; !ok {
+			typ = domain.OperationTypeExpense
+		}
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+
+	account := recordValue(record, m.account)
+	if account == "" {
+		account = strings.TrimSpace(m.profile.Account)
+	}
+	if account == "" {
+		return filesmodel.Operation{}, fmt.Errorf("account: profile %q has no default account", m.profile.Name)
+	}
+
+	category := recordValue(record, m.category)
+	if category == "" {
+		category = m.defaultCategory(typ)
+	}
+
+	return filesmodel.Operation{
+		Type:         string(typ),
+		AccountName:  account,
+		CategoryName: category,
+		Amount:       amount,
+		Date:         date,
+		Description:  recordValue(record, m.description),
+		Rounded:      rounded,
+	}, nil
+}
+
+func (m *statementMapping) defaultCategory(typ domain.OperationType) string {
+	if typ == domain.OperationTypeIncome {
+		if name := strings.TrimSpace(m.profile.IncomeCategory); name != "" {
+			return name
+		}
+		return defaultIncomeCategory
+	}
+	if name := strings.TrimSpace(m.profile.ExpenseCategory); name != "" {
+		return name
+	}
+	return defaultExpenseCategory
+}
+
+func columnIndex(spec string, header []string) (int, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return -1, nil
+	}
+	if number, err := strconv.Atoi(spec); err == nil {
+		if number < 1 {
+			return -1, fmt.Errorf("invalid column number %d", number)
+		}
+		return number - 1, nil
+	}
+	for idx, name := range header {
+		if strings.EqualFold(name, spec) {
+			return idx, nil
+		}
+	}
+	return -1, fmt.Errorf("column %q not found in header", spec)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func statementKey(profile string, op filesmodel.Operation) string {
+	return strings.Join([]string{
+		profile,
+		op.AccountName,
+		op.Type,
+		op.Date.Format(time.RFC3339),
+		strconv.FormatInt(op.Amount, 10),
+		op.Description,
+	}, "\x1f")
+}
+
+func statementID(key string, occurrence int) string {
+	sum := sha256.Sum256([]byte(key + "\x1f" + strconv.Itoa(occurrence)))
+	value := new(big.Int).SetBytes(sum[:17])
+
+	var b strings.Builder
+	base := big.NewInt(int64(len(domain.ULIDAlphabet)))
+	digit := new(big.Int)
+	for i := 0; i < 26; i++ {
+		value.DivMod(value, base, digit)
+		b.WriteByte(domain.ULIDAlphabet[digit.Int64()])
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+package fileimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	filesmodel "kpo-hw-2/internal/files/model"
+)
+
+func TestStatementImporterParseWithProfile(t *testing.T) {
+	type operation struct {
+		typ      string
+		amount   int64
+		account  string
+		category string
+		date     time.Time
+	}
+
+	tests := []struct {
+		name    string
+		profile filesmodel.StatementProfile
+		data    string
+		want    []operation
+	}{
+		{
+			name: "header columns and negative expense",
+			profile: filesmodel.StatementProfile{
+				Name:    "tbank",
+				Header:  true,
+				Account: "Карта",
+				Columns: filesmodel.StatementColumns{Date: "Дата", Amount: "Сумма", Description: "Описание", Category: "Категория"},
+			},
+			data: "Дата;Сумма;Описание;Категория\n01.03.2026;-1 250,50;Кафе;Рестораны\n02.03.2026;50 000,00;Зарплата;\n",
+			want: []operation{
+				{typ: "expense", amount: 1251, account: "Карта", category: "Рестораны", date: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+				{typ: "income", amount: 50000, account: "Карта", category: defaultIncomeCategory, date: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "numbered columns, skipped rows and positive expense",
+			profile: filesmodel.StatementProfile{
+				Name:            "card",
+				SkipRows:        2,
+				Sign:            SignPositiveExpense,
+				DateLayout:      "2006/01/02",
+				ExpenseCategory: "Покупки",
+				Columns:         filesmodel.StatementColumns{Date: "1", Amount: "2", Account: "3"},
+			},
+			data: "Выписка по карте\nПериод: март\n2026/03/05,300,Кредитка\n2026/03/06,-100,Кредитка\n",
+			want: []operation{
+				{typ: "expense", amount: 300, account: "Кредитка", category: "Покупки", date: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+				{typ: "income", amount: 100, account: "Кредитка", category: defaultIncomeCategory, date: time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "type column and scale",
+			profile: filesmodel.StatementProfile{
+				Name:         "sber",
+				Header:       true,
+				Delimiter:    "\t",
+				Account:      "Сбер",
+				Sign:         SignTypeColumn,
+				IncomeValues: []string{"Зачисление"},
+				Scale:        100,
+				Columns:      filesmodel.StatementColumns{Date: "date", Amount: "sum", Type: "kind"},
+			},
+			data: "date\tsum\tkind\n2026-03-07\t12.34\tСписание\n2026-03-08\t1\tзачисление\n",
+			want: []operation{
+				{typ: "expense", amount: 1234, account: "Сбер", category: defaultExpenseCategory, date: time.Date(2026, time.March, 7, 0, 0, 0, 0, time.UTC)},
+				{typ: "income", amount: 100, account: "Сбер", category: defaultIncomeCategory, date: time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := NewStatementImporter().ParseWithProfile(strings.NewReader(tt.data), tt.profile)
+			if err != nil {
+				t.Fatalf("ParseWithProfile: %v", err)
+			}
+			if len(payload.Operations) != len(tt.want) {
+				t.Fatalf("operations = %d, want %d", len(payload.Operations), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := payload.Operations[i]
+				if got.Type != want.typ || got.Amount != want.amount || got.AccountName != want.account ||
+					got.CategoryName != want.category || !got.Date.Equal(want.date) {
+					t.Errorf("operation %d = %s %d %q/%q %s, want %s %d %q/%q %s", i,
+						got.Type, got.Amount, got.AccountName, got.CategoryName, got.Date.Format(time.DateOnly),
+						want.typ, want.amount, want.account, want.category, want.date.Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
+
+func TestStatementImporterIDsAreStable(t *testing.T) {
+	profile := filesmodel.StatementProfile{
+		Name:    "tbank",
+		Account: "Карта",
+		Columns: filesmodel.StatementColumns{Date: "1", Amount: "2", Description: "3"},
+	}
+	data := "01.03.2026,-100,Метро\n01.03.2026,-100,Метро\n"
+
+	first, err := NewStatementImporter().ParseWithProfile(strings.NewReader(data), profile)
+	if err != nil {
+		t.Fatalf("ParseWithProfile: %v", err)
+	}
+	second, err := NewStatementImporter().ParseWithProfile(strings.NewReader(data), profile)
+	if err != nil {
+		t.Fatalf("ParseWithProfile: %v", err)
+	}
+
+	if first.Operations[0].ID == first.Operations[1].ID {
+		t.Fatalf("identical rows share ID %s", first.Operations[0].ID)
+	}
+	for i := range first.Operations {
+		if first.Operations[i].ID != second.Operations[i].ID {
+			t.Fatalf("operation %d ID = %s on re-import, want %s", i, second.Operations[i].ID, first.Operations[i].ID)
+		}
+	}
+}
+
+func TestStatementImporterRejectsInvalidProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile filesmodel.StatementProfile
+		data    string
+	}{
+		{
+			name:    "unknown sign",
+			profile: filesmodel.StatementProfile{Name: "p", Account: "A", Sign: "inverted", Columns: filesmodel.StatementColumns{Date: "1", Amount: "2"}},
+			data:    "01.03.2026,1\n",
+		},
+		{
+			name:    "missing amount column",
+			profile: filesmodel.StatementProfile{Name: "p", Account: "A", Columns: filesmodel.StatementColumns{Date: "1"}},
+			data:    "01.03.2026,1\n",
+		},
+		{
+			name:    "column not in header",
+			profile: filesmodel.StatementProfile{Name: "p", Account: "A", Header: true, Columns: filesmodel.StatementColumns{Date: "date", Amount: "amount"}},
+			data:    "date,sum\n01.03.2026,1\n",
+		},
+		{
+			name:    "type column without type",
+			profile: filesmodel.StatementProfile{Name: "p", Account: "A", Sign: SignTypeColumn, Columns: filesmodel.StatementColumns{Date: "1", Amount: "2"}},
+			data:    "01.03.2026,1\n",
+		},
+		{
+			name:    "no account",
+			profile: filesmodel.StatementProfile{Name: "p", Columns: filesmodel.StatementColumns{Date: "1", Amount: "2"}},
+			data:    "01.03.2026,1\n",
+		},
+		{
+			name:    "bad amount",
+			profile: filesmodel.StatementProfile{Name: "p", Account: "A", Columns: filesmodel.StatementColumns{Date: "1", Amount: "2"}},
+			data:    "01.03.2026,abc\n",
+		},
+		{
+			name:    "bad date",
+			profile: filesmodel.StatementProfile{Name: "p", Account: "A", Columns: filesmodel.StatementColumns{Date: "1", Amount: "2"}},
+			data:    "в понедельник,1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewStatementImporter().ParseWithProfile(strings.NewReader(tt.data), tt.profile); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package fileimport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	appexport "kpo-hw-2/internal/application/files/export"
+	appimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	filesmodel "kpo-hw-2/internal/files/model"
+	fileexport "kpo-hw-2/internal/infrastructure/files/export"
+)
+
+type countingVisitor struct {
+	accounts, categories, operations, views int
+	failAt                                  int
+}
+
+var errVisitorStop = errors.New("visitor stop")
+
+func (v *countingVisitor) VisitAccount(filesmodel.Account) error {
+	v.accounts++
+	return nil
+}
+
+func (v *countingVisitor) VisitCategory(filesmodel.Category) error {
+	v.categories++
+	return nil
+}
+
+func (v *countingVisitor) VisitOperation(filesmodel.Operation) error {
+	v.operations++
+	if v.failAt > 0 && v.operations == v.failAt {
+		return errVisitorStop
+	}
+	return nil
+}
+
+func (v *countingVisitor) VisitView(filesmodel.SavedView) error {
+	v.views++
+	return nil
+}
+
+func syntheticOFX(operations int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("OFXHEADER:100\nDATA:OFXSGML\n\n<OFX>\n<BANKMSGSRSV1>\n<STMTTRNRS>\n<STMTRS>\n")
+	buf.WriteString("<BANKACCTFROM>\n<BANKID>044525225\n<ACCTID>40817810000000001234\n<ACCTTYPE>CHECKING\n</BANKACCTFROM>\n")
+	buf.WriteString("<BANKTRANLIST>\n")
+	day := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < operations; i++ {
+		amount := "-12.50"
+		kind := "DEBIT"
+		if i%10 == 0 {
+			amount, kind = "150.00", "CREDIT"
+		}
+		fmt.Fprintf(&buf, "<STMTTRN>\n<TRNTYPE>%s\n<DTPOSTED>%s\n<TRNAMT>%s\n<FITID>%d\n<NAME>Shop %d\n</STMTTRN>\n",
+			kind, day.AddDate(0, 0, i/100).Format("20060102"), amount, i, i%50)
+	}
+	buf.WriteString("</BANKTRANLIST>\n<LEDGERBAL>\n<BALAMT>1000.00\n<DTASOF>20250101\n</LEDGERBAL>\n</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+	return buf.Bytes()
+}
+
+func syntheticStore(tb testing.TB, exporter appexport.Exporter, operations int) *roundTripStore {
+	tb.Helper()
+
+	store := newRoundTripStore([]appexport.Exporter{exporter}, nil)
+	account, err := store.accounts.CreateAccount("тбанк")
+	if err != nil {
+		tb.Fatalf("create account: %v", err)
+	}
+	salary, err := store.categories.CreateCategory("зарплата", domain.OperationTypeIncome)
+	if err != nil {
+		tb.Fatalf("create category: %v", err)
+	}
+	food, err := store.categories.CreateCategory("еда", domain.OperationTypeExpense)
+	if err != nil {
+		tb.Fatalf("create category: %v", err)
+	}
+
+	day := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < operations; i++ {
+		typ, category, amount := domain.OperationTypeExpense, food.ID(), int64(1250)
+		if i%10 == 0 {
+			typ, category, amount = domain.OperationTypeIncome, salary.ID(), 15000
+		}
+		if _, err := store.operations.CreateOperation(typ, account.ID(), category, amount, day.AddDate(0, 0, i/100), ""); err != nil {
+			tb.Fatalf("create operation: %v", err)
+		}
+	}
+	return store
+}
+
+func syntheticExport(tb testing.TB, exporter appexport.Exporter, operations int) []byte {
+	tb.Helper()
+
+	store := syntheticStore(tb, exporter, operations)
+	var buf bytes.Buffer
+	if err := store.exports.Export(exporter.Format().Key, &buf, appexport.DefaultOptions()); err != nil {
+		tb.Fatalf("export: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestStreamVisitsEveryRecord(t *testing.T) {
+	const operations = 250
+
+	cases := []struct {
+		name     string
+		importer appimport.StreamImporter
+		data     []byte
+		accounts int
+	}{
+		{name: "json", importer: NewJSONImporter(), data: syntheticExport(t, fileexport.NewJSONExporter(), operations), accounts: 1},
+		{name: "csv", importer: NewCSVImporter(), data: syntheticExport(t, fileexport.NewCSVExporter(), operations), accounts: 1},
+		{name: "ofx", importer: NewOFXImporter(), data: syntheticOFX(operations), accounts: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			visitor := &countingVisitor{}
+			if err := tc.importer.Stream(bytes.NewReader(tc.data), visitor); err != nil {
+				t.Fatalf("stream: %v", err)
+			}
+			if visitor.accounts != tc.accounts || visitor.operations != operations {
+				t.Fatalf("visited %d accounts, %d operations; want %d, %d",
+					visitor.accounts, visitor.operations, tc.accounts, operations)
+			}
+
+			payload, err := tc.importer.Parse(bytes.NewReader(tc.data))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(payload.Operations) != operations || len(payload.Accounts) != tc.accounts {
+				t.Fatalf("parse = %d accounts, %d operations", len(payload.Accounts), len(payload.Operations))
+			}
+
+			stopped := &countingVisitor{failAt: 3}
+			if err := tc.importer.Stream(bytes.NewReader(tc.data), stopped); !errors.Is(err, errVisitorStop) {
+				t.Fatalf("stream error = %v, want %v", err, errVisitorStop)
+			}
+			if stopped.operations != 3 {
+				t.Fatalf("visited %d operations after stop, want 3", stopped.operations)
+			}
+		})
+	}
+}
+
+const benchmarkOperations = 100000
+
+func TestIncrementalExportKeepsBalances(t *testing.T) {
+	const operations = 250
+
+	cases := []struct {
+		name     string
+		exporter appexport.Exporter
+		importer appimport.Importer
+	}{
+		{name: "qif", exporter: fileexport.NewQIFExporter(), importer: NewQIFImporter()},
+		{name: "ledger", exporter: fileexport.NewLedgerExporter(), importer: NewJournalImporter()},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := syntheticStore(t, tc.exporter, operations)
+			var buf bytes.Buffer
+			if err := store.exports.Export(tc.exporter.Format().Key, &buf, appexport.DefaultOptions()); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			accounts, err := store.accounts.ListAccounts()
+			if err != nil {
+				t.Fatalf("list accounts: %v", err)
+			}
+
+			payload, err := tc.importer.Parse(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(payload.Operations) != operations {
+				t.Errorf("operations = %d, want %d", len(payload.Operations), operations)
+			}
+			if len(payload.Accounts) != 1 || payload.Accounts[0].Balance != accounts[0].Balance() {
+				t.Errorf("accounts = %+v, want one with balance %d", payload.Accounts, accounts[0].Balance())
+			}
+		})
+	}
+}
+
+func BenchmarkStream(b *testing.B) {
+	cases := []struct {
+		name     string
+		importer appimport.StreamImporter
+		data     func(testing.TB) []byte
+	}{
+		{name: "json", importer: NewJSONImporter(), data: func(tb testing.TB) []byte {
+			return syntheticExport(tb, fileexport.NewJSONExporter(), benchmarkOperations)
+		}},
+		{name: "csv", importer: NewCSVImporter(), data: func(tb testing.TB) []byte {
+			return syntheticExport(tb, fileexport.NewCSVExporter(), benchmarkOperations)
+		}},
+		{name: "ofx", importer: NewOFXImporter(), data: func(testing.TB) []byte {
+			return syntheticOFX(benchmarkOperations)
+		}},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			data := tc.data(b)
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := tc.importer.Stream(bytes.NewReader(data), &countingVisitor{}); err != nil {
+					b.Fatalf("stream: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkImport(b *testing.B) {
+	cases := []struct {
+		name     string
+		exporter appexport.Exporter
+		importer appimport.Importer
+	}{
+		{name: "json", exporter: fileexport.NewJSONExporter(), importer: NewJSONImporter()},
+		{name: "csv", exporter: fileexport.NewCSVExporter(), importer: NewCSVImporter()},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			data := syntheticExport(b, tc.exporter, benchmarkOperations)
+			format := tc.exporter.Format().Key
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				target := newRoundTripStore(nil, []appimport.Importer{tc.importer})
+				b.StartTimer()
+				if _, err := target.imports.Import(format, bytes.NewReader(data), appimport.DefaultOptions()); err != nil {
+					b.Fatalf("import: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkExport(b *testing.B) {
+	cases := []struct {
+		name     string
+		exporter appexport.Exporter
+	}{
+		{name: "json", exporter: fileexport.NewJSONExporter()},
+		{name: "csv", exporter: fileexport.NewCSVExporter()},
+		{name: "qif", exporter: fileexport.NewQIFExporter()},
+		{name: "ledger", exporter: fileexport.NewLedgerExporter()},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			store := syntheticStore(b, tc.exporter, benchmarkOperations)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := store.exports.Export(tc.exporter.Format().Key, io.Discard, appexport.DefaultOptions()); err != nil {
+					b.Fatalf("export: %v", err)
+				}
+			}
+		})
+	}
+}
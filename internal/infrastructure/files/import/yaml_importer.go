@@ -1,6 +1,9 @@
 package fileimport
 
 import (
+	"errors"
+	"io"
+
 	"gopkg.in/yaml.v3"
 
 	appfiles "kpo-hw-2/internal/application/files"
@@ -23,13 +26,12 @@ func (i *YAMLImporter) Format() appfiles.Format {
 	}
 }
 
-func (i *YAMLImporter) Parse(data []byte) (filesmodel.Payload, error) {
-	if len(data) == 0 {
-		return filesmodel.Payload{}, nil
-	}
-
+func (i *YAMLImporter) Parse(reader io.Reader) (filesmodel.Payload, error) {
 	var payload filesmodel.Payload
-	if err := yaml.Unmarshal(data, &payload); err != nil {
+	if err := yaml.NewDecoder(reader).Decode(&payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			return filesmodel.Payload{}, nil
+		}
 		return filesmodel.Payload{}, err
 	}
 
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type accountProfileRepository struct {
+	mu       sync.RWMutex
+	profiles map[domain.ID]*domain.AccountProfile
+}
+
+func NewAccountProfileRepository() repository.AccountProfileRepository {
+	return &accountProfileRepository{
+		profiles: make(map[domain.ID]*domain.AccountProfile),
+	}
+}
+
+func (r *accountProfileRepository) Save(profile *domain.AccountProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[profile.AccountID()] = profile
+	return nil
+}
+
+func (r *accountProfileRepository) Delete(accountID domain.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[accountID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	delete(r.profiles, accountID)
+	return nil
+}
+
+func (r *accountProfileRepository) Get(accountID domain.ID) (*domain.AccountProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, exists := r.profiles[accountID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	return profile, nil
+}
+
+func (r *accountProfileRepository) List() ([]*domain.AccountProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profiles := make([]*domain.AccountProfile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].AccountID() < profiles[j].AccountID()
+	})
+
+	return profiles, nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type ignoredPatternRepository struct {
+	mu   sync.RWMutex
+	keys map[string]struct{}
+}
+
+func NewIgnoredPatternRepository() repository.IgnoredPatternRepository {
+	return &ignoredPatternRepository{
+		keys: make(map[string]struct{}),
+	}
+}
+
+func (r *ignoredPatternRepository) Add(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key]; exists {
+		return domain.ErrAlreadyExists
+	}
+
+	r.keys[key] = struct{}{}
+	return nil
+}
+
+func (r *ignoredPatternRepository) Remove(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key]; !exists {
+		return domain.ErrNotFound
+	}
+
+	delete(r.keys, key)
+	return nil
+}
+
+func (r *ignoredPatternRepository) List() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]string, 0, len(r.keys))
+	for key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type importBatchRepository struct {
+	mu      sync.RWMutex
+	batches map[domain.ID]repository.ImportBatch
+}
+
+func NewImportBatchRepository() repository.ImportBatchRepository {
+	return &importBatchRepository{
+		batches: make(map[domain.ID]repository.ImportBatch),
+	}
+}
+
+func (r *importBatchRepository) Save(batch repository.ImportBatch) error {
+	if batch.ID == "" {
+		return domain.ErrInvalidID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	batch.Changes = append([]repository.ImportChange(nil), batch.Changes...)
+	r.batches[batch.ID] = batch
+	return nil
+}
+
+func (r *importBatchRepository) Delete(id domain.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.batches[id]; !exists {
+		return domain.ErrNotFound
+	}
+
+	delete(r.batches, id)
+	return nil
+}
+
+func (r *importBatchRepository) Get(id domain.ID) (repository.ImportBatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	batch, exists := r.batches[id]
+	if !exists {
+		return repository.ImportBatch{}, domain.ErrNotFound
+	}
+
+	batch.Changes = append([]repository.ImportChange(nil), batch.Changes...)
+	return batch, nil
+}
+
+func (r *importBatchRepository) List() ([]repository.ImportBatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]repository.ImportBatch, 0, len(r.batches))
+	for _, batch := range r.batches {
+		batch.Changes = append([]repository.ImportChange(nil), batch.Changes...)
+		result = append(result, batch)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type aggregateKey struct {
+	account  domain.ID
+	category domain.ID
+	typ      domain.OperationType
+}
+
+type aggregateValue struct {
+	amount int64
+	count  int
+}
+
+type operationAggregateRepository struct {
+	mu    sync.RWMutex
+	days  map[int64]map[aggregateKey]*aggregateValue
+	order []int64
+}
+
+func NewOperationAggregateRepository() repository.OperationAggregateRepository {
+	return &operationAggregateRepository{
+		days: make(map[int64]map[aggregateKey]*aggregateValue),
+	}
+}
+
+func (r *operationAggregateRepository) Add(operation *domain.Operation) error {
+	if operation == nil {
+		return domain.ErrInvalidOperation
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	day := aggregateDay(operation.Date()).Unix()
+	cells, ok := r.days[day]
+	if !ok {
+		cells = make(map[aggregateKey]*aggregateValue)
+		r.days[day] = cells
+
+		pos := sort.Search(len(r.order), func(i int) bool { return r.order[i] >= day })
+		r.order = append(r.order, 0)
+		copy(r.order[pos+1:], r.order[pos:])
+		r.order[pos] = day
+	}
+
+	key := aggregateKeyOf(operation)
+	value, ok := cells[key]
+	if !ok {
+		value = &aggregateValue{}
+		cells[key] = value
+	}
+	value.amount += operation.Amount()
+	value.count++
+
+	return nil
+}
+
+func (r *operationAggregateRepository) Remove(operation *domain.Operation) error {
+	if operation == nil {
+		return domain.ErrInvalidOperation
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	day := aggregateDay(operation.Date()).Unix()
+	key := aggregateKeyOf(operation)
+	value, ok := r.days[day][key]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	value.amount -= operation.Amount()
+	value.count--
+	if value.count > 0 {
+		return nil
+	}
+
+	delete(r.days[day], key)
+	if len(r.days[day]) == 0 {
+		delete(r.days, day)
+		pos := sort.Search(len(r.order), func(i int) bool { return r.order[i] >= day })
+		r.order = append(r.order[:pos], r.order[pos+1:]...)
+	}
+
+	return nil
+}
+
+func (r *operationAggregateRepository) Rebuild(day time.Time, operations []*domain.Operation) error {
+	start := aggregateDay(day)
+	key := start.Unix()
+
+	cells := make(map[aggregateKey]*aggregateValue)
+	for _, operation := range operations {
+		if operation == nil || !aggregateDay(operation.Date()).Equal(start) {
+			continue
+		}
+		cellKey := aggregateKeyOf(operation)
+		value, ok := cells[cellKey]
+		if !ok {
+			value = &aggregateValue{}
+			cells[cellKey] = value
+		}
+		value.amount += operation.Amount()
+		value.count++
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pos := sort.Search(len(r.order), func(i int) bool { return r.order[i] >= key })
+	_, exists := r.days[key]
+	switch {
+	case len(cells) == 0 && exists:
+		delete(r.days, key)
+		r.order = append(r.order[:pos], r.order[pos+1:]...)
+	case len(cells) > 0 && !exists:
+		r.days[key] = cells
+		r.order = append(r.order, 0)
+		copy(r.order[pos+1:], r.order[pos:])
+		r.order[pos] = key
+	case len(cells) > 0:
+		r.days[key] = cells
+	}
+
+	return nil
+}
+
+func (r *operationAggregateRepository) Cells(filter query.OperationFilter) ([]repository.AggregateCell, error) {
+	if minAmount, maxAmount := filter.AmountRange(); minAmount != nil || maxAmount != nil {
+		return nil, domain.ErrUnsupportedFilter
+	}
+	if len(filter.Texts()) > 0 {
+		return nil, domain.ErrUnsupportedFilter
+	}
+
+	from, to := filter.Period()
+	if from != nil && !aggregateDay(*from).Equal(*from) {
+		return nil, domain.ErrUnsupportedFilter
+	}
+	if to != nil {
+		next := to.Add(time.Nanosecond)
+		if !aggregateDay(next).Equal(next) {
+			return nil, domain.ErrUnsupportedFilter
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	start, end := 0, len(r.order)
+	if from != nil {
+		first := from.Unix()
+		start = sort.Search(len(r.order), func(i int) bool { return r.order[i] >= first })
+	}
+	if to != nil {
+		last := aggregateDay(*to).Unix()
+		end = sort.Search(len(r.order), func(i int) bool { return r.order[i] > last })
+	}
+
+	accounts, categories, types := filter.Accounts(), filter.Categories(), filter.Types()
+
+	var cells []repository.AggregateCell
+	for _, day := range r.order[start:max(start, end)] {
+		date := time.Unix(day, 0).UTC()
+		for key, value := range r.days[day] {
+			if !accounts.Matches(key.account) || !categories.Matches(key.category) || !types.Matches(key.typ) {
+				continue
+			}
+			cells = append(cells, repository.AggregateCell{
+				AccountID:  key.account,
+				CategoryID: key.category,
+				Type:       key.typ,
+				Day:        date,
+				Amount:     value.amount,
+				Count:      value.count,
+			})
+		}
+	}
+
+	return cells, nil
+}
+
+func aggregateKeyOf(operation *domain.Operation) aggregateKey {
+	return aggregateKey{
+		account:  operation.BankAccountID(),
+		category: operation.CategoryID(),
+		typ:      operation.Type(),
+	}
+}
+
+func aggregateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+)
+
+const indexChunkSize = 64
+
+type indexKey struct {
+	sec  int64
+	nsec int32
+	id   domain.ID
+}
+
+type indexEntry struct {
+	key indexKey
+	op  *domain.Operation
+}
+
+type operationIndex struct {
+	chunks [][]indexEntry
+	size   int
+}
+
+func operationLess(a, b *domain.Operation) bool {
+	da, db := a.Date(), b.Date()
+	if da.Equal(db) {
+		return a.ID() < b.ID()
+	}
+	return da.Before(db)
+}
+
+func keyOf(op *domain.Operation) indexKey {
+	date := op.Date()
+	return indexKey{sec: date.Unix(), nsec: int32(date.Nanosecond()), id: op.ID()}
+}
+
+func timeKey(at time.Time) indexKey {
+	return indexKey{sec: at.Unix(), nsec: int32(at.Nanosecond())}
+}
+
+func (k indexKey) less(other indexKey) bool {
+	if k.sec != other.sec {
+		return k.sec < other.sec
+	}
+	if k.nsec != other.nsec {
+		return k.nsec < other.nsec
+	}
+	return k.id < other.id
+}
+
+func (k indexKey) before(at indexKey) bool {
+	return k.sec < at.sec || (k.sec == at.sec && k.nsec < at.nsec)
+}
+
+func (idx *operationIndex) len() int {
+	if idx == nil {
+		return 0
+	}
+	return idx.size
+}
+
+func (idx *operationIndex) locate(key indexKey) (int, int) {
+	c := sort.Search(len(idx.chunks), func(i int) bool {
+		chunk := idx.chunks[i]
+		return !chunk[len(chunk)-1].key.less(key)
+	})
+	if c == len(idx.chunks) {
+		return c, 0
+	}
+	chunk := idx.chunks[c]
+	pos := sort.Search(len(chunk), func(i int) bool {
+		return !chunk[i].key.less(key)
+	})
+	return c, pos
+}
+
+func (idx *operationIndex) insert(op *domain.Operation) {
+	entry := indexEntry{key: keyOf(op), op: op}
+	idx.size++
+	if len(idx.chunks) == 0 {
+		idx.chunks = append(idx.chunks, append(make([]indexEntry, 0, indexChunkSize), entry))
+		return
+	}
+
+	c, pos := idx.locate(entry.key)
+	if c == len(idx.chunks) {
+		c--
+		pos = len(idx.chunks[c])
+	}
+
+	chunk := append(idx.chunks[c], indexEntry{})
+	copy(chunk[pos+1:], chunk[pos:])
+	chunk[pos] = entry
+
+	if len(chunk) < 2*indexChunkSize {
+		idx.chunks[c] = chunk
+		return
+	}
+
+	head := append(make([]indexEntry, 0, 2*indexChunkSize), chunk[:indexChunkSize]...)
+	tail := append(make([]indexEntry, 0, 2*indexChunkSize), chunk[indexChunkSize:]...)
+	idx.chunks = append(idx.chunks, nil)
+	copy(idx.chunks[c+2:], idx.chunks[c+1:])
+	idx.chunks[c] = head
+	idx.chunks[c+1] = tail
+}
+
+func (idx *operationIndex) remove(op *domain.Operation) {
+	key := keyOf(op)
+	c, pos := idx.locate(key)
+	if c == len(idx.chunks) {
+		return
+	}
+
+	chunk := idx.chunks[c]
+	if pos >= len(chunk) || chunk[pos].key != key {
+		return
+	}
+
+	copy(chunk[pos:], chunk[pos+1:])
+	chunk[len(chunk)-1] = indexEntry{}
+	chunk = chunk[:len(chunk)-1]
+	idx.size--
+
+	if len(chunk) > 0 {
+		idx.chunks[c] = chunk
+		return
+	}
+	copy(idx.chunks[c:], idx.chunks[c+1:])
+	idx.chunks[len(idx.chunks)-1] = nil
+	idx.chunks = idx.chunks[:len(idx.chunks)-1]
+}
+
+func (idx *operationIndex) between(from, to *time.Time) ([][]indexEntry, int) {
+	if idx.len() == 0 {
+		return nil, 0
+	}
+
+	first := 0
+	var start indexKey
+	if from != nil {
+		start = timeKey(*from)
+		first = sort.Search(len(idx.chunks), func(i int) bool {
+			chunk := idx.chunks[i]
+			return !chunk[len(chunk)-1].key.before(start)
+		})
+	}
+	var end indexKey
+	if to != nil {
+		end = timeKey(*to)
+	}
+
+	var parts [][]indexEntry
+	total := 0
+	for c := first; c < len(idx.chunks); c++ {
+		chunk := idx.chunks[c]
+		lo, hi := 0, len(chunk)
+		if from != nil && c == first {
+			lo = sort.Search(len(chunk), func(i int) bool {
+				return !chunk[i].key.before(start)
+			})
+		}
+		if to != nil {
+			hi = sort.Search(len(chunk), func(i int) bool {
+				return end.before(chunk[i].key)
+			})
+		}
+		if lo < hi {
+			parts = append(parts, chunk[lo:hi])
+			total += hi - lo
+		}
+		if hi < len(chunk) {
+			break
+		}
+	}
+
+	return parts, total
+}
+
+type groupIndex map[domain.ID]*operationIndex
+
+func (g groupIndex) insert(key domain.ID, op *domain.Operation) {
+	items, ok := g[key]
+	if !ok {
+		items = &operationIndex{}
+		g[key] = items
+	}
+	items.insert(op)
+}
+
+func (g groupIndex) remove(key domain.ID, op *domain.Operation) {
+	items, ok := g[key]
+	if !ok {
+		return
+	}
+	items.remove(op)
+	if items.len() == 0 {
+		delete(g, key)
+	}
+}
+
+func (g groupIndex) between(keys []domain.ID, from, to *time.Time) ([][]indexEntry, int, int) {
+	var parts [][]indexEntry
+	total, groups := 0, 0
+	for _, key := range keys {
+		items, ok := g[key]
+		if !ok {
+			continue
+		}
+		part, size := items.between(from, to)
+		if size == 0 {
+			continue
+		}
+		parts = append(parts, part...)
+		total += size
+		groups++
+	}
+	return parts, total, groups
+}
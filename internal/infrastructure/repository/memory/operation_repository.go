@@ -3,6 +3,7 @@ package memory
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"kpo-hw-2/internal/domain"
 	"kpo-hw-2/internal/domain/query"
@@ -12,11 +13,16 @@ import (
 type operationRepository struct {
 	mu         sync.RWMutex
 	operations map[domain.ID]*domain.Operation
+	ordered    operationIndex
+	byAccount  groupIndex
+	byCategory groupIndex
 }
 
 func NewOperationRepository() repository.OperationRepository {
 	return &operationRepository{
 		operations: make(map[domain.ID]*domain.Operation),
+		byAccount:  make(groupIndex),
+		byCategory: make(groupIndex),
 	}
 }
 
@@ -28,7 +34,7 @@ func (r *operationRepository) Create(operation *domain.Operation) error {
 		return domain.ErrAlreadyExists
 	}
 
-	r.operations[operation.ID()] = operation
+	r.index(operation)
 	return nil
 }
 
@@ -36,11 +42,13 @@ func (r *operationRepository) Update(operation *domain.Operation) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.operations[operation.ID()]; !exists {
+	existing, exists := r.operations[operation.ID()]
+	if !exists {
 		return domain.ErrNotFound
 	}
 
-	r.operations[operation.ID()] = operation
+	r.unindex(existing)
+	r.index(operation)
 	return nil
 }
 
@@ -48,11 +56,12 @@ func (r *operationRepository) Delete(id domain.ID) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.operations[id]; !exists {
+	existing, exists := r.operations[id]
+	if !exists {
 		return domain.ErrNotFound
 	}
 
-	delete(r.operations, id)
+	r.unindex(existing)
 	return nil
 }
 
@@ -70,35 +79,21 @@ func (r *operationRepository) Get(id domain.ID) (*domain.Operation, error) {
 }
 
 func (r *operationRepository) ListByFilter(filter query.OperationFilter) ([]*domain.Operation, error) {
-	r.mu.RLock()
-
-	accountID := filter.AccountID()
-	categoryID := filter.CategoryID()
-	typ := filter.Type()
 	from, to := filter.Period()
 
-	var result []*domain.Operation
-	for _, op := range r.operations {
-		if accountID != "" && op.BankAccountID() != accountID {
-			continue
-		}
-		if categoryID != "" && op.CategoryID() != categoryID {
-			continue
-		}
-		if typ != "" && op.Type() != typ {
-			continue
-		}
+	r.mu.RLock()
 
-		opDate := op.Date()
-		if from != nil && opDate.Before(*from) {
-			continue
-		}
-		if to != nil && opDate.After(*to) {
-			continue
+	parts, ordered := r.candidates(filter, from, to)
+	var result []*domain.Operation
+	for _, part := range parts {
+		for _, entry := range part {
+			if !filter.Matches(entry.op) {
+				continue
+			}
+
+			clone := *entry.op
+			result = append(result, &clone)
 		}
-
-		clone := *op
-		result = append(result, &clone)
 	}
 	r.mu.RUnlock()
 
@@ -106,14 +101,89 @@ func (r *operationRepository) ListByFilter(filter query.OperationFilter) ([]*dom
 		return nil, nil
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		di := result[i].Date()
-		dj := result[j].Date()
-		if di.Equal(dj) {
-			return result[i].ID() < result[j].ID()
+	if !ordered {
+		sort.Slice(result, func(i, j int) bool {
+			return operationLess(result[i], result[j])
+		})
+	}
+
+	return result, nil
+}
+
+func (r *operationRepository) ListPage(filter query.OperationFilter, after *domain.Operation, limit int) ([]*domain.Operation, error) {
+	if limit <= 0 {
+		return r.ListByFilter(filter)
+	}
+
+	from, to := filter.Period()
+	var cursor indexKey
+	if after != nil {
+		cursor = keyOf(after)
+		if at := after.Date(); from == nil || from.Before(at) {
+			from = &at
 		}
-		return di.Before(dj)
-	})
+	}
 
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	parts, ordered := r.candidates(filter, from, to)
+	if !ordered {
+		parts, _ = r.ordered.between(from, to)
+	}
+
+	result := make([]*domain.Operation, 0, limit)
+	for _, part := range parts {
+		for _, entry := range part {
+			if after != nil && !cursor.less(entry.key) {
+				continue
+			}
+			if !filter.Matches(entry.op) {
+				continue
+			}
+
+			clone := *entry.op
+			result = append(result, &clone)
+			if len(result) == limit {
+				return result, nil
+			}
+		}
+	}
 	return result, nil
 }
+
+func (r *operationRepository) candidates(filter query.OperationFilter, from, to *time.Time) ([][]indexEntry, bool) {
+	best, bestSize := r.ordered.between(from, to)
+	ordered := true
+
+	if accounts := filter.Accounts(); !accounts.IsEmpty() && !accounts.IsExclude() {
+		if parts, size, groups := r.byAccount.between(accounts.Values(), from, to); size < bestSize {
+			best, bestSize, ordered = parts, size, groups <= 1
+		}
+	}
+
+	if categories := filter.Categories(); !categories.IsEmpty() && !categories.IsExclude() {
+		if parts, size, groups := r.byCategory.between(categories.Values(), from, to); size < bestSize {
+			best, ordered = parts, groups <= 1
+		}
+	}
+
+	return best, ordered
+}
+
+func (r *operationRepository) index(operation *domain.Operation) {
+	stored := *operation
+	op := &stored
+
+	r.operations[op.ID()] = op
+	r.ordered.insert(op)
+	r.byAccount.insert(op.BankAccountID(), op)
+	r.byCategory.insert(op.CategoryID(), op)
+}
+
+func (r *operationRepository) unindex(op *domain.Operation) {
+	delete(r.operations, op.ID())
+	r.ordered.remove(op)
+	r.byAccount.remove(op.BankAccountID(), op)
+	r.byCategory.remove(op.CategoryID(), op)
+}
@@ -0,0 +1,355 @@
+package memory
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type linearOperationRepository struct {
+	mu         sync.RWMutex
+	operations map[domain.ID]*domain.Operation
+}
+
+func newLinearOperationRepository() *linearOperationRepository {
+	return &linearOperationRepository{operations: make(map[domain.ID]*domain.Operation)}
+}
+
+func (r *linearOperationRepository) Create(operation *domain.Operation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.operations[operation.ID()]; exists {
+		return domain.ErrAlreadyExists
+	}
+	r.operations[operation.ID()] = operation
+	return nil
+}
+
+func (r *linearOperationRepository) Update(operation *domain.Operation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.operations[operation.ID()]; !exists {
+		return domain.ErrNotFound
+	}
+	r.operations[operation.ID()] = operation
+	return nil
+}
+
+func (r *linearOperationRepository) Delete(id domain.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.operations[id]; !exists {
+		return domain.ErrNotFound
+	}
+	delete(r.operations, id)
+	return nil
+}
+
+func (r *linearOperationRepository) Get(id domain.ID) (*domain.Operation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	operation, exists := r.operations[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	clone := *operation
+	return &clone, nil
+}
+
+func (r *linearOperationRepository) ListByFilter(filter query.OperationFilter) ([]*domain.Operation, error) {
+	r.mu.RLock()
+	var result []*domain.Operation
+	for _, op := range r.operations {
+		if filter.Matches(op) {
+			clone := *op
+			result = append(result, &clone)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return operationLess(result[i], result[j])
+	})
+	return result, nil
+}
+
+func (r *linearOperationRepository) ListPage(filter query.OperationFilter, after *domain.Operation, limit int) ([]*domain.Operation, error) {
+	all, err := r.ListByFilter(filter)
+	if err != nil || limit <= 0 {
+		return all, err
+	}
+
+	var result []*domain.Operation
+	for _, op := range all {
+		if after != nil && !operationLess(after, op) {
+			continue
+		}
+		result = append(result, op)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+type operationFixture struct {
+	accounts   []domain.ID
+	categories []domain.ID
+	start      time.Time
+	random     *rand.Rand
+	next       int
+}
+
+func newOperationFixture(seed int64) *operationFixture {
+	f := &operationFixture{
+		start:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		random: rand.New(rand.NewSource(seed)),
+	}
+	for i := 0; i < 8; i++ {
+		f.accounts = append(f.accounts, domain.ID(fmt.Sprintf("ACCOUNT%02d", i)))
+	}
+	for i := 0; i < 40; i++ {
+		f.categories = append(f.categories, domain.ID(fmt.Sprintf("CATEGORY%02d", i)))
+	}
+	return f
+}
+
+func (f *operationFixture) operation(id domain.ID) *domain.Operation {
+	typ := domain.OperationTypeExpense
+	if f.random.Intn(4) == 0 {
+		typ = domain.OperationTypeIncome
+	}
+	op, err := domain.NewOperation(
+		id,
+		typ,
+		f.accounts[f.random.Intn(len(f.accounts))],
+		f.categories[f.random.Intn(len(f.categories))],
+		int64(1+f.random.Intn(10000)),
+		f.start.AddDate(0, 0, f.random.Intn(5*365)),
+		"",
+	)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+func (f *operationFixture) fresh() *domain.Operation {
+	f.next++
+	return f.operation(domain.ID(fmt.Sprintf("OP%08d", f.next)))
+}
+
+func (f *operationFixture) filters() []query.OperationFilter {
+	from := f.start.AddDate(1, 0, 0)
+	to := f.start.AddDate(2, 0, 0)
+	return []query.OperationFilter{
+		query.NewOperationFilter(),
+		query.NewOperationFilter().Between(from, to),
+		query.NewOperationFilter().From(to),
+		query.NewOperationFilter().To(from),
+		query.NewOperationFilter().ForAccounts(f.accounts[0]),
+		query.NewOperationFilter().ForAccounts(f.accounts[1], f.accounts[2]).Between(from, to),
+		query.NewOperationFilter().ForCategories(f.categories[3]),
+		query.NewOperationFilter().ForCategories(f.categories[4], f.categories[5], f.categories[6]).From(from),
+		query.NewOperationFilter().ExcludeAccounts(f.accounts[0]).OfTypes(domain.OperationTypeIncome),
+		query.NewOperationFilter().ForAccounts(f.accounts[3]).ForCategories(f.categories[7]),
+		query.NewOperationFilter().Between(to, from),
+	}
+}
+
+func TestOperationRepositoryMatchesLinearScan(t *testing.T) {
+	fixture := newOperationFixture(1)
+	indexed := NewOperationRepository()
+	linear := newLinearOperationRepository()
+
+	var ids []domain.ID
+	for step := 0; step < 5000; step++ {
+		switch roll := fixture.random.Intn(10); {
+		case roll < 6 || len(ids) == 0:
+			op := fixture.fresh()
+			ids = append(ids, op.ID())
+			mustApply(t, indexed.Create(op), linear.Create(op))
+		case roll < 8:
+			op := fixture.operation(ids[fixture.random.Intn(len(ids))])
+			mustApply(t, indexed.Update(op), linear.Update(op))
+		default:
+			pos := fixture.random.Intn(len(ids))
+			mustApply(t, indexed.Delete(ids[pos]), linear.Delete(ids[pos]))
+			ids = append(ids[:pos], ids[pos+1:]...)
+		}
+
+		if step%500 != 0 {
+			continue
+		}
+		for i, filter := range fixture.filters() {
+			compareListings(t, fmt.Sprintf("step %d filter %d", step, i), indexed, linear, filter)
+		}
+	}
+
+	for i, filter := range fixture.filters() {
+		compareListings(t, fmt.Sprintf("final filter %d", i), indexed, linear, filter)
+	}
+}
+
+func TestOperationRepositoryDeleteToEmpty(t *testing.T) {
+	fixture := newOperationFixture(2)
+	repo := NewOperationRepository()
+
+	var ops []*domain.Operation
+	for i := 0; i < 3*indexChunkSize; i++ {
+		op := fixture.fresh()
+		ops = append(ops, op)
+		if err := repo.Create(op); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	for _, op := range ops {
+		if err := repo.Delete(op.ID()); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	}
+
+	result, err := repo.ListByFilter(query.NewOperationFilter())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(result) != 0 {
+		t.Fatalf("got %d operations after deleting all", len(result))
+	}
+}
+
+func mustApply(t *testing.T, indexedErr, linearErr error) {
+	t.Helper()
+	if indexedErr != linearErr {
+		t.Fatalf("indexed error = %v, linear error = %v", indexedErr, linearErr)
+	}
+}
+
+func compareListings(t *testing.T, name string, indexed, linear repository.OperationRepository, filter query.OperationFilter) {
+	t.Helper()
+
+	got, err := indexed.ListByFilter(filter)
+	if err != nil {
+		t.Fatalf("%s: indexed list: %v", name, err)
+	}
+	want, err := linear.ListByFilter(filter)
+	if err != nil {
+		t.Fatalf("%s: linear list: %v", name, err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d operations, want %d", name, len(got), len(want))
+	}
+	for i := range got {
+		if got[i].ID() != want[i].ID() || !got[i].Date().Equal(want[i].Date()) {
+			t.Fatalf("%s: position %d: got %s, want %s", name, i, got[i].ID(), want[i].ID())
+		}
+	}
+
+	for _, limit := range []int{1, 7, indexChunkSize + 1} {
+		var paged []*domain.Operation
+		var after *domain.Operation
+		for {
+			page, err := indexed.ListPage(filter, after, limit)
+			if err != nil {
+				t.Fatalf("%s: page: %v", name, err)
+			}
+			if len(page) > limit {
+				t.Fatalf("%s: page of %d exceeds limit %d", name, len(page), limit)
+			}
+			paged = append(paged, page...)
+			if len(page) < limit {
+				break
+			}
+			after = page[len(page)-1]
+		}
+		if len(paged) != len(want) {
+			t.Fatalf("%s: paged by %d: got %d operations, want %d", name, limit, len(paged), len(want))
+		}
+		for i := range paged {
+			if paged[i].ID() != want[i].ID() {
+				t.Fatalf("%s: paged by %d: position %d: got %s, want %s", name, limit, i, paged[i].ID(), want[i].ID())
+			}
+		}
+	}
+}
+
+var benchmarkRepositories = []struct {
+	name string
+	new  func() repository.OperationRepository
+}{
+	{name: "indexed", new: NewOperationRepository},
+	{name: "linear", new: func() repository.OperationRepository { return newLinearOperationRepository() }},
+}
+
+func BenchmarkCreate(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		fixture := newOperationFixture(3)
+		ops := make([]*domain.Operation, size)
+		for i := range ops {
+			ops[i] = fixture.fresh()
+		}
+
+		for _, impl := range benchmarkRepositories {
+			b.Run(fmt.Sprintf("%s/%d", impl.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					repo := impl.new()
+					for _, op := range ops {
+						if err := repo.Create(op); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkListByFilter(b *testing.B) {
+	const size = 100000
+
+	fixture := newOperationFixture(4)
+	repos := make([]repository.OperationRepository, len(benchmarkRepositories))
+	for i, impl := range benchmarkRepositories {
+		repos[i] = impl.new()
+	}
+	for i := 0; i < size; i++ {
+		op := fixture.fresh()
+		for _, repo := range repos {
+			if err := repo.Create(op); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	month := fixture.start.AddDate(2, 0, 0)
+	filters := []struct {
+		name   string
+		filter query.OperationFilter
+	}{
+		{name: "month", filter: query.NewOperationFilter().Between(month, month.AddDate(0, 1, 0))},
+		{name: "account", filter: query.NewOperationFilter().ForAccounts(fixture.accounts[0])},
+		{name: "category_year", filter: query.NewOperationFilter().ForCategories(fixture.categories[0]).Between(month, month.AddDate(1, 0, 0))},
+		{name: "all", filter: query.NewOperationFilter()},
+	}
+
+	for _, filter := range filters {
+		for i, impl := range benchmarkRepositories {
+			repo := repos[i]
+			b.Run(filter.name+"/"+impl.name, func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					if _, err := repo.ListByFilter(filter.filter); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/domain/repository"
+)
+
+type savedViewRepository struct {
+	mu    sync.RWMutex
+	views map[domain.ID]*query.SavedView
+}
+
+func NewSavedViewRepository() repository.SavedViewRepository {
+	return &savedViewRepository{
+		views: make(map[domain.ID]*query.SavedView),
+	}
+}
+
+func (r *savedViewRepository) Create(view *query.SavedView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.views[view.ID()]; exists {
+		return domain.ErrAlreadyExists
+	}
+
+	r.views[view.ID()] = view
+	return nil
+}
+
+func (r *savedViewRepository) Update(view *query.SavedView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.views[view.ID()]; !exists {
+		return domain.ErrNotFound
+	}
+
+	r.views[view.ID()] = view
+	return nil
+}
+
+func (r *savedViewRepository) Delete(id domain.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.views[id]; !exists {
+		return domain.ErrNotFound
+	}
+
+	delete(r.views, id)
+	return nil
+}
+
+func (r *savedViewRepository) Get(id domain.ID) (*query.SavedView, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, exists := r.views[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	return view, nil
+}
+
+func (r *savedViewRepository) List() ([]*query.SavedView, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.views) == 0 {
+		return nil, nil
+	}
+
+	result := make([]*query.SavedView, 0, len(r.views))
+	for _, view := range r.views {
+		result = append(result, view)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		in := strings.ToLower(result[i].Name())
+		jn := strings.ToLower(result[j].Name())
+		if in == jn {
+			return result[i].ID() < result[j].ID()
+		}
+		return in < jn
+	})
+
+	return result, nil
+}
@@ -9,6 +9,7 @@ import (
 	exportcmd "kpo-hw-2/internal/application/command/export"
 	fileimportcmd "kpo-hw-2/internal/application/command/import"
 	operationcmd "kpo-hw-2/internal/application/command/operation"
+	viewcmd "kpo-hw-2/internal/application/command/view"
 )
 
 type ScreenContext interface {
@@ -17,6 +18,7 @@ type ScreenContext interface {
 	AccountCommands() *accountcmd.Service
 	CategoryCommands() *categorycmd.Service
 	OperationCommands() *operationcmd.Service
+	ViewCommands() *viewcmd.Service
 	ExportCommands() *exportcmd.Service
 	ImportCommands() *fileimportcmd.Service
 	AnalyticsCommands() *analyticscmd.Service
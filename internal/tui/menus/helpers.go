@@ -22,6 +22,14 @@ type SelectConfig struct {
 	OnChange     func(string)
 }
 
+type MultiSelectConfig struct {
+	Initial      []string
+	Exclude      bool
+	AllowExclude bool
+	EmptyLabel   string
+	OnChange     func(string)
+}
+
 func NewInputItem(key, title, description string, cfg InputConfig) MenuItem {
 	model := textinput.New()
 	if cfg.Prompt != "" {
@@ -82,6 +90,21 @@ func NewSelectItem(key, title, description string, options []SelectOption, cfg S
 	return item
 }
 
+func NewMultiSelectItem(key, title, description string, options []SelectOption, cfg MultiSelectConfig) MenuItem {
+	item := &multiSelectItem{
+		key:          key,
+		title:        title,
+		description:  description,
+		emptyLabel:   cfg.EmptyLabel,
+		options:      options,
+		checked:      make(map[int]bool),
+		allowExclude: cfg.AllowExclude,
+		onChange:     cfg.OnChange,
+	}
+	item.SetValue(FormatMultiValue(cfg.Initial, cfg.Exclude))
+	return item
+}
+
 func NewActionItem(
 	key, title, description string,
 	action func(ctx tui.ScreenContext, values Values) tui.Result,
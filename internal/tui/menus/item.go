@@ -12,6 +12,7 @@ const (
 	ItemAction ItemKind = iota
 	ItemInput
 	ItemSelect
+	ItemMultiSelect
 )
 
 type MenuItem interface {
@@ -0,0 +1,298 @@
+package menus
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/styles"
+)
+
+const (
+	multiValueSeparator = ","
+	multiExcludePrefix  = "!"
+)
+
+type multiSelectItem struct {
+	key         string
+	title       string
+	description string
+	errorText   string
+	emptyLabel  string
+
+	options      []SelectOption
+	checked      map[int]bool
+	exclude      bool
+	allowExclude bool
+	cursor       int
+	expanded     bool
+
+	snapshot        map[int]bool
+	snapshotExclude bool
+
+	onChange func(string)
+}
+
+func (m *multiSelectItem) Key() string         { return m.key }
+func (m *multiSelectItem) Title() string       { return m.title }
+func (m *multiSelectItem) Description() string { return m.description }
+func (m *multiSelectItem) Kind() ItemKind      { return ItemMultiSelect }
+
+func (m *multiSelectItem) Value() string {
+	selected := m.selectedValues()
+	if len(selected) == 0 {
+		return ""
+	}
+	return FormatMultiValue(selected, m.exclude)
+}
+
+func (m *multiSelectItem) SetValue(value string) {
+	values, exclude := ParseMultiValue(value)
+	m.checked = make(map[int]bool, len(values))
+	for _, v := range values {
+		for idx, opt := range m.options {
+			if opt.Value == v {
+				m.checked[idx] = true
+				break
+			}
+		}
+	}
+	m.exclude = exclude && m.allowExclude
+}
+
+func (m *multiSelectItem) Focus() tea.Cmd {
+	return nil
+}
+
+func (m *multiSelectItem) Blur() {
+	if m.expanded {
+		m.commit(nil)
+	}
+}
+
+func (m *multiSelectItem) Handle(msg tea.Msg, _ tui.ScreenContext, values Values) (tui.Result, bool) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return tui.Result{}, false
+	}
+
+	key := keyMsg.String()
+
+	if !m.expanded {
+		if key == "enter" {
+			if len(m.options) == 0 {
+				return tui.Result{}, true
+			}
+			m.expanded = true
+			m.snapshot = copyChecked(m.checked)
+			m.snapshotExclude = m.exclude
+			return tui.Result{}, true
+		}
+		return tui.Result{}, false
+	}
+
+	switch key {
+	case "up":
+		m.moveCursor(-1)
+	case "down":
+		m.moveCursor(1)
+	case " ":
+		m.checked[m.cursor] = !m.checked[m.cursor]
+	case "a":
+		m.toggleAll()
+	case "x":
+		if m.allowExclude {
+			m.exclude = !m.exclude
+		}
+	case "enter":
+		m.commit(values)
+	case "esc":
+		m.expanded = false
+		m.checked = m.snapshot
+		m.exclude = m.snapshotExclude
+		m.snapshot = nil
+	}
+
+	return tui.Result{}, true
+}
+
+func (m *multiSelectItem) View(selected bool) string {
+	var b strings.Builder
+
+	label := styles.ItemTitle(m.title, selected)
+	b.WriteString(label)
+	b.WriteString(":\n")
+
+	if len(m.options) == 0 {
+		placeholder := styles.Description("Нет вариантов")
+		b.WriteString("  " + placeholder + "\n")
+	} else if m.expanded {
+		if m.allowExclude {
+			mode := "Режим: только отмеченные"
+			if m.exclude {
+				mode = "Режим: все, кроме отмеченных"
+			}
+			b.WriteString("  " + styles.Description(mode) + "\n")
+		}
+		for idx, opt := range m.options {
+			active := idx == m.cursor
+			cursor := styles.CursorPrefix(active)
+			line := cursor + styles.SelectOption(checkbox(m.checked[idx])+" "+opt.Label, active)
+			b.WriteString("  " + line + "\n")
+		}
+		hint := "Пробел — отметить, a — все/ничего, Enter — применить, Esc — отмена"
+		if m.allowExclude {
+			hint = "Пробел — отметить, a — все/ничего, x — исключение, Enter — применить, Esc — отмена"
+		}
+		b.WriteString("  " + styles.Description(hint) + "\n")
+	} else {
+		valueView := styles.SelectValue(m.summary(), selected)
+		b.WriteString("  " + valueView + "\n")
+	}
+
+	if m.errorText != "" {
+		errLine := styles.Error("Ошибка: " + m.errorText)
+		b.WriteString("  " + errLine + "\n")
+	}
+
+	if m.description != "" {
+		desc := styles.Description(m.description)
+		b.WriteString("  " + desc + "\n")
+	}
+
+	return b.String()
+}
+
+func (m *multiSelectItem) SetError(message string) {
+	m.errorText = message
+}
+
+func (m *multiSelectItem) ClearError() {
+	m.errorText = ""
+}
+
+func (m *multiSelectItem) moveCursor(delta int) {
+	count := len(m.options)
+	if count == 0 {
+		m.cursor = 0
+		return
+	}
+
+	m.cursor = (m.cursor + delta + count) % count
+}
+
+func (m *multiSelectItem) toggleAll() {
+	allChecked := len(m.selectedValues()) == len(m.options)
+	m.checked = make(map[int]bool, len(m.options))
+	if allChecked {
+		return
+	}
+	for idx := range m.options {
+		m.checked[idx] = true
+	}
+}
+
+func (m *multiSelectItem) commit(values Values) {
+	m.expanded = false
+	changed := m.exclude != m.snapshotExclude || !sameChecked(m.checked, m.snapshot)
+	m.snapshot = nil
+
+	if values != nil {
+		values[m.key] = m.Value()
+	}
+	if changed && m.onChange != nil {
+		m.onChange(m.Value())
+	}
+}
+
+func (m *multiSelectItem) selectedValues() []string {
+	var out []string
+	for idx, opt := range m.options {
+		if m.checked[idx] {
+			out = append(out, opt.Value)
+		}
+	}
+	return out
+}
+
+func (m *multiSelectItem) summary() string {
+	var labels []string
+	for idx, opt := range m.options {
+		if m.checked[idx] {
+			labels = append(labels, opt.Label)
+		}
+	}
+
+	if len(labels) == 0 {
+		if m.emptyLabel != "" {
+			return m.emptyLabel
+		}
+		return "Не выбрано"
+	}
+
+	joined := strings.Join(labels, ", ")
+	if m.exclude {
+		return "Кроме: " + joined
+	}
+	return joined
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func copyChecked(src map[int]bool) map[int]bool {
+	dst := make(map[int]bool, len(src))
+	for k, v := range src {
+		if v {
+			dst[k] = true
+		}
+	}
+	return dst
+}
+
+func sameChecked(a, b map[int]bool) bool {
+	count := 0
+	for k, v := range a {
+		if !v {
+			continue
+		}
+		if !b[k] {
+			return false
+		}
+		count++
+	}
+	for _, v := range b {
+		if v {
+			count--
+		}
+	}
+	return count == 0
+}
+
+func FormatMultiValue(values []string, exclude bool) string {
+	joined := strings.Join(values, multiValueSeparator)
+	if exclude && joined != "" {
+		return multiExcludePrefix + joined
+	}
+	return joined
+}
+
+func ParseMultiValue(value string) ([]string, bool) {
+	value = strings.TrimSpace(value)
+	exclude := strings.HasPrefix(value, multiExcludePrefix)
+	value = strings.TrimPrefix(value, multiExcludePrefix)
+
+	var out []string
+	for _, part := range strings.Split(value, multiValueSeparator) {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out, exclude
+}
@@ -12,6 +12,7 @@ import (
 	exportcmd "kpo-hw-2/internal/application/command/export"
 	fileimportcmd "kpo-hw-2/internal/application/command/import"
 	operationcmd "kpo-hw-2/internal/application/command/operation"
+	viewcmd "kpo-hw-2/internal/application/command/view"
 	"kpo-hw-2/internal/tui/styles"
 )
 
@@ -25,6 +26,7 @@ func NewProgram(
 	accountCommands *accountcmd.Service,
 	categoryCommands *categorycmd.Service,
 	operationCommands *operationcmd.Service,
+	viewCommands *viewcmd.Service,
 	exportCommands *exportcmd.Service,
 	importCommands *fileimportcmd.Service,
 	analyticsCommands *analyticscmd.Service,
@@ -39,6 +41,7 @@ func NewProgram(
 			accountCommands:   accountCommands,
 			categoryCommands:  categoryCommands,
 			operationCommands: operationCommands,
+			viewCommands:      viewCommands,
 			exportCommands:    exportCommands,
 			importCommands:    importCommands,
 			analyticsCommands: analyticsCommands,
@@ -82,6 +85,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if res.Pop {
 		m.pop()
+		if res.Replace == nil && res.Push == nil {
+			m.resume(&cmds)
+		}
 	}
 
 	if res.Replace != nil {
@@ -129,6 +135,16 @@ func (m *Model) pop() {
 	m.stack = m.stack[:len(m.stack)-1]
 }
 
+func (m *Model) resume(cmds *[]tea.Cmd) {
+	resumer, ok := m.current().(Resumer)
+	if !ok {
+		return
+	}
+	if cmd := resumer.Resume(m.ctx); cmd != nil {
+		*cmds = append(*cmds, cmd)
+	}
+}
+
 func (m *Model) replace(screen Screen, cmds *[]tea.Cmd) {
 	if len(m.stack) == 0 {
 		m.stack = append(m.stack, screen)
@@ -153,6 +169,7 @@ type programContext struct {
 	accountCommands   *accountcmd.Service
 	categoryCommands  *categorycmd.Service
 	operationCommands *operationcmd.Service
+	viewCommands      *viewcmd.Service
 	exportCommands    *exportcmd.Service
 	importCommands    *fileimportcmd.Service
 	analyticsCommands *analyticscmd.Service
@@ -171,6 +188,9 @@ func (c *programContext) CategoryCommands() *categorycmd.Service {
 func (c *programContext) OperationCommands() *operationcmd.Service {
 	return c.operationCommands
 }
+func (c *programContext) ViewCommands() *viewcmd.Service {
+	return c.viewCommands
+}
 func (c *programContext) ExportCommands() *exportcmd.Service {
 	return c.exportCommands
 }
@@ -9,6 +9,10 @@ type Screen interface {
 	View() string
 }
 
+type Resumer interface {
+	Resume(ctx ScreenContext) tea.Cmd
+}
+
 type Result struct {
 	Push    Screen
 	Replace Screen
@@ -14,9 +14,11 @@ import (
 const (
 	fieldEditName    = "edit_name"
 	fieldEditBalance = "edit_balance"
+	fieldEditKind    = "edit_kind"
+	fieldEditGroup   = "edit_group"
 )
 
-func NewEdit(account *domain.BankAccount) tui.Screen {
+func NewEdit(account *domain.BankAccount, profile *domain.AccountProfile) tui.Screen {
 	var screen *menus.Screen
 
 	validateName := func(value string) error {
@@ -35,6 +37,15 @@ func NewEdit(account *domain.BankAccount) tui.Screen {
 		return nil
 	}
 
+	kindOptions := []menus.SelectOption{
+		{Label: "Актив", Value: string(domain.AccountKindAsset)},
+		{Label: "Кредитный", Value: string(domain.AccountKindCredit)},
+	}
+	kindIndex := 0
+	if profile.Kind() == domain.AccountKindCredit {
+		kindIndex = 1
+	}
+
 	items := []menus.MenuItem{
 		menus.NewInputItem(
 			fieldEditName,
@@ -52,6 +63,22 @@ func NewEdit(account *domain.BankAccount) tui.Screen {
 				Initial: strconv.FormatInt(account.Balance(), 10),
 			},
 		),
+		menus.NewSelectItem(
+			fieldEditKind,
+			"Вид счёта",
+			"Кредитный счёт может уходить в минус, отрицательный баланс считается задолженностью.",
+			kindOptions,
+			menus.SelectConfig{InitialIndex: kindIndex},
+		),
+		menus.NewInputItem(
+			fieldEditGroup,
+			"Группа",
+			"Произвольная группа для отчёта о чистых активах, например «Сбережения».",
+			menus.InputConfig{
+				Initial:     profile.Group(),
+				Placeholder: "Без группы",
+			},
+		),
 		menus.NewActionItem(
 			"save",
 			"Сохранить",
@@ -74,13 +101,41 @@ func NewEdit(account *domain.BankAccount) tui.Screen {
 				}
 
 				balance, _ := strconv.ParseInt(balanceStr, 10, 64)
+				kind := domain.AccountKind(values[fieldEditKind])
+				group := strings.TrimSpace(values[fieldEditGroup])
 
-				updateCmd := ctx.AccountCommands().Update(account.ID(), name, balance)
-				if _, err := updateCmd.Execute(ctx.Context()); err != nil {
-					screen.SetFieldError(fieldEditName, err.Error())
+				if balance < 0 && !kind.AllowsNegativeBalance() {
+					screen.SetFieldError(fieldEditBalance, "баланс актива не может быть отрицательным")
 					return tui.Result{}
 				}
 
+				updateBalance := func() bool {
+					updateCmd := ctx.AccountCommands().Update(account.ID(), name, balance)
+					if _, err := updateCmd.Execute(ctx.Context()); err != nil {
+						screen.SetFieldError(fieldEditName, err.Error())
+						return false
+					}
+					return true
+				}
+				updateProfile := func() bool {
+					profileCmd := ctx.AccountCommands().SetProfile(account.ID(), kind, group)
+					if _, err := profileCmd.Execute(ctx.Context()); err != nil {
+						screen.SetFieldError(fieldEditKind, err.Error())
+						return false
+					}
+					return true
+				}
+
+				steps := []func() bool{updateBalance, updateProfile}
+				if kind.AllowsNegativeBalance() {
+					steps = []func() bool{updateProfile, updateBalance}
+				}
+				for _, step := range steps {
+					if !step() {
+						return tui.Result{}
+					}
+				}
+
 				listCmd := ctx.AccountCommands().List()
 				accounts, err := listCmd.Execute(ctx.Context())
 				if err != nil {
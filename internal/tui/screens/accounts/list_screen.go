@@ -18,7 +18,12 @@ func NewList(accounts []*domain.BankAccount) tui.Screen {
 			acc.Name(),
 			fmt.Sprintf("Баланс: %d", acc.Balance()),
 			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
-				return tui.Result{Replace: NewEdit(acc)}
+				profileCmd := ctx.AccountCommands().Profile(acc.ID())
+				profile, err := profileCmd.Execute(ctx.Context())
+				if err != nil {
+					profile = domain.DefaultAccountProfile(acc.ID())
+				}
+				return tui.Result{Replace: NewEdit(acc, profile)}
 			},
 		))
 	}
@@ -20,6 +20,19 @@ func NewMenu() tui.Screen {
 				return tui.Result{Push: NewList(accounts)}
 			},
 		),
+		menus.NewActionItem(
+			"statement",
+			"Выписка по счёту",
+			"Операции счёта с остатком после каждой и баланс на дату.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				cmd := ctx.AccountCommands().List()
+				accounts, err := cmd.Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{}
+				}
+				return tui.Result{Push: NewStatementPicker(accounts)}
+			},
+		),
 		menus.NewActionItem(
 			"create",
 			"Добавить счёт",
@@ -0,0 +1,189 @@
+package accountsmenu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+	"kpo-hw-2/internal/tui/screens/operations"
+)
+
+const (
+	fieldStatementDate  = "statement_date"
+	statementDateLayout = "2006-01-02"
+	statementSeriesDays = 30
+)
+
+func NewStatementPicker(accounts []*domain.BankAccount) tui.Screen {
+	items := make([]menus.MenuItem, 0, len(accounts)+1)
+
+	for _, account := range accounts {
+		acc := account
+		items = append(items, menus.NewActionItem(
+			acc.ID().String(),
+			acc.Name(),
+			fmt.Sprintf("Баланс: %d", acc.Balance()),
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				return openStatement(ctx, acc)
+			},
+		))
+	}
+
+	items = append(items, menus.NewPopItem("Назад", "Вернуться в меню счетов"))
+
+	return menus.NewScreen(
+		"Выписка по счёту",
+		"Выберите счёт.",
+		items,
+	).WithEmptyMessage("Счета ещё не добавлены.")
+}
+
+func openStatement(ctx tui.ScreenContext, account *domain.BankAccount) tui.Result {
+	statement, err := ctx.AnalyticsCommands().Statement(account.ID()).Execute(ctx.Context())
+	if err != nil {
+		return tui.Result{Push: messageScreen("Ошибка", fmt.Sprintf("Не удалось построить выписку:\n%s", err.Error()))}
+	}
+
+	categories, err := ctx.CategoryCommands().List("").Execute(ctx.Context())
+	if err != nil {
+		return tui.Result{Push: messageScreen("Ошибка", fmt.Sprintf("Не удалось получить список категорий:\n%s", err.Error()))}
+	}
+
+	return tui.Result{Push: NewStatement(account, statement, categories)}
+}
+
+func NewStatement(account *domain.BankAccount, statement appanalytics.Statement, categories []*domain.Category) tui.Screen {
+	var screen *menus.Screen
+
+	categoryNames := make(map[domain.ID]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID()] = category.Name()
+	}
+
+	items := make([]menus.MenuItem, 0, len(statement.Lines)+3)
+	items = append(items,
+		menus.NewInputItem(
+			fieldStatementDate,
+			"Баланс на дату",
+			fmt.Sprintf("Остаток на конец дня и ежедневные остатки за %d дней до него.", statementSeriesDays),
+			menus.InputConfig{
+				Initial:     time.Now().Format(statementDateLayout),
+				Placeholder: "ГГГГ-ММ-ДД",
+			},
+		),
+		menus.NewActionItem(
+			"balance_as_of",
+			"Показать баланс",
+			"Рассчитать остаток на указанную дату.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				date, err := time.Parse(statementDateLayout, strings.TrimSpace(values[fieldStatementDate]))
+				if err != nil {
+					screen.SetFieldError(fieldStatementDate, "используйте формат ГГГГ-ММ-ДД")
+					return tui.Result{}
+				}
+				screen.SetFieldError(fieldStatementDate, "")
+
+				endOfDay := date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+				balance, err := ctx.AnalyticsCommands().BalanceAsOf(account.ID(), endOfDay).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldStatementDate, err.Error())
+					return tui.Result{}
+				}
+
+				from := date.AddDate(0, 0, -(statementSeriesDays - 1))
+				series, err := ctx.AnalyticsCommands().BalanceSeries(account.ID(), from, endOfDay).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldStatementDate, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: messageScreen(
+					fmt.Sprintf("Баланс: %s", account.Name()),
+					describeBalance(date, balance, series),
+				)}
+			},
+		),
+	)
+
+	for _, line := range statement.Lines {
+		line := line
+		op := line.Operation
+
+		sign := "+"
+		if op.Type() == domain.OperationTypeExpense {
+			sign = "-"
+		}
+		categoryName := categoryNames[op.CategoryID()]
+		if categoryName == "" {
+			categoryName = op.CategoryID().String()
+		}
+		title := strings.TrimSpace(op.Description())
+		if title == "" {
+			title = categoryName
+		}
+
+		items = append(items, menus.NewActionItem(
+			op.ID().String(),
+			fmt.Sprintf("%s • %s", op.Date().Format(statementDateLayout), title),
+			fmt.Sprintf("Сумма: %s%d • Остаток: %d • Категория: %s", sign, op.Amount(), line.Balance, categoryName),
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				operation, err := ctx.OperationCommands().Get(op.ID()).Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{}
+				}
+				accounts, err := ctx.AccountCommands().List().Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{}
+				}
+				categories, err := ctx.CategoryCommands().List("").Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{}
+				}
+				return tui.Result{Replace: operations.NewEdit(operation, accounts, categories)}
+			},
+		))
+	}
+
+	items = append(items, menus.NewPopItem("Назад", "Вернуться к выбору счёта"))
+
+	screen = menus.NewScreen(
+		fmt.Sprintf("Выписка: %s", account.Name()),
+		fmt.Sprintf(
+			"Начальный остаток: %d • Текущий баланс: %d • Операций: %d",
+			statement.Opening,
+			statement.Closing,
+			len(statement.Lines),
+		),
+		items,
+	)
+
+	return screen
+}
+
+func describeBalance(date time.Time, balance int64, series []appanalytics.BalancePoint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Остаток на конец %s: %d\n", date.Format(statementDateLayout), balance)
+	if len(series) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\nЕжедневные остатки:\n")
+	for _, point := range series {
+		fmt.Fprintf(&b, "%s  %d\n", point.Date.Format(statementDateLayout), point.Balance)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func messageScreen(title, message string) tui.Screen {
+	return menus.NewScreen(
+		title,
+		message,
+		[]menus.MenuItem{
+			menus.NewPopItem("Назад", "Вернуться к выписке"),
+		},
+	)
+}
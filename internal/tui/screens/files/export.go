@@ -1,16 +1,35 @@
 package files
 
 import (
+	"errors"
 	"strings"
+	"time"
 
+	fileexport "kpo-hw-2/internal/application/files/export"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
 	"kpo-hw-2/internal/tui"
 	"kpo-hw-2/internal/tui/menus"
 )
 
 const (
-	fieldExportFormat = "export_format"
-	fieldExportDir    = "export_dir"
-	fieldExportName   = "export_name"
+	fieldExportFormat     = "export_format"
+	fieldExportDir        = "export_dir"
+	fieldExportName       = "export_name"
+	fieldExportScope      = "export_scope"
+	fieldExportStart      = "export_start"
+	fieldExportEnd        = "export_end"
+	fieldExportAccounts   = "export_accounts"
+	fieldExportCategories = "export_categories"
+
+	exportDateLayout = "2006-01-02"
+)
+
+const (
+	scopeAll        = "all"
+	scopeReferenced = "referenced"
+	scopeOperations = "operations"
+	scopeReference  = "reference"
 )
 
 func newExportScreen(ctx tui.ScreenContext) tui.Screen {
@@ -23,6 +42,7 @@ func newExportScreen(ctx tui.ScreenContext) tui.Screen {
 
 	defaultFormat := formats[defaultIndex]
 	defaultDir, defaultName := defaultExportLocation(defaultFormat)
+	accountOptions, categoryOptions := exportSelectionOptions(ctx)
 
 	items := []menus.MenuItem{
 		menus.NewSelectItem(
@@ -50,6 +70,43 @@ func newExportScreen(ctx tui.ScreenContext) tui.Screen {
 				Initial:     defaultName,
 			},
 		),
+		menus.NewSelectItem(
+			fieldExportScope,
+			"Состав",
+			"Какие данные записать в файл.",
+			scopeOptions(),
+			menus.SelectConfig{},
+		),
+		menus.NewInputItem(
+			fieldExportStart,
+			"Дата начала",
+			"Оставьте пустым, чтобы не ограничивать начало периода.",
+			menus.InputConfig{
+				Placeholder: "ГГГГ-ММ-ДД",
+			},
+		),
+		menus.NewInputItem(
+			fieldExportEnd,
+			"Дата окончания",
+			"Оставьте пустым, чтобы не ограничивать конец периода.",
+			menus.InputConfig{
+				Placeholder: "ГГГГ-ММ-ДД",
+			},
+		),
+		menus.NewMultiSelectItem(
+			fieldExportAccounts,
+			"Счета",
+			"Отметьте счета или оставьте пустым для всех; x — исключить отмеченные.",
+			accountOptions,
+			menus.MultiSelectConfig{AllowExclude: true, EmptyLabel: "Все счета"},
+		),
+		menus.NewMultiSelectItem(
+			fieldExportCategories,
+			"Категории",
+			"Отметьте категории или оставьте пустым для всех; x — исключить отмеченные.",
+			categoryOptions,
+			menus.MultiSelectConfig{AllowExclude: true, EmptyLabel: "Все категории"},
+		),
 		menus.NewActionItem(
 			"save",
 			"Сохранить",
@@ -78,15 +135,27 @@ func newExportScreen(ctx tui.ScreenContext) tui.Screen {
 					return tui.Result{}
 				}
 
+				options, ok := readExportOptions(screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
 				path := exportFilePath(dir, name, format)
 
-				cmd := context.ExportCommands().ExportToPath(formatKey, path)
+				cmd := context.ExportCommands().ExportToPath(formatKey, path, options)
 				if _, err := cmd.Execute(context.Context()); err != nil {
+					if errors.Is(err, fileexport.ErrNoEntities) {
+						screen.SetFieldError(fieldExportScope, "выберите данные для экспорта")
+						return tui.Result{}
+					}
 					screen.SetFieldError(fieldExportName, err.Error())
 					return tui.Result{}
 				}
 
 				successMessage := "Данные сохранены в " + path
+				if options.Partial() {
+					successMessage += "\nВ файл попали только выбранные операции и связанные с ними счета и категории."
+				}
 				return tui.Result{
 					Push: successScreen("Экспорт завершён", successMessage),
 				}
@@ -102,3 +171,100 @@ func newExportScreen(ctx tui.ScreenContext) tui.Screen {
 	)
 	return screen
 }
+
+func exportSelectionOptions(ctx tui.ScreenContext) ([]menus.SelectOption, []menus.SelectOption) {
+	var accountOptions, categoryOptions []menus.SelectOption
+
+	if accounts, err := ctx.AccountCommands().List().Execute(ctx.Context()); err == nil {
+		for _, account := range accounts {
+			accountOptions = append(accountOptions, menus.SelectOption{
+				Label: account.Name(),
+				Value: account.ID().String(),
+			})
+		}
+	}
+
+	if categories, err := ctx.CategoryCommands().List("").Execute(ctx.Context()); err == nil {
+		for _, category := range categories {
+			categoryOptions = append(categoryOptions, menus.SelectOption{
+				Label: category.Name(),
+				Value: category.ID().String(),
+			})
+		}
+	}
+
+	return accountOptions, categoryOptions
+}
+
+func scopeOptions() []menus.SelectOption {
+	return []menus.SelectOption{
+		{Label: "Все данные", Value: scopeAll},
+		{Label: "Операции со счетами и категориями", Value: scopeReferenced},
+		{Label: "Только операции", Value: scopeOperations},
+		{Label: "Только счета и категории", Value: scopeReference},
+	}
+}
+
+func scopeEntities(value string) fileexport.Entities {
+	switch value {
+	case scopeReferenced:
+		return fileexport.Entities{Accounts: true, Categories: true, Operations: true}
+	case scopeOperations:
+		return fileexport.Entities{Operations: true}
+	case scopeReference:
+		return fileexport.Entities{Accounts: true, Categories: true}
+	default:
+		return fileexport.AllEntities()
+	}
+}
+
+func readExportOptions(screen *menus.Screen, values menus.Values) (fileexport.Options, bool) {
+	start, startOK := parseExportDate(screen, fieldExportStart, values[fieldExportStart])
+	end, endOK := parseExportDate(screen, fieldExportEnd, values[fieldExportEnd])
+	if !startOK || !endOK {
+		return fileexport.Options{}, false
+	}
+	if start != nil && end != nil && start.After(*end) {
+		screen.SetFieldError(fieldExportStart, "дата начала должна предшествовать окончанию")
+		screen.SetFieldError(fieldExportEnd, "дата окончания должна следовать после начала")
+		return fileexport.Options{}, false
+	}
+	screen.SetFieldError(fieldExportScope, "")
+
+	filter := query.NewOperationFilter().
+		WithAccounts(exportIDSelector(values[fieldExportAccounts])).
+		WithCategories(exportIDSelector(values[fieldExportCategories])).
+		WithinPeriod(query.CustomPeriod(start, end), time.Now())
+
+	return fileexport.Options{
+		Filter:   filter,
+		Entities: scopeEntities(screen.Value(fieldExportScope)),
+	}, true
+}
+
+func parseExportDate(screen *menus.Screen, field, value string) (*time.Time, bool) {
+	value = strings.TrimSpace(value)
+	screen.SetFieldError(field, "")
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse(exportDateLayout, value)
+	if err != nil {
+		screen.SetFieldError(field, "используйте формат ГГГГ-ММ-ДД")
+		return nil, false
+	}
+	return &parsed, true
+}
+
+func exportIDSelector(value string) query.Selector[domain.ID] {
+	raw, exclude := menus.ParseMultiValue(value)
+	ids := make([]domain.ID, 0, len(raw))
+	for _, v := range raw {
+		ids = append(ids, domain.ID(v))
+	}
+	if exclude {
+		return query.Exclude(ids...)
+	}
+	return query.Include(ids...)
+}
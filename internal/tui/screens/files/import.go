@@ -1,6 +1,7 @@
 package files
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,9 +11,14 @@ import (
 )
 
 const (
-	fieldImportFormat = "import_format"
-	fieldImportDir    = "import_dir"
-	fieldImportName   = "import_name"
+	fieldImportFormat   = "import_format"
+	fieldImportDir      = "import_dir"
+	fieldImportName     = "import_name"
+	fieldImportStrategy = "import_strategy"
+	fieldImportMode     = "import_mode"
+	fieldImportProfile  = "import_profile"
+	fieldImportEncoding = "import_encoding"
+	fieldImportDelim    = "import_delimiter"
 )
 
 func newImportScreen(ctx tui.ScreenContext) tui.Screen {
@@ -38,6 +44,27 @@ func newImportScreen(ctx tui.ScreenContext) tui.Screen {
 			options,
 			menus.SelectConfig{InitialIndex: defaultIndex},
 		),
+		menus.NewSelectItem(
+			fieldImportProfile,
+			"Профиль выписки",
+			"Сопоставление колонок для банковской выписки (файлы в storage/profiles).",
+			profileOptions(ctx),
+			menus.SelectConfig{},
+		),
+		menus.NewSelectItem(
+			fieldImportEncoding,
+			"Кодировка",
+			"Кодировка CSV-файла; при автоопределении файл, не являющийся UTF-8, читается как Windows-1251.",
+			encodingOptions(),
+			menus.SelectConfig{},
+		),
+		menus.NewSelectItem(
+			fieldImportDelim,
+			"Разделитель",
+			"Разделитель полей CSV-файла.",
+			delimiterOptions(),
+			menus.SelectConfig{},
+		),
 		menus.NewInputItem(
 			fieldImportDir,
 			"Папка",
@@ -56,10 +83,24 @@ func newImportScreen(ctx tui.ScreenContext) tui.Screen {
 				Initial:     defaultName,
 			},
 		),
+		menus.NewSelectItem(
+			fieldImportStrategy,
+			"Совпадения",
+			"Что делать с записями, идентификатор которых уже есть в приложении. «Оставить новейшую» сравнивает время изменения и работает только для операций.",
+			strategyOptions(),
+			menus.SelectConfig{},
+		),
+		menus.NewSelectItem(
+			fieldImportMode,
+			"Балансы",
+			"Взять балансы счетов из файла как есть или вычислить начальные балансы и провести операции через баланс.",
+			modeOptions(),
+			menus.SelectConfig{},
+		),
 		menus.NewActionItem(
 			"load",
-			"Загрузить",
-			"Импортировать данные из файла.",
+			"Проверить и загрузить",
+			"Проверить записи файла и показать результат перед импортом.",
 			func(context tui.ScreenContext, values menus.Values) tui.Result {
 				formatKey := screen.Value(fieldImportFormat)
 				format := findFormat(formats, formatKey)
@@ -85,18 +126,40 @@ func newImportScreen(ctx tui.ScreenContext) tui.Screen {
 				}
 
 				path := exportFilePath(dir, name, format)
+				options := fileimport.Options{
+					Strategy: fileimport.Strategy(screen.Value(fieldImportStrategy)),
+					Mode:     fileimport.Mode(screen.Value(fieldImportMode)),
+					Profile:  screen.Value(fieldImportProfile),
+					Dialect: fileimport.Dialect{
+						Encoding:  screen.Value(fieldImportEncoding),
+						Delimiter: screen.Value(fieldImportDelim),
+					},
+				}
 
-				cmd := context.ImportCommands().ImportFromPath(formatKey, path)
-				result, err := cmd.Execute(context.Context())
-				if err != nil {
+				cmd := context.ImportCommands().PreviewFromPath(formatKey, path, options)
+				preview, err := cmd.Execute(context.Context())
+				screen.SetFieldError(fieldImportProfile, "")
+				screen.SetFieldError(fieldImportEncoding, "")
+				switch {
+				case errors.Is(err, fileimport.ErrProfileRequired):
+					screen.SetFieldError(fieldImportProfile, "выберите профиль выписки")
+					return tui.Result{}
+				case errors.Is(err, fileimport.ErrProfileUnsupported):
+					screen.SetFieldError(fieldImportProfile, "формат не поддерживает профили")
+					return tui.Result{}
+				case errors.Is(err, fileimport.ErrDialectUnsupported):
+					screen.SetFieldError(fieldImportEncoding, "кодировка и разделитель задаются только для CSV")
+					return tui.Result{}
+				case errors.Is(err, fileimport.ErrUnknownProfile):
+					screen.SetFieldError(fieldImportProfile, "профиль не найден")
+					return tui.Result{}
+				case err != nil:
 					screen.SetFieldError(fieldImportName, err.Error())
 					return tui.Result{}
 				}
+				screen.SetFieldError(fieldImportName, "")
 
-				message := formatImportResult(path, result)
-				return tui.Result{
-					Push: successScreen("Импорт завершён", message),
-				}
+				return tui.Result{Push: newImportPreview(formatKey, path, options, preview)}
 			},
 		),
 		menus.NewPopItem("Назад", "Вернуться к меню файлов"),
@@ -111,14 +174,118 @@ func newImportScreen(ctx tui.ScreenContext) tui.Screen {
 }
 
 func formatImportResult(path string, result fileimport.Result) string {
-	return fmt.Sprintf(
-		"Данные загружены из %s.\nСоздано: %d счетов, %d категорий, %d операций.\nПропущено: %d/%d/%d.",
+	message := fmt.Sprintf(
+		"Данные загружены из %s.\nСоздано: %d счетов, %d категорий, %d операций, %d представлений.\nОбновлено: %d/%d/%d/%d.\nПропущено: %d/%d/%d/%d.",
 		path,
 		result.CreatedAccounts,
 		result.CreatedCategories,
 		result.CreatedOperations,
+		result.CreatedViews,
+		result.UpdatedAccounts,
+		result.UpdatedCategories,
+		result.UpdatedOperations,
+		result.UpdatedViews,
 		result.SkippedAccounts,
 		result.SkippedCategories,
 		result.SkippedOperations,
+		result.SkippedViews,
 	)
+
+	if rounded := countWarnings(result.Records); rounded > 0 {
+		message += fmt.Sprintf("\nДробные суммы округлены до целых: %d записей.", rounded)
+	}
+
+	if len(result.Balances) > 0 {
+		message += "\n\n" + describeBalances(result.Balances)
+	}
+
+	if result.BatchID != "" {
+		message += "\nОтменить этот импорт можно в истории импорта."
+	}
+
+	var failed []fileimport.RecordOutcome
+	for _, record := range result.Records {
+		if record.Outcome == fileimport.OutcomeInvalid || record.Outcome == fileimport.OutcomeMissingReference {
+			failed = append(failed, record)
+		}
+	}
+	if len(failed) > 0 {
+		message += "\n\nНе загружены:\n" + describeRecords(failed)
+	}
+
+	return message
+}
+
+func strategyOptions() []menus.SelectOption {
+	strategies := fileimport.Strategies()
+	options := make([]menus.SelectOption, len(strategies))
+	for i, strategy := range strategies {
+		options[i] = menus.SelectOption{Label: strategyLabel(strategy), Value: string(strategy)}
+	}
+	return options
+}
+
+func strategyLabel(strategy fileimport.Strategy) string {
+	switch strategy {
+	case fileimport.StrategySkip:
+		return "Пропустить"
+	case fileimport.StrategyOverwrite:
+		return "Перезаписать"
+	case fileimport.StrategyKeepNewest:
+		return "Оставить новейшую"
+	case fileimport.StrategyImportAsNew:
+		return "Импортировать как новые"
+	default:
+		return string(strategy)
+	}
+}
+
+func modeOptions() []menus.SelectOption {
+	modes := fileimport.Modes()
+	options := make([]menus.SelectOption, len(modes))
+	for i, mode := range modes {
+		options[i] = menus.SelectOption{Label: modeLabel(mode), Value: string(mode)}
+	}
+	return options
+}
+
+func modeLabel(mode fileimport.Mode) string {
+	switch mode {
+	case fileimport.ModeSnapshot:
+		return "Как в файле"
+	case fileimport.ModeOpeningBalance:
+		return "Начальные балансы"
+	default:
+		return string(mode)
+	}
+}
+
+func encodingOptions() []menus.SelectOption {
+	return []menus.SelectOption{
+		{Label: "Автоопределение", Value: ""},
+		{Label: "UTF-8", Value: "utf-8"},
+		{Label: "Windows-1251", Value: "windows-1251"},
+	}
+}
+
+func delimiterOptions() []menus.SelectOption {
+	return []menus.SelectOption{
+		{Label: "Автоопределение", Value: ""},
+		{Label: "Точка с запятой", Value: ";"},
+		{Label: "Запятая", Value: ","},
+		{Label: "Табуляция", Value: "tab"},
+	}
+}
+
+func profileOptions(ctx tui.ScreenContext) []menus.SelectOption {
+	options := []menus.SelectOption{{Label: "Без профиля", Value: ""}}
+
+	profiles, err := ctx.ImportCommands().Profiles().Execute(ctx.Context())
+	if err != nil {
+		return options
+	}
+	for _, profile := range profiles {
+		options = append(options, menus.SelectOption{Label: profile.Name, Value: profile.Name})
+	}
+	return options
 }
@@ -0,0 +1,123 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain/repository"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const batchTimeLayout = "02.01.2006 15:04:05"
+
+func loadImportHistory(ctx tui.ScreenContext) tui.Screen {
+	if ctx.ImportCommands() == nil {
+		return successScreen("История импорта", "Импорт недоступен.")
+	}
+
+	batches, err := ctx.ImportCommands().Batches().Execute(ctx.Context())
+	if err != nil {
+		return successScreen("Ошибка", fmt.Sprintf("Не удалось загрузить историю импорта:\n%s", err.Error()))
+	}
+
+	return newImportHistory(batches)
+}
+
+func newImportHistory(batches []repository.ImportBatch) tui.Screen {
+	items := make([]menus.MenuItem, 0, len(batches)+1)
+	for _, batch := range batches {
+		batch := batch
+		items = append(items, menus.NewActionItem(
+			batch.ID.String(),
+			batchTitle(batch),
+			describeBatch(batch),
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Replace: newRollbackScreen(batch)}
+			},
+		))
+	}
+
+	items = append(items, menus.NewPopItem("Назад", "Вернуться к меню файлов"))
+
+	return menus.NewScreen(
+		"История импорта",
+		"Выберите импорт, чтобы отменить его изменения.",
+		items,
+	).WithEmptyMessage("Импортов пока не было.")
+}
+
+func newRollbackScreen(batch repository.ImportBatch) tui.Screen {
+	items := []menus.MenuItem{
+		menus.NewActionItem(
+			"rollback",
+			"Откатить",
+			"Удалить созданные записи и вернуть обновлённые к прежнему виду.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				if _, err := ctx.ImportCommands().Rollback(batch.ID).Execute(ctx.Context()); err != nil {
+					return tui.Result{Replace: successScreen("Ошибка отката", describeRollbackError(err))}
+				}
+
+				return tui.Result{Replace: successScreen(
+					"Откат выполнен",
+					fmt.Sprintf("Импорт от %s отменён.\n%s", batch.CreatedAt.Format(batchTimeLayout), describeBatch(batch)),
+				)}
+			},
+		),
+		menus.NewActionItem(
+			"back",
+			"Назад",
+			"Вернуться к истории импорта.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				return tui.Result{Replace: loadImportHistory(ctx)}
+			},
+		),
+	}
+
+	return menus.NewScreen(
+		"Откат импорта",
+		fmt.Sprintf("%s\n%s", batchTitle(batch), describeBatch(batch)),
+		items,
+	)
+}
+
+func batchTitle(batch repository.ImportBatch) string {
+	parts := []string{batch.CreatedAt.Format(batchTimeLayout), strings.ToUpper(batch.Format)}
+	if batch.Source != "" {
+		parts = append(parts, batch.Source)
+	}
+	return strings.Join(parts, " • ")
+}
+
+func describeBatch(batch repository.ImportBatch) string {
+	created, updated := 0, 0
+	for _, change := range batch.Changes {
+		if change.Action == repository.ImportCreated {
+			created++
+		} else {
+			updated++
+		}
+	}
+	return fmt.Sprintf(
+		"Создано записей: %d • обновлено: %d • совпадения: %s • балансы: %s",
+		created,
+		updated,
+		strategyLabel(fileimport.Strategy(batch.Strategy)),
+		modeLabel(fileimport.Mode(batch.Mode)),
+	)
+}
+
+func describeRollbackError(err error) string {
+	switch {
+	case errors.Is(err, fileimport.ErrBatchInUse):
+		return "Созданные импортом счета или категории уже используются в других операциях. Удалите эти операции и повторите откат."
+	case errors.Is(err, fileimport.ErrRollbackConflict):
+		return fmt.Sprintf("После импорта эти записи были изменены, откат перезаписал бы правки. Ничего не отменено:\n%s", err.Error())
+	case errors.Is(err, fileimport.ErrRollbackFailed):
+		return fmt.Sprintf("Откат выполнен не полностью, оставшиеся изменения сохранены в истории:\n%s", err.Error())
+	default:
+		return err.Error()
+	}
+}
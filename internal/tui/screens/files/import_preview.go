@@ -0,0 +1,243 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	fileimport "kpo-hw-2/internal/application/files/import"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const maxListedRecords = 200
+
+var importEntities = []fileimport.Entity{
+	fileimport.EntityAccount,
+	fileimport.EntityCategory,
+	fileimport.EntityOperation,
+	fileimport.EntityView,
+}
+
+func newImportPreview(formatKey, path string, options fileimport.Options, preview fileimport.Preview) tui.Screen {
+	options.IDs = preview.IDs
+	items := []menus.MenuItem{
+		menus.NewActionItem(
+			"confirm",
+			"Импортировать",
+			"Создать и обновить записи согласно проверке.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				cmd := ctx.ImportCommands().ImportFromPath(formatKey, path, options)
+				result, err := cmd.Execute(ctx.Context())
+				if err != nil {
+					message := err.Error()
+					if errors.Is(err, fileimport.ErrImportAborted) {
+						message = "Импорт прерван, уже внесённые изменения отменены.\n" + message
+					}
+					return tui.Result{Replace: successScreen("Ошибка импорта", message)}
+				}
+
+				return tui.Result{Replace: successScreen("Импорт завершён", formatImportResult(path, result))}
+			},
+		),
+		menus.NewActionItem(
+			"details",
+			"Подробности",
+			"Результат проверки по каждой записи.",
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Push: newImportRecords(preview.Records)}
+			},
+		),
+		menus.NewPopItem("Отмена", "Вернуться к параметрам импорта"),
+	}
+
+	return menus.NewScreen(
+		"Проверка импорта",
+		fmt.Sprintf(
+			"Файл: %s\nСовпадения: %s • балансы: %s\n\n%s",
+			path,
+			strategyLabel(options.Strategy),
+			modeLabel(options.Mode),
+			describePreview(preview),
+		),
+		items,
+	)
+}
+
+func newImportRecords(records []fileimport.RecordOutcome) tui.Screen {
+	return menus.NewScreen(
+		"Записи файла",
+		describeRecords(records),
+		[]menus.MenuItem{menus.NewPopItem("Назад", "Вернуться к проверке")},
+	)
+}
+
+func describePreview(preview fileimport.Preview) string {
+	var b strings.Builder
+	for _, entity := range importEntities {
+		fmt.Fprintf(
+			&b,
+			"%s: создать %d, обновить %d, дубликатов %d, ошибок %d, без ссылок %d\n",
+			entityTitle(entity),
+			preview.Count(entity, fileimport.OutcomeCreate),
+			preview.Count(entity, fileimport.OutcomeUpdate),
+			preview.Count(entity, fileimport.OutcomeDuplicate),
+			preview.Count(entity, fileimport.OutcomeInvalid),
+			preview.Count(entity, fileimport.OutcomeMissingReference),
+		)
+	}
+	if rounded := countWarnings(preview.Records); rounded > 0 {
+		fmt.Fprintf(&b, "\nДробные суммы округлены до целых: %d записей (см. подробности).\n", rounded)
+	}
+	if len(preview.Balances) > 0 {
+		b.WriteString("\n" + describeBalances(preview.Balances))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func describeBalances(checks []fileimport.BalanceCheck) string {
+	mismatches := 0
+	var b strings.Builder
+	for _, check := range checks {
+		line := fmt.Sprintf(
+			"%s: начальный %d, операции %+d, итог %d",
+			check.Name,
+			check.Opening,
+			check.Imported,
+			check.Balance,
+		)
+		if check.Mismatch() {
+			mismatches++
+			line += fmt.Sprintf(" — в файле %d, расхождение %+d", check.Stated, check.Balance-check.Stated)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	header := "Сверка балансов: расхождений нет."
+	if mismatches > 0 {
+		header = fmt.Sprintf("Сверка балансов: расхождений %d.", mismatches)
+	}
+	return header + "\n" + strings.TrimRight(b.String(), "\n")
+}
+
+func describeRecords(records []fileimport.RecordOutcome) string {
+	if len(records) == 0 {
+		return "Файл не содержит записей."
+	}
+
+	ordered := make([]fileimport.RecordOutcome, 0, len(records))
+	for _, record := range records {
+		if !recordAccepted(record.Outcome) {
+			ordered = append(ordered, record)
+		}
+	}
+	for _, record := range records {
+		if recordAccepted(record.Outcome) {
+			ordered = append(ordered, record)
+		}
+	}
+
+	var b strings.Builder
+	for idx, record := range ordered {
+		if idx == maxListedRecords {
+			fmt.Fprintf(&b, "… и ещё %d записей\n", len(ordered)-maxListedRecords)
+			break
+		}
+		line := fmt.Sprintf("%s %s «%s»: %s", entityTitle(record.Entity), record.ID, record.Label, outcomeLabel(record.Outcome))
+		if record.NewID != "" {
+			line += ", новый идентификатор " + record.NewID
+		}
+		if record.Err != nil {
+			line += " — " + describeImportError(record.Err)
+		}
+		if record.Warning != nil {
+			line += " (" + describeImportError(record.Warning) + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func countWarnings(records []fileimport.RecordOutcome) int {
+	count := 0
+	for _, record := range records {
+		if record.Warning != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func recordAccepted(outcome fileimport.Outcome) bool {
+	return outcome == fileimport.OutcomeCreate || outcome == fileimport.OutcomeUpdate
+}
+
+func entityTitle(entity fileimport.Entity) string {
+	switch entity {
+	case fileimport.EntityAccount:
+		return "Счета"
+	case fileimport.EntityCategory:
+		return "Категории"
+	case fileimport.EntityOperation:
+		return "Операции"
+	case fileimport.EntityView:
+		return "Представления"
+	default:
+		return string(entity)
+	}
+}
+
+func outcomeLabel(outcome fileimport.Outcome) string {
+	switch outcome {
+	case fileimport.OutcomeCreate:
+		return "создание"
+	case fileimport.OutcomeUpdate:
+		return "обновление"
+	case fileimport.OutcomeDuplicate:
+		return "дубликат"
+	case fileimport.OutcomeInvalid:
+		return "ошибка"
+	case fileimport.OutcomeMissingReference:
+		return "нет ссылки"
+	default:
+		return string(outcome)
+	}
+}
+
+func describeImportError(err error) string {
+	switch {
+	case errors.Is(err, fileimport.ErrEmptyID):
+		return "нет идентификатора"
+	case errors.Is(err, fileimport.ErrRepeatedID):
+		return "идентификатор повторяется в файле"
+	case errors.Is(err, fileimport.ErrExistingRecord):
+		return "запись с таким идентификатором уже есть"
+	case errors.Is(err, fileimport.ErrStoredNewer):
+		return "в приложении более новая версия"
+	case errors.Is(err, fileimport.ErrNoTimestamp):
+		return "нет времени изменения, сохранена запись из приложения"
+	case errors.Is(err, fileimport.ErrAmountRounded):
+		return "дробная сумма округлена до целого"
+	case errors.Is(err, fileimport.ErrMissingAccount):
+		return "счёт не найден ни в файле, ни в приложении"
+	case errors.Is(err, fileimport.ErrMissingCategory):
+		return "категория не найдена ни в файле, ни в приложении"
+	case errors.Is(err, domain.ErrInvalidBankAccount):
+		return "пустое название или отрицательный баланс у счёта-актива"
+	case errors.Is(err, domain.ErrInvalidCategory):
+		return "пустое название или неизвестный тип категории"
+	case errors.Is(err, domain.ErrInvalidOperation):
+		return "неизвестный тип, неположительная сумма или не указан счёт либо категория"
+	case errors.Is(err, domain.ErrInsufficientFunds):
+		return "недостаточно средств на счёте"
+	case errors.Is(err, domain.ErrOperationTypeMismatch):
+		return "тип операции не совпадает с типом категории"
+	case errors.Is(err, fileimport.ErrInvalidPeriod):
+		return "неизвестный вид периода или число дней не больше нуля"
+	case errors.Is(err, domain.ErrInvalidSavedView):
+		return "пустое название или некорректный период"
+	default:
+		return err.Error()
+	}
+}
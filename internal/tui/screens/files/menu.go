@@ -15,6 +15,14 @@ func NewMenu() tui.Screen {
 				return tui.Result{Push: newImportScreen(ctx)}
 			},
 		),
+		menus.NewActionItem(
+			"import_history",
+			"История импорта",
+			"Просмотреть выполненные импорты и откатить любой из них.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				return tui.Result{Push: loadImportHistory(ctx)}
+			},
+		),
 		menus.NewActionItem(
 			"export",
 			"Экспорт данных",
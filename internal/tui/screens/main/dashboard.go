@@ -0,0 +1,71 @@
+package mainmenu
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+var widgetStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("60")).
+	Padding(0, 1)
+
+type dashboard struct {
+	*menus.Screen
+	widget string
+}
+
+func newDashboard(screen *menus.Screen) *dashboard {
+	return &dashboard{Screen: screen}
+}
+
+func (d *dashboard) Init(ctx tui.ScreenContext) tea.Cmd {
+	d.refresh(ctx)
+	return d.Screen.Init(ctx)
+}
+
+func (d *dashboard) Resume(ctx tui.ScreenContext) tea.Cmd {
+	d.refresh(ctx)
+	return nil
+}
+
+func (d *dashboard) View() string {
+	if d.widget == "" {
+		return d.Screen.View()
+	}
+	return widgetStyle.Render(d.widget) + "\n\n" + d.Screen.View()
+}
+
+func (d *dashboard) refresh(ctx tui.ScreenContext) {
+	d.widget = ""
+	if ctx.AnalyticsCommands() == nil {
+		return
+	}
+
+	options := appanalytics.NetWorthOptions{End: time.Now(), Months: 2}
+	netWorth, err := ctx.AnalyticsCommands().NetWorth(options).Execute(ctx.Context())
+	if err != nil || len(netWorth.Points) == 0 {
+		return
+	}
+
+	change := netWorth.Net - netWorth.Points[0].Net
+	d.widget = fmt.Sprintf(
+		"Чистые активы: %d\nАктивы %d • кредиты %d • с начала месяца %+d",
+		netWorth.Net,
+		netWorth.Assets,
+		netWorth.Credit,
+		change,
+	)
+}
+
+var (
+	_ tui.Screen  = (*dashboard)(nil)
+	_ tui.Resumer = (*dashboard)(nil)
+)
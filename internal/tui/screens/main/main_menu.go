@@ -7,6 +7,7 @@ import (
 	categoriesmenu "kpo-hw-2/internal/tui/screens/categories"
 	filesmenu "kpo-hw-2/internal/tui/screens/files"
 	operationsmenu "kpo-hw-2/internal/tui/screens/operations"
+	reportsmenu "kpo-hw-2/internal/tui/screens/reports"
 )
 
 func New() tui.Screen {
@@ -22,6 +23,9 @@ func New() tui.Screen {
 		menus.NewActionItem("operations", "Операции", "Работа с финансовыми операциями", func(tui.ScreenContext, menus.Values) tui.Result {
 			return tui.Result{Push: operationsmenu.NewMenu()}
 		}),
+		menus.NewActionItem("reports", "Отчёты", "Аналитика по операциям за период.", func(tui.ScreenContext, menus.Values) tui.Result {
+			return tui.Result{Push: reportsmenu.NewMenu()}
+		}),
 		menus.NewActionItem("files", "Работа с файлами", "Экспорт и другие операции с файлами.", func(tui.ScreenContext, menus.Values) tui.Result {
 			return tui.Result{Push: filesmenu.NewMenu()}
 		}),
@@ -29,5 +33,5 @@ func New() tui.Screen {
 	}
 
 	screen = menus.NewScreen("Главное меню", "Выберите раздел для продолжения.", items)
-	return screen
+	return newDashboard(screen)
 }
@@ -1,6 +1,8 @@
 package operations
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -12,21 +14,20 @@ import (
 )
 
 const (
+	fieldFilterQuery     = "filter_query"
+	fieldFilterPeriod    = "filter_period"
 	fieldFilterStartDate = "filter_start_date"
 	fieldFilterEndDate   = "filter_end_date"
 	fieldFilterAccount   = "filter_account"
 	fieldFilterCategory  = "filter_category"
 	fieldFilterType      = "filter_type"
+	fieldFilterViewName  = "filter_view_name"
 )
 
 func NewFilter(accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
 	var screen *menus.Screen
 
-	accountOptions := make([]menus.SelectOption, 0, len(accounts)+1)
-	accountOptions = append(accountOptions, menus.SelectOption{
-		Label: "Все счета",
-		Value: "",
-	})
+	accountOptions := make([]menus.SelectOption, 0, len(accounts))
 	for _, account := range accounts {
 		accountOptions = append(accountOptions, menus.SelectOption{
 			Label: account.Name(),
@@ -34,11 +35,7 @@ func NewFilter(accounts []*domain.BankAccount, categories []*domain.Category) tu
 		})
 	}
 
-	categoryOptions := make([]menus.SelectOption, 0, len(categories)+1)
-	categoryOptions = append(categoryOptions, menus.SelectOption{
-		Label: "Все категории",
-		Value: "",
-	})
+	categoryOptions := make([]menus.SelectOption, 0, len(categories))
 	for _, category := range categories {
 		categoryOptions = append(categoryOptions, menus.SelectOption{
 			Label: category.Name(),
@@ -47,12 +44,27 @@ func NewFilter(accounts []*domain.BankAccount, categories []*domain.Category) tu
 	}
 
 	typeOptions := []menus.SelectOption{
-		{Label: "Все типы", Value: ""},
 		{Label: "Доход", Value: string(domain.OperationTypeIncome)},
 		{Label: "Расход", Value: string(domain.OperationTypeExpense)},
 	}
 
 	items := []menus.MenuItem{
+		menus.NewInputItem(
+			fieldFilterQuery,
+			"Запрос",
+			"Например: type:expense cat:такси,метро amount>=500 date:2026-09..2026-10 \"кофе\". Если заполнено, остальные поля не учитываются.",
+			menus.InputConfig{
+				Placeholder: "type:expense cat:такси amount>=500",
+				Width:       48,
+			},
+		),
+		menus.NewSelectItem(
+			fieldFilterPeriod,
+			"Период",
+			"Относительный период сохраняется в представлении и пересчитывается при открытии.",
+			periodOptions(),
+			menus.SelectConfig{InitialIndex: 0},
+		),
 		menus.NewInputItem(
 			fieldFilterStartDate,
 			"Дата начала",
@@ -69,113 +81,84 @@ func NewFilter(accounts []*domain.BankAccount, categories []*domain.Category) tu
 				Placeholder: "ГГГГ-ММ-ДД",
 			},
 		),
-		menus.NewSelectItem(
+		menus.NewMultiSelectItem(
 			fieldFilterAccount,
-			"Счёт",
-			"Выберите счёт или оставьте «Все счета».",
+			"Счета",
+			"Отметьте счета или оставьте пустым для всех; x — исключить отмеченные.",
 			accountOptions,
-			menus.SelectConfig{InitialIndex: 0},
+			menus.MultiSelectConfig{AllowExclude: true, EmptyLabel: "Все счета"},
 		),
-		menus.NewSelectItem(
+		menus.NewMultiSelectItem(
 			fieldFilterCategory,
-			"Категория",
-			"Выберите категорию или оставьте «Все категории».",
+			"Категории",
+			"Отметьте категории или оставьте пустым для всех; x — исключить отмеченные.",
 			categoryOptions,
-			menus.SelectConfig{InitialIndex: 0},
+			menus.MultiSelectConfig{AllowExclude: true, EmptyLabel: "Все категории"},
 		),
-		menus.NewSelectItem(
+		menus.NewMultiSelectItem(
 			fieldFilterType,
 			"Тип операции",
-			"Выберите приход или расход, либо оставьте «Все типы».",
+			"Отметьте приход и/или расход, либо оставьте пустым для всех типов.",
 			typeOptions,
-			menus.SelectConfig{InitialIndex: 0},
+			menus.MultiSelectConfig{AllowExclude: true, EmptyLabel: "Все типы"},
 		),
 		menus.NewActionItem(
 			"apply",
 			"Показать операции",
 			"Применить фильтр и перейти к списку.",
 			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
-				startStr := strings.TrimSpace(values[fieldFilterStartDate])
-				endStr := strings.TrimSpace(values[fieldFilterEndDate])
-				accountID := strings.TrimSpace(values[fieldFilterAccount])
-				categoryID := strings.TrimSpace(values[fieldFilterCategory])
-				operationType := strings.TrimSpace(values[fieldFilterType])
-
-				var startDate, endDate *time.Time
-				hasError := false
-
-				if startStr != "" {
-					parsed, err := time.Parse(dateLayout, startStr)
-					if err != nil {
-						screen.SetFieldError(fieldFilterStartDate, "используйте формат ГГГГ-ММ-ДД")
-						hasError = true
-					} else {
-						screen.SetFieldError(fieldFilterStartDate, "")
-						startDate = &parsed
-					}
-				} else {
-					screen.SetFieldError(fieldFilterStartDate, "")
-				}
-
-				if endStr != "" {
-					parsed, err := time.Parse(dateLayout, endStr)
-					if err != nil {
-						screen.SetFieldError(fieldFilterEndDate, "используйте формат ГГГГ-ММ-ДД")
-						hasError = true
-					} else {
-						screen.SetFieldError(fieldFilterEndDate, "")
-						endDate = &parsed
-					}
-				} else {
-					screen.SetFieldError(fieldFilterEndDate, "")
-				}
-
-				if startDate != nil && endDate != nil && startDate.After(*endDate) {
-					screen.SetFieldError(fieldFilterStartDate, "дата начала должна предшествовать окончанию")
-					screen.SetFieldError(fieldFilterEndDate, "дата окончания должна следовать после начала")
-					hasError = true
-				}
-
-				filter := query.NewOperationFilter()
-
-				if accountID != "" {
-					filter = filter.ForAccount(domain.ID(accountID))
-				}
-
-				if categoryID != "" {
-					filter = filter.ForCategory(domain.ID(categoryID))
-				}
-
-				if operationType != "" {
-					filter = filter.OfType(domain.OperationType(operationType))
-				}
-
-				if startDate != nil && endDate != nil {
-					filter = filter.Between(*startDate, *endDate)
-				} else if startDate != nil {
-					filter = filter.From(*startDate)
-				} else if endDate != nil {
-					filter = filter.To(*endDate)
-				}
-
-				if hasError {
+				filter, ok := resolveFilter(ctx, screen, values)
+				if !ok {
 					return tui.Result{}
 				}
 
-				listCmd := ctx.OperationCommands().List(filter)
-				operations, err := listCmd.Execute(ctx.Context())
-				if err != nil {
-					screen.SetFieldError(fieldFilterStartDate, err.Error())
+				return showFiltered(ctx, screen, fieldFilterQuery, filter, accounts, categories)
+			},
+		),
+		menus.NewInputItem(
+			fieldFilterViewName,
+			"Название представления",
+			"Укажите имя, чтобы сохранить текущие параметры фильтра.",
+			menus.InputConfig{
+				Placeholder: "Например, Такси за месяц",
+			},
+		),
+		menus.NewActionItem(
+			"save_view",
+			"Сохранить как представление",
+			"Сохранить фильтр под указанным названием.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				name := strings.TrimSpace(values[fieldFilterViewName])
+				if menus.ApplyValidation(screen, fieldFilterViewName, name, validateViewName) {
 					return tui.Result{}
 				}
 
-				totals, totalsErr := computeTotals(ctx, operations)
-				if totalsErr != nil {
-					screen.SetFieldError(fieldFilterStartDate, totalsErr.Error())
+				var view *query.SavedView
+				var err error
+				if expression := strings.TrimSpace(values[fieldFilterQuery]); expression != "" {
+					view, err = ctx.ViewCommands().CreateFromQuery(name, expression).Execute(ctx.Context())
+					var parseErr *query.ParseError
+					if errors.As(err, &parseErr) {
+						screen.SetFieldError(fieldFilterQuery, DescribeQueryError(err))
+						return tui.Result{}
+					}
+				} else {
+					criteria, period, ok := readCriteria(screen, values)
+					if !ok {
+						return tui.Result{}
+					}
+					view, err = ctx.ViewCommands().Create(name, criteria, period).Execute(ctx.Context())
+				}
+				if err != nil {
+					screen.SetFieldError(fieldFilterViewName, err.Error())
 					return tui.Result{}
 				}
 
-				return tui.Result{Push: NewList(filter, operations, accounts, categories, totals)}
+				screen.SetValue(fieldFilterViewName, "")
+				return tui.Result{Push: noticeScreen(
+					"Представление сохранено",
+					fmt.Sprintf("Представление «%s» доступно в меню операций.", view.Name()),
+				)}
 			},
 		),
 		menus.NewPopItem("Назад", "Вернуться в меню операций"),
@@ -190,16 +173,161 @@ func NewFilter(accounts []*domain.BankAccount, categories []*domain.Category) tu
 	return screen
 }
 
-func computeTotals(ctx tui.ScreenContext, operations []*domain.Operation) (appanalytics.Totals, error) {
+func validateViewName(value string) error {
+	return menus.ValidateNonEmpty(value, "укажите название представления")
+}
+
+func resolveFilter(ctx tui.ScreenContext, screen *menus.Screen, values menus.Values) (query.OperationFilter, bool) {
+	if expression := strings.TrimSpace(values[fieldFilterQuery]); expression != "" {
+		parseCmd := ctx.OperationCommands().ParseQuery(expression)
+		filter, err := parseCmd.Execute(ctx.Context())
+		if err != nil {
+			screen.SetFieldError(fieldFilterQuery, DescribeQueryError(err))
+			return query.OperationFilter{}, false
+		}
+		screen.SetFieldError(fieldFilterQuery, "")
+		return filter, true
+	}
+	screen.SetFieldError(fieldFilterQuery, "")
+
+	criteria, period, ok := readCriteria(screen, values)
+	if !ok {
+		return query.OperationFilter{}, false
+	}
+
+	return criteria.WithinPeriod(period, time.Now()), true
+}
+
+func readCriteria(screen *menus.Screen, values menus.Values) (query.OperationFilter, query.PeriodSpec, bool) {
+	startStr := strings.TrimSpace(values[fieldFilterStartDate])
+	endStr := strings.TrimSpace(values[fieldFilterEndDate])
+	preset := PeriodFromPreset(values[fieldFilterPeriod])
+
+	var startDate, endDate *time.Time
+	hasError := false
+
+	if startStr != "" {
+		parsed, err := time.Parse(dateLayout, startStr)
+		if err != nil {
+			screen.SetFieldError(fieldFilterStartDate, "используйте формат ГГГГ-ММ-ДД")
+			hasError = true
+		} else {
+			screen.SetFieldError(fieldFilterStartDate, "")
+			startDate = &parsed
+		}
+	} else {
+		screen.SetFieldError(fieldFilterStartDate, "")
+	}
+
+	if endStr != "" {
+		parsed, err := time.Parse(dateLayout, endStr)
+		if err != nil {
+			screen.SetFieldError(fieldFilterEndDate, "используйте формат ГГГГ-ММ-ДД")
+			hasError = true
+		} else {
+			screen.SetFieldError(fieldFilterEndDate, "")
+			endDate = &parsed
+		}
+	} else {
+		screen.SetFieldError(fieldFilterEndDate, "")
+	}
+
+	if startDate != nil && endDate != nil && startDate.After(*endDate) {
+		screen.SetFieldError(fieldFilterStartDate, "дата начала должна предшествовать окончанию")
+		screen.SetFieldError(fieldFilterEndDate, "дата окончания должна следовать после начала")
+		hasError = true
+	}
+
+	if preset.IsRelative() && (startStr != "" || endStr != "") {
+		screen.SetFieldError(fieldFilterPeriod, "очистите даты или выберите произвольный период")
+		hasError = true
+	} else {
+		screen.SetFieldError(fieldFilterPeriod, "")
+	}
+
+	if hasError {
+		return query.OperationFilter{}, query.PeriodSpec{}, false
+	}
+
+	period := preset
+	if !preset.IsRelative() {
+		period = query.CustomPeriod(startDate, endDate)
+	}
+
+	criteria := query.NewOperationFilter().
+		WithAccounts(idSelector(values[fieldFilterAccount])).
+		WithCategories(idSelector(values[fieldFilterCategory])).
+		WithTypes(typeSelector(values[fieldFilterType]))
+
+	return criteria, period, true
+}
+
+func showFiltered(
+	ctx tui.ScreenContext,
+	screen *menus.Screen,
+	errorField string,
+	filter query.OperationFilter,
+	accounts []*domain.BankAccount,
+	categories []*domain.Category,
+) tui.Result {
+	listCmd := ctx.OperationCommands().List(filter)
+	operations, err := listCmd.Execute(ctx.Context())
+	if err != nil {
+		screen.SetFieldError(errorField, err.Error())
+		return tui.Result{}
+	}
+
+	totals, totalsErr := computeTotals(ctx, filter)
+	if totalsErr != nil {
+		screen.SetFieldError(errorField, totalsErr.Error())
+		return tui.Result{}
+	}
+
+	return tui.Result{Push: NewList(filter, operations, accounts, categories, totals)}
+}
+
+func DescribeQueryError(err error) string {
+	var parseErr *query.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Sprintf("позиция %d: %s", parseErr.Position, parseErr.Message)
+	}
+	return err.Error()
+}
+
+func computeTotals(ctx tui.ScreenContext, filter query.OperationFilter) (appanalytics.Totals, error) {
 	cmdService := ctx.AnalyticsCommands()
 	if cmdService == nil {
 		return appanalytics.Totals{}, nil
 	}
 
-	cmd := cmdService.NetTotals(operations)
+	cmd := cmdService.Totals(filter)
 	if cmd == nil {
 		return appanalytics.Totals{}, nil
 	}
 
 	return cmd.Execute(ctx.Context())
 }
+
+func idSelector(value string) query.Selector[domain.ID] {
+	raw, exclude := menus.ParseMultiValue(value)
+	ids := make([]domain.ID, 0, len(raw))
+	for _, v := range raw {
+		ids = append(ids, domain.ID(v))
+	}
+	if exclude {
+		return query.Exclude(ids...)
+	}
+	return query.Include(ids...)
+}
+
+func typeSelector(value string) query.Selector[domain.OperationType] {
+	raw, exclude := menus.ParseMultiValue(value)
+	types := make([]domain.OperationType, 0, len(raw))
+	for _, v := range raw {
+		types = append(types, domain.OperationType(v))
+	}
+	if exclude {
+		return query.Exclude(types...)
+	}
+	return query.Include(types...)
+}
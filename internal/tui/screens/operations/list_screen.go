@@ -116,25 +116,8 @@ func buildOperationDescription(op *domain.Operation, accountNames map[domain.ID]
 func buildFilterIntro(filter query.OperationFilter, accountNames map[domain.ID]string, categoryNames map[domain.ID]string) string {
 	var parts []string
 
-	if accID := filter.AccountID(); accID != "" {
-		if name, ok := accountNames[accID]; ok {
-			parts = append(parts, fmt.Sprintf("Счёт: %s", name))
-		} else {
-			parts = append(parts, fmt.Sprintf("Счёт: %s", accID.String()))
-		}
-	} else {
-		parts = append(parts, "Все счета")
-	}
-
-	if catID := filter.CategoryID(); catID != "" {
-		if name, ok := categoryNames[catID]; ok {
-			parts = append(parts, fmt.Sprintf("Категория: %s", name))
-		} else {
-			parts = append(parts, fmt.Sprintf("Категория: %s", catID.String()))
-		}
-	} else {
-		parts = append(parts, "Все категории")
-	}
+	parts = append(parts, describeIDSelector(filter.Accounts(), accountNames, "Счета", "Все счета"))
+	parts = append(parts, describeIDSelector(filter.Categories(), categoryNames, "Категории", "Все категории"))
 
 	if from, to := filter.Period(); from != nil || to != nil {
 		var periodParts []string
@@ -149,18 +132,60 @@ func buildFilterIntro(filter query.OperationFilter, accountNames map[domain.ID]s
 		parts = append(parts, "Без ограничения по датам")
 	}
 
-	switch typ := filter.Type(); typ {
-	case domain.OperationTypeIncome:
-		parts = append(parts, "Тип: доход")
-	case domain.OperationTypeExpense:
-		parts = append(parts, "Тип: расход")
-	default:
+	if minAmount, maxAmount := filter.AmountRange(); minAmount != nil || maxAmount != nil {
+		var amountParts []string
+		if minAmount != nil {
+			amountParts = append(amountParts, fmt.Sprintf("от %d", *minAmount))
+		}
+		if maxAmount != nil {
+			amountParts = append(amountParts, fmt.Sprintf("до %d", *maxAmount))
+		}
+		parts = append(parts, "Сумма "+strings.Join(amountParts, " "))
+	}
+
+	if texts := filter.Texts(); len(texts) > 0 {
+		parts = append(parts, fmt.Sprintf("Описание: «%s»", strings.Join(texts, "», «")))
+	}
+
+	types := filter.Types()
+	if types.IsEmpty() {
 		parts = append(parts, "Тип: все операции")
+	} else {
+		labels := make([]string, 0, len(types.Values()))
+		for _, typ := range types.Values() {
+			labels = append(labels, readableType(typ))
+		}
+		parts = append(parts, "Тип: "+selectorLabel(labels, types.IsExclude()))
 	}
 
 	return strings.Join(parts, " • ")
 }
 
+func describeIDSelector(selector query.Selector[domain.ID], names map[domain.ID]string, title, all string) string {
+	if selector.IsEmpty() {
+		return all
+	}
+
+	labels := make([]string, 0, len(selector.Values()))
+	for _, id := range selector.Values() {
+		if name, ok := names[id]; ok {
+			labels = append(labels, name)
+		} else {
+			labels = append(labels, id.String())
+		}
+	}
+
+	return fmt.Sprintf("%s: %s", title, selectorLabel(labels, selector.IsExclude()))
+}
+
+func selectorLabel(labels []string, exclude bool) string {
+	joined := strings.Join(labels, ", ")
+	if exclude {
+		return "кроме " + joined
+	}
+	return joined
+}
+
 func buildTotalsSummary(totals appanalytics.Totals) string {
 	return fmt.Sprintf(
 		"Всего доходов: %d • Всего расходов: %d • Разница: %+d",
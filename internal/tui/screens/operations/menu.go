@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
 	"kpo-hw-2/internal/tui"
 	"kpo-hw-2/internal/tui/menus"
 )
@@ -25,6 +27,22 @@ func NewMenu() tui.Screen {
 
 			return tui.Result{Push: NewFilter(accounts, categories)}
 		}),
+		menus.NewActionItem("views", "Сохранённые представления", "Открыть операции по сохранённому фильтру.", func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+			views, accounts, categories, err := loadViewData(ctx)
+			if err != nil {
+				return tui.Result{Push: errorScreen("Ошибка", err.Error())}
+			}
+
+			return tui.Result{Push: NewViews(views, accounts, categories)}
+		}),
+		menus.NewActionItem("manage_views", "Управление представлениями", "Просмотреть и удалить сохранённые фильтры.", func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+			views, accounts, categories, err := loadViewData(ctx)
+			if err != nil {
+				return tui.Result{Push: errorScreen("Ошибка", err.Error())}
+			}
+
+			return tui.Result{Push: NewViewManagement(views, accounts, categories)}
+		}),
 		menus.NewActionItem("create", "Добавить операцию", "Создать новую финансовую операцию.", func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
 			accountCmd := ctx.AccountCommands().List()
 			accounts, err := accountCmd.Execute(ctx.Context())
@@ -59,6 +77,28 @@ func NewMenu() tui.Screen {
 	return menus.NewScreen("Операции", "Выберите действие.", items)
 }
 
+func loadViewData(ctx tui.ScreenContext) ([]*query.SavedView, []*domain.BankAccount, []*domain.Category, error) {
+	viewCmd := ctx.ViewCommands().List()
+	views, err := viewCmd.Execute(ctx.Context())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Не удалось получить представления:\n%s", err.Error())
+	}
+
+	accountCmd := ctx.AccountCommands().List()
+	accounts, err := accountCmd.Execute(ctx.Context())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Не удалось получить список счетов:\n%s", err.Error())
+	}
+
+	categoryCmd := ctx.CategoryCommands().List("")
+	categories, err := categoryCmd.Execute(ctx.Context())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Не удалось получить список категорий:\n%s", err.Error())
+	}
+
+	return views, accounts, categories, nil
+}
+
 func errorScreen(title, message string) tui.Screen {
 	return menus.NewScreen(
 		title,
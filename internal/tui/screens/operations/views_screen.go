@@ -0,0 +1,199 @@
+package operations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+func NewViews(views []*query.SavedView, accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	accountNames, categoryNames := namesByID(accounts, categories)
+
+	items := make([]menus.MenuItem, 0, len(views)+1)
+	for _, view := range views {
+		view := view
+		items = append(items, menus.NewActionItem(
+			view.ID().String(),
+			view.Name(),
+			describeView(view, accountNames, categoryNames),
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				resolveCmd := ctx.ViewCommands().Resolve(view.ID())
+				filter, err := resolveCmd.Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{Push: errorScreen("Ошибка", fmt.Sprintf("Не удалось открыть представление:\n%s", err.Error()))}
+				}
+
+				listCmd := ctx.OperationCommands().List(filter)
+				operations, err := listCmd.Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{Push: errorScreen("Ошибка", fmt.Sprintf("Не удалось получить операции:\n%s", err.Error()))}
+				}
+
+				totals, err := computeTotals(ctx, filter)
+				if err != nil {
+					return tui.Result{Push: errorScreen("Ошибка", fmt.Sprintf("Не удалось посчитать итоги:\n%s", err.Error()))}
+				}
+
+				return tui.Result{Push: NewList(filter, operations, accounts, categories, totals)}
+			},
+		))
+	}
+
+	items = append(items, menus.NewPopItem("Назад", "Вернуться в меню операций"))
+
+	return menus.NewScreen(
+		"Сохранённые представления",
+		"Выберите представление, чтобы открыть список операций.",
+		items,
+	).WithEmptyMessage("Представления ещё не сохранены. Создайте их на экране фильтра.")
+}
+
+func NewViewManagement(views []*query.SavedView, accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	accountNames, categoryNames := namesByID(accounts, categories)
+
+	items := make([]menus.MenuItem, 0, len(views)+1)
+	for _, view := range views {
+		view := view
+		items = append(items, menus.NewActionItem(
+			view.ID().String(),
+			view.Name(),
+			describeView(view, accountNames, categoryNames),
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Replace: newViewDetails(view, accounts, categories)}
+			},
+		))
+	}
+
+	items = append(items, menus.NewPopItem("Назад", "Вернуться в меню операций"))
+
+	return menus.NewScreen(
+		"Управление представлениями",
+		"Выберите представление для просмотра или удаления.",
+		items,
+	).WithEmptyMessage("Представления ещё не сохранены.")
+}
+
+func newViewDetails(view *query.SavedView, accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	accountNames, categoryNames := namesByID(accounts, categories)
+
+	items := []menus.MenuItem{
+		menus.NewActionItem(
+			"delete",
+			"Удалить представление",
+			"Удаление не затрагивает операции.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				deleteCmd := ctx.ViewCommands().Delete(view.ID())
+				if _, err := deleteCmd.Execute(ctx.Context()); err != nil {
+					return tui.Result{Push: errorScreen("Ошибка", fmt.Sprintf("Не удалось удалить представление:\n%s", err.Error()))}
+				}
+
+				listCmd := ctx.ViewCommands().List()
+				views, err := listCmd.Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{}
+				}
+				return tui.Result{Replace: NewViewManagement(views, accounts, categories)}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться в меню операций"),
+	}
+
+	return menus.NewScreen(
+		fmt.Sprintf("Представление: %s", view.Name()),
+		describeView(view, accountNames, categoryNames),
+		items,
+	)
+}
+
+func namesByID(accounts []*domain.BankAccount, categories []*domain.Category) (map[domain.ID]string, map[domain.ID]string) {
+	accountNames := make(map[domain.ID]string, len(accounts))
+	for _, acc := range accounts {
+		accountNames[acc.ID()] = acc.Name()
+	}
+
+	categoryNames := make(map[domain.ID]string, len(categories))
+	for _, cat := range categories {
+		categoryNames[cat.ID()] = cat.Name()
+	}
+
+	return accountNames, categoryNames
+}
+
+func describeView(view *query.SavedView, accountNames, categoryNames map[domain.ID]string) string {
+	intro := buildFilterIntro(view.Filter(time.Now()), accountNames, categoryNames)
+	if period := view.Period(); period.IsRelative() {
+		intro = fmt.Sprintf("%s\nПериод: %s", intro, PeriodLabel(period))
+	}
+	return intro
+}
+
+func periodOptions() []menus.SelectOption {
+	return append([]menus.SelectOption{{Label: "Произвольный (даты ниже)", Value: ""}}, PeriodPresets()...)
+}
+
+func PeriodPresets() []menus.SelectOption {
+	return []menus.SelectOption{
+		{Label: "Сегодня", Value: string(query.PeriodToday)},
+		{Label: "Эта неделя", Value: string(query.PeriodThisWeek)},
+		{Label: "Этот месяц", Value: string(query.PeriodThisMonth)},
+		{Label: "Прошлый месяц", Value: string(query.PeriodLastMonth)},
+		{Label: "Этот год", Value: string(query.PeriodThisYear)},
+		{Label: "Прошлый год", Value: string(query.PeriodLastYear)},
+		{Label: "Последние 7 дней", Value: lastDaysPreset(7)},
+		{Label: "Последние 30 дней", Value: lastDaysPreset(30)},
+		{Label: "Последние 90 дней", Value: lastDaysPreset(90)},
+	}
+}
+
+func lastDaysPreset(days int) string {
+	return fmt.Sprintf("%s:%d", query.PeriodLastDays, days)
+}
+
+func PeriodFromPreset(value string) query.PeriodSpec {
+	value = strings.TrimSpace(value)
+	if raw, ok := strings.CutPrefix(value, string(query.PeriodLastDays)+":"); ok {
+		days, err := strconv.Atoi(raw)
+		if err != nil {
+			return query.AllTime()
+		}
+		return query.LastDays(days)
+	}
+	return query.RelativePeriod(query.PeriodKind(value))
+}
+
+func PeriodLabel(period query.PeriodSpec) string {
+	switch period.Kind() {
+	case query.PeriodToday:
+		return "сегодня"
+	case query.PeriodThisWeek:
+		return "эта неделя"
+	case query.PeriodThisMonth:
+		return "этот месяц"
+	case query.PeriodLastMonth:
+		return "прошлый месяц"
+	case query.PeriodThisYear:
+		return "этот год"
+	case query.PeriodLastYear:
+		return "прошлый год"
+	case query.PeriodLastDays:
+		return fmt.Sprintf("последние %d дн.", period.Days())
+	default:
+		return "без ограничения"
+	}
+}
+
+func noticeScreen(title, message string) tui.Screen {
+	return menus.NewScreen(
+		title,
+		message,
+		[]menus.MenuItem{
+			menus.NewPopItem("Назад", "Вернуться к фильтру"),
+		},
+	)
+}
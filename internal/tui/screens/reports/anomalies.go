@@ -0,0 +1,148 @@
+package reports
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const (
+	fieldAnomalyLookback  = "anomaly_lookback"
+	fieldAnomalyThreshold = "anomaly_threshold"
+)
+
+func newAnomaliesForm(accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	var screen *menus.Screen
+
+	names := referenceNames(accounts, categories)
+
+	items := []menus.MenuItem{
+		menus.NewInputItem(
+			fieldAnomalyLookback,
+			"Окно, мес.",
+			"За сколько месяцев собирать историю для сравнения.",
+			menus.InputConfig{Initial: "12"},
+		),
+		menus.NewInputItem(
+			fieldAnomalyThreshold,
+			"Чувствительность",
+			"Порог робастной оценки (медиана/MAD): меньше — больше срабатываний.",
+			menus.InputConfig{Initial: "3.5"},
+		),
+		menus.NewActionItem(
+			"build",
+			"Найти аномалии",
+			"Проверить расходы по категориям и отдельным операциям.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				hasError := false
+
+				lookback, err := strconv.Atoi(strings.TrimSpace(values[fieldAnomalyLookback]))
+				if err != nil || lookback < 2 || lookback > 120 {
+					screen.SetFieldError(fieldAnomalyLookback, "укажите число месяцев от 2 до 120")
+					hasError = true
+				} else {
+					screen.SetFieldError(fieldAnomalyLookback, "")
+				}
+
+				threshold, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(values[fieldAnomalyThreshold]), ",", "."), 64)
+				if err != nil || threshold <= 0 {
+					screen.SetFieldError(fieldAnomalyThreshold, "укажите положительное число")
+					hasError = true
+				} else {
+					screen.SetFieldError(fieldAnomalyThreshold, "")
+				}
+
+				if hasError {
+					return tui.Result{}
+				}
+
+				anomalies, err := ctx.AnalyticsCommands().Anomalies(appanalytics.AnomalyOptions{
+					Now:            time.Now(),
+					LookbackMonths: lookback,
+					Threshold:      threshold,
+				}).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldAnomalyLookback, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Аномалии", renderAnomalies(anomalies, names))}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Аномалии",
+		"Расходы, заметно превышающие обычные значения для категории.",
+		items,
+	)
+
+	return screen
+}
+
+func renderAnomalies(anomalies []appanalytics.Anomaly, names map[domain.ID]string) string {
+	if len(anomalies) == 0 {
+		return okStyle.Render("Необычных расходов не найдено.")
+	}
+
+	var months, operations []string
+	for _, anomaly := range anomalies {
+		switch anomaly.Kind {
+		case appanalytics.AnomalyCategoryMonth:
+			months = append(months, fmt.Sprintf(
+				"%s • %s — за месяц %d, обычно %.0f (%s, оценка %.1f по %d мес.)",
+				anomaly.Date.Format("01.2006"),
+				nameOf(names, anomaly.CategoryID),
+				anomaly.Amount,
+				anomaly.Median,
+				describeRatio(anomaly.Ratio),
+				anomaly.Score,
+				anomaly.Samples,
+			))
+		case appanalytics.AnomalyOperation:
+			title := strings.TrimSpace(anomaly.Description)
+			if title == "" {
+				title = "без описания"
+			}
+			operations = append(operations, fmt.Sprintf(
+				"%s • %s • «%s» %d — обычно %.0f (%s, оценка %.1f по %d оп.) • %s",
+				anomaly.Date.Format(forecastDateLayout),
+				nameOf(names, anomaly.CategoryID),
+				title,
+				anomaly.Amount,
+				anomaly.Median,
+				describeRatio(anomaly.Ratio),
+				anomaly.Score,
+				anomaly.Samples,
+				nameOf(names, anomaly.AccountID),
+			))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Найдено: %d\n", len(anomalies))
+	if len(months) > 0 {
+		b.WriteString("\n" + headingStyle.Render("Месяцы с необычными расходами") + "\n")
+		b.WriteString(alertStyle.Render(strings.Join(months, "\n")) + "\n")
+	}
+	if len(operations) > 0 {
+		b.WriteString("\n" + headingStyle.Render("Необычно крупные операции") + "\n")
+		b.WriteString(strings.Join(operations, "\n"))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func describeRatio(ratio float64) string {
+	if math.IsInf(ratio, 1) {
+		return "обычно расходов нет"
+	}
+	return fmt.Sprintf("в %.1f раза больше", ratio)
+}
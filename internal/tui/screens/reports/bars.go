@@ -0,0 +1,41 @@
+package reports
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const barWidth = 30
+
+var (
+	incomeBarStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	expenseBarStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	emptyBarStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	headingStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("111")).Bold(true)
+)
+
+func renderBar(ratio float64, width int, style lipgloss.Style) string {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+
+	filled := int(math.Round(ratio * float64(width)))
+	if filled == 0 && ratio > 0 {
+		filled = 1
+	}
+
+	return style.Render(strings.Repeat("█", filled)) + emptyBarStyle.Render(strings.Repeat("░", width-filled))
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,110 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const (
+	fieldBreakdownDimension = "breakdown_dimension"
+	breakdownLabelWidth     = 20
+)
+
+func newBreakdownForm(accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	var screen *menus.Screen
+
+	names := referenceNames(accounts, categories)
+
+	items := []menus.MenuItem{
+		periodItem(query.PeriodThisMonth),
+		menus.NewSelectItem(
+			fieldBreakdownDimension,
+			"Разрез",
+			"Группировать операции по категориям или по счетам.",
+			[]menus.SelectOption{
+				{Label: "Категории", Value: string(appanalytics.ByCategory)},
+				{Label: "Счета", Value: string(appanalytics.ByAccount)},
+			},
+			menus.SelectConfig{InitialIndex: 0},
+		),
+		queryItem(),
+		menus.NewActionItem(
+			"build",
+			"Построить отчёт",
+			"Посчитать суммы и доли за выбранный период.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				filter, ok := resolveReportFilter(ctx, screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				dimension := appanalytics.Dimension(values[fieldBreakdownDimension])
+				breakdown, err := ctx.AnalyticsCommands().Breakdown(filter, dimension).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldReportQuery, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Структура доходов и расходов", renderBreakdown(breakdown, names))}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Структура доходов и расходов",
+		"Выберите период и разрез отчёта.",
+		items,
+	)
+
+	return screen
+}
+
+func renderBreakdown(breakdown appanalytics.Breakdown, names map[domain.ID]string) string {
+	if len(breakdown.Income) == 0 && len(breakdown.Expense) == 0 {
+		return "Операции за выбранный период не найдены."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Доходы: %d • Расходы: %d • Итого: %+d\n", breakdown.Totals.Income, breakdown.Totals.Expense, breakdown.Totals.Delta)
+
+	writeBreakdownSection(&b, "Расходы", breakdown.Expense, names, expenseBarStyle)
+	writeBreakdownSection(&b, "Доходы", breakdown.Income, names, incomeBarStyle)
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeBreakdownSection(
+	b *strings.Builder,
+	heading string,
+	entries []appanalytics.BreakdownEntry,
+	names map[domain.ID]string,
+	style lipgloss.Style,
+) {
+	if len(entries) == 0 {
+		return
+	}
+
+	b.WriteString("\n" + headingStyle.Render(heading) + "\n")
+	for _, entry := range entries {
+		name := nameOf(names, entry.Key)
+		fmt.Fprintf(
+			b,
+			"%-*s %s %5.1f%% %10d  %d оп.\n",
+			breakdownLabelWidth,
+			truncate(name, breakdownLabelWidth),
+			renderBar(entry.Share, barWidth, style),
+			entry.Share*100,
+			entry.Amount,
+			entry.Count,
+		)
+	}
+}
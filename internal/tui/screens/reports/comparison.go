@@ -0,0 +1,210 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const (
+	fieldCompareMode = "compare_mode"
+	fieldCompareType = "compare_type"
+
+	compareMonthPrevious     = "month_previous"
+	compareMonthLastYear     = "month_last_year"
+	compareLastMonthPrevious = "last_month_previous"
+	compareYearPrevious      = "year_previous"
+
+	compareNameWidth = 20
+)
+
+type comparePeriods struct {
+	currentLabel  string
+	previousLabel string
+	current       [2]time.Time
+	previous      [2]time.Time
+}
+
+func newComparisonForm(accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	var screen *menus.Screen
+
+	names := referenceNames(accounts, categories)
+
+	items := []menus.MenuItem{
+		menus.NewSelectItem(
+			fieldCompareMode,
+			"Сравнение",
+			"Какие периоды сравнивать.",
+			[]menus.SelectOption{
+				{Label: "Этот месяц и прошлый", Value: compareMonthPrevious},
+				{Label: "Этот месяц и он же год назад", Value: compareMonthLastYear},
+				{Label: "Прошлый месяц и позапрошлый", Value: compareLastMonthPrevious},
+				{Label: "Этот год и прошлый", Value: compareYearPrevious},
+			},
+			menus.SelectConfig{InitialIndex: 0},
+		),
+		menus.NewSelectItem(
+			fieldCompareType,
+			"Тип операций",
+			"Сравнивать расходы, доходы или все категории.",
+			[]menus.SelectOption{
+				{Label: "Расходы", Value: string(domain.OperationTypeExpense)},
+				{Label: "Доходы", Value: string(domain.OperationTypeIncome)},
+				{Label: "Все", Value: ""},
+			},
+			menus.SelectConfig{InitialIndex: 0},
+		),
+		queryItem(),
+		menus.NewActionItem(
+			"build",
+			"Сравнить",
+			"Показать изменения по категориям, начиная с наибольшего роста.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				base, ok := parseReportQuery(ctx, screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+				if from, to := base.Period(); from != nil || to != nil {
+					screen.SetFieldError(fieldReportQuery, "периоды задаются выше — уберите date: из запроса")
+					return tui.Result{}
+				}
+				if typ := values[fieldCompareType]; typ != "" {
+					if !base.Types().IsEmpty() {
+						screen.SetFieldError(fieldReportQuery, "тип уже выбран выше — уберите type: из запроса")
+						return tui.Result{}
+					}
+					base = base.OfType(domain.OperationType(typ))
+				}
+
+				periods := resolveComparePeriods(values[fieldCompareMode], time.Now())
+				current := base.Between(periods.current[0], periods.current[1])
+				previous := base.Between(periods.previous[0], periods.previous[1])
+
+				comparison, err := ctx.AnalyticsCommands().Compare(current, previous).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldReportQuery, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Сравнение периодов", renderComparison(comparison, periods, names))}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Сравнение периодов",
+		"Выберите периоды и тип операций.",
+		items,
+	)
+
+	return screen
+}
+
+func resolveComparePeriods(mode string, now time.Time) comparePeriods {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	year := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	span := func(from time.Time, months int) [2]time.Time {
+		return [2]time.Time{from, from.AddDate(0, months, 0).Add(-time.Nanosecond)}
+	}
+
+	switch mode {
+	case compareMonthLastYear:
+		previous := month.AddDate(-1, 0, 0)
+		return comparePeriods{
+			currentLabel:  month.Format("01.2006"),
+			previousLabel: previous.Format("01.2006"),
+			current:       span(month, 1),
+			previous:      span(previous, 1),
+		}
+	case compareLastMonthPrevious:
+		current := month.AddDate(0, -1, 0)
+		previous := month.AddDate(0, -2, 0)
+		return comparePeriods{
+			currentLabel:  current.Format("01.2006"),
+			previousLabel: previous.Format("01.2006"),
+			current:       span(current, 1),
+			previous:      span(previous, 1),
+		}
+	case compareYearPrevious:
+		previous := year.AddDate(-1, 0, 0)
+		return comparePeriods{
+			currentLabel:  year.Format("2006"),
+			previousLabel: previous.Format("2006"),
+			current:       span(year, 12),
+			previous:      span(previous, 12),
+		}
+	default:
+		previous := month.AddDate(0, -1, 0)
+		return comparePeriods{
+			currentLabel:  month.Format("01.2006"),
+			previousLabel: previous.Format("01.2006"),
+			current:       span(month, 1),
+			previous:      span(previous, 1),
+		}
+	}
+}
+
+func renderComparison(comparison appanalytics.Comparison, periods comparePeriods, names map[domain.ID]string) string {
+	if len(comparison.Entries) == 0 {
+		return "Операции за сравниваемые периоды не найдены."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"%s: доходы %d, расходы %d • %s: доходы %d, расходы %d\n\n",
+		periods.currentLabel,
+		comparison.Current.Income,
+		comparison.Current.Expense,
+		periods.previousLabel,
+		comparison.Previous.Income,
+		comparison.Previous.Expense,
+	)
+
+	b.WriteString(headingStyle.Render(fmt.Sprintf(
+		"%-*s %10s %10s %10s %8s",
+		compareNameWidth,
+		"Категория",
+		periods.currentLabel,
+		periods.previousLabel,
+		"Δ",
+		"%",
+	)) + "\n")
+
+	for _, entry := range comparison.Entries {
+		percent := "—"
+		if entry.Percent != nil {
+			percent = fmt.Sprintf("%+.1f%%", *entry.Percent)
+		} else if entry.Current > 0 {
+			percent = "новая"
+		}
+
+		line := fmt.Sprintf(
+			"%-*s %10d %10d %+10d %8s",
+			compareNameWidth,
+			truncate(nameOf(names, entry.CategoryID), compareNameWidth),
+			entry.Current,
+			entry.Previous,
+			entry.Change,
+			percent,
+		)
+
+		switch {
+		case entry.Change > 0 && entry.Type == domain.OperationTypeExpense,
+			entry.Change < 0 && entry.Type == domain.OperationTypeIncome:
+			line = alertStyle.Render(line)
+		case entry.Change != 0:
+			line = okStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,81 @@
+package reports
+
+import (
+	"strings"
+	"time"
+
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+	"kpo-hw-2/internal/tui/screens/operations"
+)
+
+const (
+	fieldReportPeriod = "report_period"
+	fieldReportQuery  = "report_query"
+)
+
+func periodItem(initial query.PeriodKind) menus.MenuItem {
+	options := append([]menus.SelectOption{{Label: "Всё время", Value: ""}}, operations.PeriodPresets()...)
+	initialIndex := 0
+	for idx, option := range options {
+		if option.Value == string(initial) {
+			initialIndex = idx
+		}
+	}
+
+	return menus.NewSelectItem(
+		fieldReportPeriod,
+		"Период",
+		"Относительный период считается от сегодняшней даты.",
+		options,
+		menus.SelectConfig{InitialIndex: initialIndex},
+	)
+}
+
+func queryItem() menus.MenuItem {
+	return menus.NewInputItem(
+		fieldReportQuery,
+		"Запрос",
+		"Дополнительные условия на языке запросов, например: acc:карта -cat:переводы.",
+		menus.InputConfig{
+			Placeholder: "необязательно",
+			Width:       48,
+		},
+	)
+}
+
+func resolveReportFilter(ctx tui.ScreenContext, screen *menus.Screen, values menus.Values) (query.OperationFilter, bool) {
+	filter, ok := parseReportQuery(ctx, screen, values)
+	if !ok {
+		return query.OperationFilter{}, false
+	}
+
+	period := operations.PeriodFromPreset(values[fieldReportPeriod])
+	if period.Kind() == query.PeriodAll {
+		return filter, true
+	}
+
+	if from, to := filter.Period(); from != nil || to != nil {
+		screen.SetFieldError(fieldReportQuery, "период уже выбран выше — уберите date: из запроса")
+		return query.OperationFilter{}, false
+	}
+
+	return filter.WithinPeriod(period, time.Now()), true
+}
+
+func parseReportQuery(ctx tui.ScreenContext, screen *menus.Screen, values menus.Values) (query.OperationFilter, bool) {
+	expression := strings.TrimSpace(values[fieldReportQuery])
+	if expression == "" {
+		screen.SetFieldError(fieldReportQuery, "")
+		return query.NewOperationFilter(), true
+	}
+
+	filter, err := ctx.OperationCommands().ParseQuery(expression).Execute(ctx.Context())
+	if err != nil {
+		screen.SetFieldError(fieldReportQuery, operations.DescribeQueryError(err))
+		return query.OperationFilter{}, false
+	}
+	screen.SetFieldError(fieldReportQuery, "")
+	return filter, true
+}
@@ -0,0 +1,290 @@
+package reports
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const (
+	fieldForecastMonths    = "forecast_months"
+	fieldForecastThreshold = "forecast_threshold"
+	fieldForecastPath      = "forecast_path"
+
+	forecastDateLayout = "02.01.2006"
+	forecastNameWidth  = 18
+)
+
+var (
+	alertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+	okStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+)
+
+func newForecastForm(accounts []*domain.BankAccount, categories []*domain.Category) tui.Screen {
+	var screen *menus.Screen
+
+	names := referenceNames(accounts, categories)
+
+	defaultPath := filepath.Join("storage", "forecast.csv")
+	if abs, err := filepath.Abs(defaultPath); err == nil {
+		defaultPath = abs
+	}
+
+	readOptions := func(values menus.Values) (appanalytics.ForecastOptions, bool) {
+		hasError := false
+
+		months, err := strconv.Atoi(strings.TrimSpace(values[fieldForecastMonths]))
+		if err != nil || months < 1 || months > 24 {
+			screen.SetFieldError(fieldForecastMonths, "укажите число месяцев от 1 до 24")
+			hasError = true
+		} else {
+			screen.SetFieldError(fieldForecastMonths, "")
+		}
+
+		var threshold int64
+		if raw := strings.TrimSpace(values[fieldForecastThreshold]); raw != "" {
+			threshold, err = strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				screen.SetFieldError(fieldForecastThreshold, "порог должен быть целым числом")
+				hasError = true
+			} else {
+				screen.SetFieldError(fieldForecastThreshold, "")
+			}
+		}
+
+		if hasError {
+			return appanalytics.ForecastOptions{}, false
+		}
+
+		return appanalytics.ForecastOptions{
+			Start:     time.Now(),
+			Months:    months,
+			Threshold: threshold,
+		}, true
+	}
+
+	items := []menus.MenuItem{
+		menus.NewInputItem(
+			fieldForecastMonths,
+			"Горизонт, мес.",
+			"На сколько месяцев вперёд строить прогноз.",
+			menus.InputConfig{Initial: "3"},
+		),
+		menus.NewInputItem(
+			fieldForecastThreshold,
+			"Порог остатка",
+			"Дата, когда баланс впервые опустится ниже этой суммы, будет выделена.",
+			menus.InputConfig{Initial: "0"},
+		),
+		menus.NewActionItem(
+			"build",
+			"Построить прогноз",
+			"Регулярные операции и средние прочие расходы за последние 3 месяца.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				options, ok := readOptions(values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				forecast, err := ctx.AnalyticsCommands().Forecast(options).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldForecastMonths, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Прогноз остатков", renderForecast(forecast, names))}
+			},
+		),
+		menus.NewInputItem(
+			fieldForecastPath,
+			"Файл CSV",
+			"Куда сохранить ежедневные прогнозные остатки.",
+			menus.InputConfig{Initial: defaultPath, Width: 48},
+		),
+		menus.NewActionItem(
+			"export",
+			"Сохранить в CSV",
+			"Экспортировать прогноз по дням для каждого счёта.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				options, ok := readOptions(values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				path := strings.TrimSpace(values[fieldForecastPath])
+				if path == "" {
+					screen.SetFieldError(fieldForecastPath, "укажите путь к файлу")
+					return tui.Result{}
+				}
+
+				if _, err := ctx.AnalyticsCommands().ExportForecast(options, path).Execute(ctx.Context()); err != nil {
+					screen.SetFieldError(fieldForecastPath, err.Error())
+					return tui.Result{}
+				}
+				screen.SetFieldError(fieldForecastPath, "")
+
+				return tui.Result{Push: newTextScreen("Экспорт завершён", "Прогноз сохранён в "+path)}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Прогноз остатков",
+		"Укажите горизонт и порог остатка.",
+		items,
+	)
+
+	return screen
+}
+
+func renderForecast(forecast appanalytics.Forecast, names map[domain.ID]string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(
+		&b,
+		"Период: %s — %s • порог: %d\n",
+		forecast.Start.Format(forecastDateLayout),
+		forecast.End.AddDate(0, 0, -1).Format(forecastDateLayout),
+		forecast.Threshold,
+	)
+
+	if forecast.FirstBelow != nil {
+		b.WriteString(alertStyle.Render(fmt.Sprintf(
+			"Счёт «%s» опустится ниже порога %s: %d",
+			nameOf(names, forecast.FirstBelowID),
+			forecast.FirstBelow.Date.Format(forecastDateLayout),
+			forecast.FirstBelow.Balance,
+		)))
+	} else {
+		b.WriteString(okStyle.Render("Ни один счёт не опустится ниже порога."))
+	}
+	b.WriteString("\n")
+
+	if len(forecast.Accounts) == 0 {
+		b.WriteString("\nСчета ещё не добавлены.")
+		return b.String()
+	}
+
+	b.WriteString("\n" + headingStyle.Render("Счета") + "\n")
+	for _, account := range forecast.Accounts {
+		final := account.Current
+		if len(account.Points) > 0 {
+			final = account.Points[len(account.Points)-1].Balance
+		}
+
+		line := fmt.Sprintf(
+			"%-*s сейчас %10d • в конце %10d • минимум %10d (%s)",
+			forecastNameWidth,
+			truncate(account.AccountName, forecastNameWidth),
+			account.Current,
+			final,
+			account.Lowest.Balance,
+			account.Lowest.Date.Format(forecastDateLayout),
+		)
+		if account.FirstBelow != nil {
+			line = alertStyle.Render(line + " • ниже порога с " + account.FirstBelow.Date.Format(forecastDateLayout))
+		}
+		b.WriteString(line + "\n")
+		b.WriteString(strings.Repeat(" ", forecastNameWidth+1) + monthlyCheckpoints(account.Points) + "\n")
+	}
+
+	if len(forecast.Recurring) > 0 {
+		b.WriteString("\n" + headingStyle.Render("Регулярные операции") + "\n")
+		for _, pattern := range forecast.Recurring {
+			sign := "+"
+			if pattern.Type == domain.OperationTypeExpense {
+				sign = "-"
+			}
+			title := pattern.Description
+			if title == "" {
+				title = nameOf(names, pattern.CategoryID)
+			}
+			fmt.Fprintf(
+				&b,
+				"%-*s %s%d %s • следующая %s • %s\n",
+				forecastNameWidth,
+				truncate(title, forecastNameWidth),
+				sign,
+				pattern.Amount,
+				cadenceLabel(pattern.Cadence),
+				pattern.Next.Format(forecastDateLayout),
+				nameOf(names, pattern.AccountID),
+			)
+		}
+	}
+
+	if len(forecast.Discretionary) > 0 {
+		b.WriteString("\n" + headingStyle.Render("Прочие расходы в месяц (в среднем)") + "\n")
+		for _, rate := range forecast.Discretionary {
+			if rate.Monthly == 0 {
+				continue
+			}
+			fmt.Fprintf(
+				&b,
+				"%-*s %10d • %s\n",
+				forecastNameWidth,
+				truncate(nameOf(names, rate.CategoryID), forecastNameWidth),
+				rate.Monthly,
+				nameOf(names, rate.AccountID),
+			)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func monthlyCheckpoints(points []appanalytics.ForecastPoint) string {
+	var parts []string
+	for i, point := range points {
+		last := i == len(points)-1
+		if last || points[i+1].Date.Month() != point.Date.Month() {
+			parts = append(parts, fmt.Sprintf("%s: %d", point.Date.Format("01.2006"), point.Balance))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func cadenceLabel(cadence appanalytics.Cadence) string {
+	switch cadence {
+	case appanalytics.CadenceWeekly:
+		return "еженедельно"
+	case appanalytics.CadenceBiweekly:
+		return "раз в две недели"
+	case appanalytics.CadenceMonthly:
+		return "ежемесячно"
+	case appanalytics.CadenceQuarterly:
+		return "ежеквартально"
+	case appanalytics.CadenceYearly:
+		return "ежегодно"
+	default:
+		return string(cadence)
+	}
+}
+
+func referenceNames(accounts []*domain.BankAccount, categories []*domain.Category) map[domain.ID]string {
+	names := make(map[domain.ID]string, len(accounts)+len(categories))
+	for _, account := range accounts {
+		names[account.ID()] = account.Name()
+	}
+	for _, category := range categories {
+		names[category.ID()] = category.Name()
+	}
+	return names
+}
+
+func nameOf(names map[domain.ID]string, id domain.ID) string {
+	if name := names[id]; name != "" {
+		return name
+	}
+	return id.String()
+}
@@ -0,0 +1,125 @@
+package reports
+
+import (
+	"fmt"
+
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+func NewMenu() tui.Screen {
+	items := []menus.MenuItem{
+		menus.NewActionItem(
+			"breakdown",
+			"Структура доходов и расходов",
+			"Суммы, доли и количество операций по категориям или счетам.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				accounts, categories, err := loadReferences(ctx)
+				if err != nil {
+					return tui.Result{Push: newTextScreen("Ошибка", err.Error())}
+				}
+				return tui.Result{Push: newBreakdownForm(accounts, categories)}
+			},
+		),
+		menus.NewActionItem(
+			"series",
+			"Динамика доходов и расходов",
+			"График по дням, неделям, месяцам или годам.",
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Push: newSeriesForm()}
+			},
+		),
+		menus.NewActionItem(
+			"forecast",
+			"Прогноз остатков",
+			"Прогноз баланса счетов на ближайшие месяцы.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				accounts, categories, err := loadReferences(ctx)
+				if err != nil {
+					return tui.Result{Push: newTextScreen("Ошибка", err.Error())}
+				}
+				return tui.Result{Push: newForecastForm(accounts, categories)}
+			},
+		),
+		menus.NewActionItem(
+			"anomalies",
+			"Аномалии",
+			"Необычно крупные расходы и месяцы с перерасходом по категориям.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				accounts, categories, err := loadReferences(ctx)
+				if err != nil {
+					return tui.Result{Push: newTextScreen("Ошибка", err.Error())}
+				}
+				return tui.Result{Push: newAnomaliesForm(accounts, categories)}
+			},
+		),
+		menus.NewActionItem(
+			"comparison",
+			"Сравнение периодов",
+			"Изменения по категориям относительно прошлого месяца или года.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				accounts, categories, err := loadReferences(ctx)
+				if err != nil {
+					return tui.Result{Push: newTextScreen("Ошибка", err.Error())}
+				}
+				return tui.Result{Push: newComparisonForm(accounts, categories)}
+			},
+		),
+		menus.NewActionItem(
+			"subscriptions",
+			"Подписки",
+			"Регулярные платежи: периодичность, сумма и стоимость в год.",
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				return openSubscriptions(ctx)
+			},
+		),
+		menus.NewActionItem(
+			"net_worth",
+			"Чистые активы",
+			"Активы минус кредиты по месяцам, итоги по видам и группам счетов.",
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Push: newNetWorthForm()}
+			},
+		),
+		menus.NewActionItem(
+			"income_statement",
+			"Отчёт о доходах и расходах",
+			"Категории доходов и расходов с промежуточными итогами за период.",
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Push: newIncomeStatementForm()}
+			},
+		),
+		menus.NewActionItem(
+			"balance_sheet",
+			"Баланс",
+			"Остатки счетов на дату: активы, кредиты и чистые активы.",
+			func(tui.ScreenContext, menus.Values) tui.Result {
+				return tui.Result{Push: newBalanceSheetForm()}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться в главное меню"),
+	}
+
+	return menus.NewScreen(
+		"Отчёты",
+		"Выберите отчёт.",
+		items,
+	)
+}
+
+func loadReferences(ctx tui.ScreenContext) ([]*domain.BankAccount, []*domain.Category, error) {
+	accountCmd := ctx.AccountCommands().List()
+	accounts, err := accountCmd.Execute(ctx.Context())
+	if err != nil {
+		return nil, nil, fmt.Errorf("Не удалось получить список счетов:\n%s", err.Error())
+	}
+
+	categoryCmd := ctx.CategoryCommands().List("")
+	categories, err := categoryCmd.Execute(ctx.Context())
+	if err != nil {
+		return nil, nil, fmt.Errorf("Не удалось получить список категорий:\n%s", err.Error())
+	}
+
+	return accounts, categories, nil
+}
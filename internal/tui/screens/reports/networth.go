@@ -0,0 +1,186 @@
+package reports
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const (
+	fieldNetWorthMonths = "net_worth_months"
+	fieldNetWorthPath   = "net_worth_path"
+
+	netWorthNameWidth = 18
+)
+
+func newNetWorthForm() tui.Screen {
+	var screen *menus.Screen
+
+	defaultPath := filepath.Join("storage", "net_worth.csv")
+	if abs, err := filepath.Abs(defaultPath); err == nil {
+		defaultPath = abs
+	}
+
+	readOptions := func(values menus.Values) (appanalytics.NetWorthOptions, bool) {
+		months, err := strconv.Atoi(strings.TrimSpace(values[fieldNetWorthMonths]))
+		if err != nil || months < 1 || months > 120 {
+			screen.SetFieldError(fieldNetWorthMonths, "укажите число месяцев от 1 до 120")
+			return appanalytics.NetWorthOptions{}, false
+		}
+		screen.SetFieldError(fieldNetWorthMonths, "")
+
+		return appanalytics.NetWorthOptions{End: time.Now(), Months: months}, true
+	}
+
+	items := []menus.MenuItem{
+		menus.NewInputItem(
+			fieldNetWorthMonths,
+			"Период, мес.",
+			"За сколько последних месяцев показать динамику.",
+			menus.InputConfig{Initial: "12"},
+		),
+		menus.NewActionItem(
+			"build",
+			"Показать",
+			"Активы, кредиты и чистые активы на конец каждого месяца.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				options, ok := readOptions(values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				netWorth, err := ctx.AnalyticsCommands().NetWorth(options).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldNetWorthMonths, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Чистые активы", renderNetWorth(netWorth))}
+			},
+		),
+		menus.NewInputItem(
+			fieldNetWorthPath,
+			"Файл CSV",
+			"Куда сохранить помесячную динамику.",
+			menus.InputConfig{Initial: defaultPath, Width: 48},
+		),
+		menus.NewActionItem(
+			"export",
+			"Сохранить в CSV",
+			"Экспортировать строки month,assets,credit,net.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				options, ok := readOptions(values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				path := strings.TrimSpace(values[fieldNetWorthPath])
+				if path == "" {
+					screen.SetFieldError(fieldNetWorthPath, "укажите путь к файлу")
+					return tui.Result{}
+				}
+
+				if _, err := ctx.AnalyticsCommands().ExportNetWorth(options, path).Execute(ctx.Context()); err != nil {
+					screen.SetFieldError(fieldNetWorthPath, err.Error())
+					return tui.Result{}
+				}
+				screen.SetFieldError(fieldNetWorthPath, "")
+
+				return tui.Result{Push: newTextScreen("Экспорт завершён", "Динамика сохранена в "+path)}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Чистые активы",
+		"Активы минус задолженность по кредитным счетам. Вид и группа задаются в карточке счёта.",
+		items,
+	)
+
+	return screen
+}
+
+func renderNetWorth(netWorth appanalytics.NetWorth) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Активы: %d • кредиты: %d • чистые активы: %d\n", netWorth.Assets, netWorth.Credit, netWorth.Net)
+
+	b.WriteString("\n" + headingStyle.Render("По виду счёта") + "\n")
+	for _, total := range netWorth.ByKind {
+		writeGroupTotal(&b, accountKindLabel(domain.AccountKind(total.Key)), total)
+	}
+
+	b.WriteString("\n" + headingStyle.Render("По группам") + "\n")
+	for _, total := range netWorth.ByGroup {
+		name := total.Key
+		if name == "" {
+			name = "Без группы"
+		}
+		writeGroupTotal(&b, name, total)
+	}
+
+	if len(netWorth.Points) > 0 {
+		var peak int64
+		for _, point := range netWorth.Points {
+			peak = max(peak, abs64(point.Net))
+		}
+
+		b.WriteString("\n" + headingStyle.Render("На конец месяца") + "\n")
+		for _, point := range netWorth.Points {
+			ratio := 0.0
+			if peak > 0 {
+				ratio = float64(abs64(point.Net)) / float64(peak)
+			}
+			style := incomeBarStyle
+			if point.Net < 0 {
+				style = expenseBarStyle
+			}
+			fmt.Fprintf(
+				&b,
+				"%s %s %12d  (активы %d, кредиты %d)\n",
+				point.Date.Format("01.2006"),
+				renderBar(ratio, barWidth, style),
+				point.Net,
+				point.Assets,
+				point.Credit,
+			)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeGroupTotal(b *strings.Builder, name string, total appanalytics.AccountGroupTotal) {
+	fmt.Fprintf(
+		b,
+		"%-*s %12d • счетов: %d\n",
+		netWorthNameWidth,
+		truncate(name, netWorthNameWidth),
+		total.Net,
+		total.Accounts,
+	)
+}
+
+func accountKindLabel(kind domain.AccountKind) string {
+	switch kind {
+	case domain.AccountKindCredit:
+		return "Кредитный"
+	default:
+		return "Актив"
+	}
+}
+
+func abs64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
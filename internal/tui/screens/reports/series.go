@@ -0,0 +1,270 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+	"kpo-hw-2/internal/tui/styles"
+)
+
+const (
+	fieldSeriesGranularity = "series_granularity"
+	fieldSeriesWeekStart   = "series_week_start"
+	fieldSeriesTimezone    = "series_timezone"
+
+	seriesWindow     = 12
+	seriesBarWidth   = 24
+	sparklineWidth   = 60
+	seriesLabelWidth = 12
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+func newSeriesForm() tui.Screen {
+	var screen *menus.Screen
+
+	items := []menus.MenuItem{
+		periodItem(query.PeriodThisYear),
+		menus.NewSelectItem(
+			fieldSeriesGranularity,
+			"Шаг",
+			"Размер одного столбца графика.",
+			[]menus.SelectOption{
+				{Label: "День", Value: string(appanalytics.GranularityDay)},
+				{Label: "Неделя", Value: string(appanalytics.GranularityWeek)},
+				{Label: "Месяц", Value: string(appanalytics.GranularityMonth)},
+				{Label: "Год", Value: string(appanalytics.GranularityYear)},
+			},
+			menus.SelectConfig{InitialIndex: 2},
+		),
+		menus.NewSelectItem(
+			fieldSeriesWeekStart,
+			"Начало недели",
+			"Используется при шаге «Неделя».",
+			[]menus.SelectOption{
+				{Label: "Понедельник", Value: "1"},
+				{Label: "Воскресенье", Value: "0"},
+			},
+			menus.SelectConfig{InitialIndex: 0},
+		),
+		menus.NewInputItem(
+			fieldSeriesTimezone,
+			"Часовой пояс",
+			"Название зоны IANA, например Europe/Moscow. Пусто — системный пояс.",
+			menus.InputConfig{
+				Placeholder: "Local",
+			},
+		),
+		queryItem(),
+		menus.NewActionItem(
+			"build",
+			"Построить график",
+			"Сгруппировать операции по периодам.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				location := time.Local
+				if name := strings.TrimSpace(values[fieldSeriesTimezone]); name != "" {
+					loaded, err := time.LoadLocation(name)
+					if err != nil {
+						screen.SetFieldError(fieldSeriesTimezone, "неизвестный часовой пояс")
+						return tui.Result{}
+					}
+					location = loaded
+				}
+				screen.SetFieldError(fieldSeriesTimezone, "")
+
+				filter, ok := resolveReportFilter(ctx, screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				weekStart := time.Monday
+				if values[fieldSeriesWeekStart] == "0" {
+					weekStart = time.Sunday
+				}
+
+				granularity := appanalytics.Granularity(values[fieldSeriesGranularity])
+				buckets, err := ctx.AnalyticsCommands().Series(filter, appanalytics.SeriesOptions{
+					Granularity: granularity,
+					WeekStart:   weekStart,
+					Location:    location,
+				}).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldReportQuery, err.Error())
+					return tui.Result{}
+				}
+
+				if len(buckets) == 0 {
+					return tui.Result{Push: newTextScreen("Динамика", "Операции за выбранный период не найдены.")}
+				}
+
+				return tui.Result{Push: newSeriesScreen(granularity, buckets)}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Динамика доходов и расходов",
+		"Выберите период, шаг и часовой пояс.",
+		items,
+	)
+
+	return screen
+}
+
+type seriesScreen struct {
+	granularity appanalytics.Granularity
+	buckets     []appanalytics.Bucket
+	cursor      int
+	offset      int
+	maxValue    int64
+}
+
+func newSeriesScreen(granularity appanalytics.Granularity, buckets []appanalytics.Bucket) tui.Screen {
+	screen := &seriesScreen{
+		granularity: granularity,
+		buckets:     buckets,
+	}
+	for _, bucket := range buckets {
+		screen.maxValue = max(screen.maxValue, bucket.Income, bucket.Expense)
+	}
+	screen.moveTo(len(buckets) - 1)
+	return screen
+}
+
+func (s *seriesScreen) Name() string { return "Динамика доходов и расходов" }
+
+func (s *seriesScreen) Init(tui.ScreenContext) tea.Cmd { return nil }
+
+func (s *seriesScreen) Update(msg tea.Msg, _ tui.ScreenContext) tui.Result {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return tui.Result{}
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		return tui.Result{Pop: true}
+	case "left", "up", "h", "k":
+		s.moveTo(s.cursor - 1)
+	case "right", "down", "l", "j":
+		s.moveTo(s.cursor + 1)
+	case "pgup", "[":
+		s.moveTo(s.cursor - seriesWindow)
+	case "pgdown", "]":
+		s.moveTo(s.cursor + seriesWindow)
+	case "home":
+		s.moveTo(0)
+	case "end":
+		s.moveTo(len(s.buckets) - 1)
+	}
+	return tui.Result{}
+}
+
+func (s *seriesScreen) moveTo(index int) {
+	s.cursor = min(max(index, 0), len(s.buckets)-1)
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+seriesWindow {
+		s.offset = s.cursor - seriesWindow + 1
+	}
+}
+
+func (s *seriesScreen) View() string {
+	var b strings.Builder
+
+	first, last := s.buckets[0], s.buckets[len(s.buckets)-1]
+	fmt.Fprintf(&b, "%s — %s • периодов: %d\n", s.label(first), s.label(last), len(s.buckets))
+	b.WriteString(styles.Description("Чистый результат: ") + s.sparkline() + "\n\n")
+
+	end := min(s.offset+seriesWindow, len(s.buckets))
+	for i := s.offset; i < end; i++ {
+		bucket := s.buckets[i]
+		fmt.Fprintf(
+			&b,
+			"%s%-*s %s %10d\n%s%-*s %s %10d\n",
+			styles.CursorPrefix(i == s.cursor),
+			seriesLabelWidth,
+			s.label(bucket),
+			renderBar(s.ratio(bucket.Income), seriesBarWidth, incomeBarStyle),
+			bucket.Income,
+			"  ",
+			seriesLabelWidth,
+			"",
+			renderBar(s.ratio(bucket.Expense), seriesBarWidth, expenseBarStyle),
+			bucket.Expense,
+		)
+	}
+
+	selected := s.buckets[s.cursor]
+	fmt.Fprintf(
+		&b,
+		"\n%s\nДоходы: %d • Расходы: %d • Итого: %+d • Операций: %d\n\n",
+		headingStyle.Render(s.label(selected)),
+		selected.Income,
+		selected.Expense,
+		selected.Net,
+		selected.Count,
+	)
+	b.WriteString(styles.Description("←/→ — период, PgUp/PgDn — страница, Esc — назад"))
+
+	return b.String()
+}
+
+func (s *seriesScreen) ratio(value int64) float64 {
+	if s.maxValue == 0 {
+		return 0
+	}
+	return float64(value) / float64(s.maxValue)
+}
+
+func (s *seriesScreen) label(bucket appanalytics.Bucket) string {
+	switch s.granularity {
+	case appanalytics.GranularityWeek:
+		return bucket.Start.Format("02.01") + "–" + bucket.End.Format("02.01.06")
+	case appanalytics.GranularityMonth:
+		return bucket.Start.Format("01.2006")
+	case appanalytics.GranularityYear:
+		return bucket.Start.Format("2006")
+	default:
+		return bucket.Start.Format("02.01.2006")
+	}
+}
+
+func (s *seriesScreen) sparkline() string {
+	start := max(0, min(s.cursor-sparklineWidth/2, len(s.buckets)-sparklineWidth))
+	end := min(start+sparklineWidth, len(s.buckets))
+
+	low, high := s.buckets[start].Net, s.buckets[start].Net
+	for _, bucket := range s.buckets[start:end] {
+		low = min(low, bucket.Net)
+		high = max(high, bucket.Net)
+	}
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		level := len(sparkLevels) / 2
+		if high > low {
+			level = int(float64(s.buckets[i].Net-low) / float64(high-low) * float64(len(sparkLevels)-1))
+		}
+		style := incomeBarStyle
+		if s.buckets[i].Net < 0 {
+			style = expenseBarStyle
+		}
+		if i == s.cursor {
+			style = style.Reverse(true)
+		}
+		b.WriteString(style.Render(string(sparkLevels[level])))
+	}
+	return b.String()
+}
+
+var _ tui.Screen = (*seriesScreen)(nil)
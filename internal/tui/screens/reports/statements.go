@@ -0,0 +1,213 @@
+package reports
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain/query"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+const (
+	fieldStatementDate   = "statement_date"
+	fieldStatementFormat = "statement_format"
+	fieldStatementPath   = "statement_path"
+
+	statementDateLayout = "2006-01-02"
+)
+
+func newIncomeStatementForm() tui.Screen {
+	var screen *menus.Screen
+
+	items := []menus.MenuItem{
+		periodItem(query.PeriodThisYear),
+		queryItem(),
+		menus.NewActionItem(
+			"build",
+			"Показать",
+			"Доходы и расходы по категориям с промежуточными итогами.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				filter, ok := resolveReportFilter(ctx, screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				statement, err := ctx.AnalyticsCommands().IncomeStatement(filter).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldReportQuery, err.Error())
+					return tui.Result{}
+				}
+
+				table := statement.Table()
+				return tui.Result{Push: newTextScreen(table.Title, renderTable(table))}
+			},
+		),
+		reportFormatItem(),
+		reportPathItem("income_statement"),
+		menus.NewActionItem(
+			"export",
+			"Сохранить",
+			"Экспортировать отчёт в выбранном формате.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				filter, ok := resolveReportFilter(ctx, screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				format, path, ok := readReportTarget(screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				exportCmd := ctx.AnalyticsCommands().ExportIncomeStatement(filter, format, path)
+				if _, err := exportCmd.Execute(ctx.Context()); err != nil {
+					screen.SetFieldError(fieldStatementPath, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Экспорт завершён", "Отчёт сохранён в "+path)}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Отчёт о доходах и расходах",
+		"Выберите период и при необходимости уточните запрос.",
+		items,
+	)
+
+	return screen
+}
+
+func newBalanceSheetForm() tui.Screen {
+	var screen *menus.Screen
+
+	readDate := func(values menus.Values) (time.Time, bool) {
+		raw := strings.TrimSpace(values[fieldStatementDate])
+		day, err := time.ParseInLocation(statementDateLayout, raw, time.Local)
+		if err != nil {
+			screen.SetFieldError(fieldStatementDate, "используйте формат ГГГГ-ММ-ДД")
+			return time.Time{}, false
+		}
+		screen.SetFieldError(fieldStatementDate, "")
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+	}
+
+	items := []menus.MenuItem{
+		menus.NewInputItem(
+			fieldStatementDate,
+			"Дата",
+			"Остатки считаются на конец указанного дня.",
+			menus.InputConfig{
+				Initial:     time.Now().Format(statementDateLayout),
+				Placeholder: "ГГГГ-ММ-ДД",
+			},
+		),
+		menus.NewActionItem(
+			"build",
+			"Показать",
+			"Остатки счетов по видам и группам.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				at, ok := readDate(values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				sheet, err := ctx.AnalyticsCommands().BalanceSheet(at).Execute(ctx.Context())
+				if err != nil {
+					screen.SetFieldError(fieldStatementDate, err.Error())
+					return tui.Result{}
+				}
+
+				table := sheet.Table()
+				return tui.Result{Push: newTextScreen(table.Title, renderTable(table))}
+			},
+		),
+		reportFormatItem(),
+		reportPathItem("balance_sheet"),
+		menus.NewActionItem(
+			"export",
+			"Сохранить",
+			"Экспортировать баланс в выбранном формате.",
+			func(ctx tui.ScreenContext, values menus.Values) tui.Result {
+				at, ok := readDate(values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				format, path, ok := readReportTarget(screen, values)
+				if !ok {
+					return tui.Result{}
+				}
+
+				exportCmd := ctx.AnalyticsCommands().ExportBalanceSheet(at, format, path)
+				if _, err := exportCmd.Execute(ctx.Context()); err != nil {
+					screen.SetFieldError(fieldStatementPath, err.Error())
+					return tui.Result{}
+				}
+
+				return tui.Result{Push: newTextScreen("Экспорт завершён", "Баланс сохранён в "+path)}
+			},
+		),
+		menus.NewPopItem("Назад", "Вернуться к отчётам"),
+	}
+
+	screen = menus.NewScreen(
+		"Баланс",
+		"Остатки всех счетов на выбранную дату.",
+		items,
+	)
+
+	return screen
+}
+
+func reportFormatItem() menus.MenuItem {
+	return menus.NewSelectItem(
+		fieldStatementFormat,
+		"Формат",
+		"",
+		[]menus.SelectOption{
+			{Label: "CSV", Value: string(appanalytics.ReportFormatCSV)},
+			{Label: "Markdown", Value: string(appanalytics.ReportFormatMarkdown)},
+		},
+		menus.SelectConfig{InitialIndex: 0},
+	)
+}
+
+func reportPathItem(name string) menus.MenuItem {
+	defaultPath := filepath.Join("storage", name)
+	if abs, err := filepath.Abs(defaultPath); err == nil {
+		defaultPath = abs
+	}
+
+	return menus.NewInputItem(
+		fieldStatementPath,
+		"Файл",
+		"Расширение .csv или .md добавляется, если не указано.",
+		menus.InputConfig{Initial: defaultPath, Width: 48},
+	)
+}
+
+func readReportTarget(screen *menus.Screen, values menus.Values) (appanalytics.ReportFormat, string, bool) {
+	path := strings.TrimSpace(values[fieldStatementPath])
+	if path == "" {
+		screen.SetFieldError(fieldStatementPath, "укажите путь к файлу")
+		return "", "", false
+	}
+	screen.SetFieldError(fieldStatementPath, "")
+
+	format := appanalytics.ReportFormat(values[fieldStatementFormat])
+	if filepath.Ext(path) == "" {
+		if format == appanalytics.ReportFormatMarkdown {
+			path += ".md"
+		} else {
+			path += ".csv"
+		}
+	}
+
+	return format, path, true
+}
@@ -0,0 +1,97 @@
+package reports
+
+import (
+	"fmt"
+	"time"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+	"kpo-hw-2/internal/domain"
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/menus"
+)
+
+func openSubscriptions(ctx tui.ScreenContext) tui.Result {
+	accounts, categories, err := loadReferences(ctx)
+	if err != nil {
+		return tui.Result{Push: newTextScreen("Ошибка", err.Error())}
+	}
+
+	subscriptions, err := ctx.AnalyticsCommands().Subscriptions(time.Now()).Execute(ctx.Context())
+	if err != nil {
+		return tui.Result{Push: newTextScreen("Ошибка", fmt.Sprintf("Не удалось найти подписки:\n%s", err.Error()))}
+	}
+
+	return tui.Result{Push: newSubscriptionsScreen(subscriptions, referenceNames(accounts, categories))}
+}
+
+func newSubscriptionsScreen(subscriptions []appanalytics.Subscription, names map[domain.ID]string) tui.Screen {
+	var total int64
+	active := 0
+
+	items := make([]menus.MenuItem, 0, len(subscriptions)+1)
+	for _, subscription := range subscriptions {
+		subscription := subscription
+		if !subscription.Ignored {
+			total += subscription.AnnualCost
+			active++
+		}
+
+		items = append(items, menus.NewActionItem(
+			subscription.Key,
+			subscriptionTitle(subscription, names),
+			describeSubscription(subscription, names),
+			func(ctx tui.ScreenContext, _ menus.Values) tui.Result {
+				toggle := ctx.AnalyticsCommands().IgnoreSubscription(subscription.Key)
+				if subscription.Ignored {
+					toggle = ctx.AnalyticsCommands().RestoreSubscription(subscription.Key)
+				}
+				if _, err := toggle.Execute(ctx.Context()); err != nil {
+					return tui.Result{Push: newTextScreen("Ошибка", fmt.Sprintf("Не удалось изменить подписку:\n%s", err.Error()))}
+				}
+
+				refreshed, err := ctx.AnalyticsCommands().Subscriptions(time.Now()).Execute(ctx.Context())
+				if err != nil {
+					return tui.Result{Push: newTextScreen("Ошибка", fmt.Sprintf("Не удалось найти подписки:\n%s", err.Error()))}
+				}
+				return tui.Result{Replace: newSubscriptionsScreen(refreshed, names)}
+			},
+		))
+	}
+
+	items = append(items, menus.NewPopItem("Назад", "Вернуться к отчётам"))
+
+	return menus.NewScreen(
+		"Подписки",
+		fmt.Sprintf(
+			"Активных подписок: %d • в год: %d • в месяц: %d\nEnter — скрыть подписку или вернуть скрытую.",
+			active,
+			total,
+			total/12,
+		),
+		items,
+	).WithEmptyMessage("Регулярные расходы не найдены.")
+}
+
+func subscriptionTitle(subscription appanalytics.Subscription, names map[domain.ID]string) string {
+	title := subscription.Description
+	if title == "" {
+		title = nameOf(names, subscription.CategoryID)
+	}
+
+	title = fmt.Sprintf("%s — %d %s", title, subscription.Amount, cadenceLabel(subscription.Cadence))
+	if subscription.Ignored {
+		title = "[скрыта] " + title
+	}
+	return title
+}
+
+func describeSubscription(subscription appanalytics.Subscription, names map[domain.ID]string) string {
+	return fmt.Sprintf(
+		"Последняя %s • следующая %s • в год %d • %s / %s",
+		subscription.Last.Format(forecastDateLayout),
+		subscription.Next.Format(forecastDateLayout),
+		subscription.AnnualCost,
+		nameOf(names, subscription.CategoryID),
+		nameOf(names, subscription.AccountID),
+	)
+}
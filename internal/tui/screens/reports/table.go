@@ -0,0 +1,59 @@
+package reports
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	appanalytics "kpo-hw-2/internal/application/analytics"
+)
+
+func renderTable(table appanalytics.ReportTable) string {
+	widths := make([]int, len(table.Columns))
+	for idx, column := range table.Columns {
+		widths[idx] = lipgloss.Width(column)
+	}
+	for _, row := range table.Rows {
+		for idx, cell := range row.Cells {
+			if idx < len(widths) {
+				widths[idx] = max(widths[idx], lipgloss.Width(cell))
+			}
+		}
+	}
+
+	formatRow := func(cells []string) string {
+		parts := make([]string, len(widths))
+		for idx := range widths {
+			cell := ""
+			if idx < len(cells) {
+				cell = cells[idx]
+			}
+			padding := strings.Repeat(" ", widths[idx]-lipgloss.Width(cell))
+			if idx < len(table.Numeric) && table.Numeric[idx] {
+				parts[idx] = padding + cell
+			} else {
+				parts[idx] = cell + padding
+			}
+		}
+		return strings.TrimRight(strings.Join(parts, "  "), " ")
+	}
+
+	var b strings.Builder
+	b.WriteString(headingStyle.Render(formatRow(table.Columns)) + "\n")
+
+	separators := make([]string, len(widths))
+	for idx, width := range widths {
+		separators[idx] = strings.Repeat("─", width)
+	}
+	b.WriteString(emptyBarStyle.Render(strings.Join(separators, "  ")) + "\n")
+
+	for _, row := range table.Rows {
+		line := formatRow(row.Cells)
+		if row.Total {
+			line = lipgloss.NewStyle().Bold(true).Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
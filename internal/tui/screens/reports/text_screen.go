@@ -0,0 +1,37 @@
+package reports
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kpo-hw-2/internal/tui"
+	"kpo-hw-2/internal/tui/styles"
+)
+
+type textScreen struct {
+	title string
+	body  string
+}
+
+func newTextScreen(title, body string) tui.Screen {
+	return &textScreen{title: title, body: body}
+}
+
+func (s *textScreen) Name() string { return s.title }
+
+func (s *textScreen) Init(tui.ScreenContext) tea.Cmd { return nil }
+
+func (s *textScreen) Update(msg tea.Msg, _ tui.ScreenContext) tui.Result {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "enter", "q":
+			return tui.Result{Pop: true}
+		}
+	}
+	return tui.Result{}
+}
+
+func (s *textScreen) View() string {
+	return s.body + "\n\n" + styles.Description("Esc — назад")
+}
+
+var _ tui.Screen = (*textScreen)(nil)